@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -14,9 +15,15 @@ import (
 	"github.com/unifocus/backend/internal/api/handlers"
 	"github.com/unifocus/backend/internal/api/middleware"
 	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/mail"
+	"github.com/unifocus/backend/internal/nlp"
+	"github.com/unifocus/backend/internal/notify"
+	"github.com/unifocus/backend/internal/ratelimit"
 	"github.com/unifocus/backend/internal/repository/postgres"
 	"github.com/unifocus/backend/internal/repository/redis"
 	"github.com/unifocus/backend/internal/service"
+	"github.com/unifocus/backend/internal/storage"
 	"github.com/unifocus/backend/pkg/jwt"
 	"github.com/unifocus/backend/pkg/logger"
 )
@@ -66,13 +73,106 @@ func main() {
 	userRepo := postgres.NewUserRepository(db)
 	oppRepo := postgres.NewOpportunityRepository(db)
 	profileRepo := postgres.NewProfileRepository(db)
+	scheduleRepo := postgres.NewScheduleRepository(db)
+	userOppRepo := postgres.NewUserOpportunityRepository(db)
+	ruleRepo := postgres.NewCompetitionRuleRepository(db)
+	taxonomyRepo := postgres.NewTaxonomyRepository(db)
+	semesterRepo := postgres.NewSemesterRepository(db)
+	calendarFeedRepo := postgres.NewCalendarFeedRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	reminderRepo := postgres.NewReminderRepository(db)
+	digestRepo := postgres.NewDigestRepository(db)
+	heldNotificationRepo := postgres.NewHeldNotificationRepository(db)
+	sessionRepo := postgres.NewSessionRepository(db)
+	accountTokenRepo := postgres.NewAccountTokenRepository(db)
+	identityRepo := postgres.NewUserIdentityRepository(db)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db)
+	deletionRepo := postgres.NewAccountDeletionRepository(db)
+	auditRepo := postgres.NewAuditLogRepository(db)
 	jwtMgr := jwt.NewManager(&cfg.JWT)
-	authService := service.NewAuthService(userRepo, jwtMgr)
-	oppService := service.NewOpportunityService(oppRepo)
-	profileService := service.NewProfileService(profileRepo, nil) // NLP客户端待集成
+
+	// 加载技能/专业分类体系（失败时不做规范化，继续启动）
+	taxonomyService := service.NewTaxonomyService(taxonomyRepo, rdb)
+	if err := taxonomyService.Load(context.Background()); err != nil {
+		logger.Errorf("Failed to load taxonomy: %v", err)
+	}
+
+	// 初始化通知渠道（后台任务随服务关闭停止）
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	broker := notify.NewBroker(rdb)
+	go broker.Run(workerCtx)
+	// 其他实例修改分类体系后重建本地索引
+	go taxonomyService.Start(workerCtx)
+	inAppNotifier := notify.NewInAppNotifier(notificationRepo, broker)
+	mailSender := mail.NewSender(cfg.Mail)
+	notifiers := notify.New(cfg, mailSender, inAppNotifier)
+	// 按用户偏好分发推送，免打扰期间站内通知静默入库，其他渠道暂存、结束后合并补发
+	dispatcher := notify.NewDispatcher(cfg, notifiers, profileRepo, heldNotificationRepo, userRepo, rdb)
+	go dispatcher.Start(workerCtx)
+	notificationService := service.NewNotificationService(notificationRepo, userOppRepo, profileRepo, userRepo, dispatcher, broker)
+
+	authService := service.NewAuthService(cfg.JWT, cfg.Auth, userRepo, sessionRepo, apiKeyRepo, jwtMgr, rdb, taxonomyService)
+	accountService := service.NewAccountService(cfg.Auth, userRepo, accountTokenRepo, authService, mailSender)
+	ssoService := service.NewSSOService(cfg.Auth, userRepo, identityRepo, authService, rdb, taxonomyService)
+	// NLP服务（简历解析与向量化）；未配置地址时关闭，语义检索返回向量不可用
+	var nlpClient service.NLPClient
+	if client := nlp.New(cfg.NLPService); client != nil {
+		nlpClient = client
+	} else {
+		logger.Warn("NLP service URL not configured, resume parsing and vector search are disabled")
+	}
+
+	oppService := service.NewOpportunityService(oppRepo, nlpClient, taxonomyService, notificationService)
+	scoringService := service.NewScoringService(cfg.Scoring, userRepo, profileRepo, oppRepo, scheduleRepo, semesterRepo, userOppRepo, taxonomyService)
+	similarityService := service.NewSimilarityService(cfg.NLPService, db, oppRepo, profileRepo, nlpClient, rdb)
+	if nlpClient != nil {
+		// 补齐缺失的机会与简历向量（包括NLP服务不可用期间写入的数据）
+		go similarityService.Start(workerCtx)
+	}
+	recService := service.NewRecommendationService(cfg.Recommendation, scoringService, userRepo, profileRepo, oppRepo, scheduleRepo, semesterRepo, userOppRepo, rdb)
+	profileService := service.NewProfileService(profileRepo, nlpClient, taxonomyService, recService)
+	gapService := service.NewGapService(userRepo, profileRepo, oppRepo, ruleRepo, taxonomyService)
+	userOppService := service.NewUserOpportunityService(userOppRepo, recService)
+	scheduleService := service.NewScheduleService(cfg.Timetable, cfg.Calendar.GetLocation(), scheduleRepo, semesterRepo)
+	conflictService := service.NewConflictService(oppRepo, scheduleRepo, semesterRepo)
+	calendarService := service.NewCalendarService(cfg.Calendar, cfg.Auth.BaseURL, userRepo, calendarFeedRepo, oppRepo, userOppRepo, scheduleRepo, semesterRepo)
+
+	// 截止提醒（后台定时扫描，随服务关闭停止）
+	reminderService := service.NewReminderService(cfg.Notification.Reminder, cfg.Calendar.GetLocation(), reminderRepo, userOppRepo, dispatcher, rdb)
+	if cfg.Notification.Reminder.Enabled {
+		go reminderService.Start(workerCtx)
+	}
+
+	// 摘要邮件（dry_run时写入本地文件而不发送）
+	var digestSender mail.Sender = mailSender
+	if cfg.Notification.Digest.DryRun {
+		digestSender = mail.NewFileSender(cfg.Mail.From, cfg.Notification.Digest.DryRunDir)
+	}
+	digestService := service.NewDigestService(cfg.Notification.Digest, cfg.Calendar.GetLocation(), cfg.JWT.Secret, digestRepo, userOppRepo, notificationRepo, recService, dispatcher, digestSender, rdb)
+	if cfg.Notification.Digest.Enabled {
+		go digestService.Start(workerCtx)
+	}
+
+	// 账号资料与头像（每学年开始时自动升级年级）
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		logger.Fatalf("Failed to initialize storage: %v", err)
+	}
+	userService := service.NewUserService(cfg.Users, cfg.Calendar.GetLocation(), userRepo, store, taxonomyService, recService)
+	if cfg.Users.GradeRolloverEnabled {
+		go userService.Start(workerCtx)
+	}
+
+	// 数据导出与账号注销（宽限期满的账号由后台任务删除）
+	privacyService := service.NewPrivacyService(cfg.Privacy, userRepo, profileRepo, userOppRepo, scheduleRepo, semesterRepo, notificationRepo, deletionRepo, auditRepo, userService, recService, rdb)
+	go privacyService.Start(workerCtx)
+
+	// 用户管理（操作写入审计日志）
+	adminService := service.NewAdminService(db, userRepo, profileRepo, userOppRepo, scheduleRepo, sessionRepo, apiKeyRepo, deletionRepo, auditRepo, authService, accountService)
 
 	// 创建路由（传入数据库和Redis实例供后续使用）
-	router := setupRouter(cfg, db, rdb, authService, oppService, profileService)
+	router := setupRouter(cfg, db, rdb, authService, oppService, profileService, scoringService, recService, similarityService, gapService, taxonomyService, userOppService, scheduleService, conflictService, calendarService, notificationService, digestService, accountService, ssoService, userService, privacyService, adminService)
 
 	// 创建HTTP服务器
 	srv := &http.Server{
@@ -97,6 +197,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopWorkers()
 
 	// 优雅关闭（5秒超时）
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -115,8 +216,28 @@ func main() {
 // authService: 认证服务实例
 // oppService: 机会服务实例
 // profileService: 用户画像服务实例
-func setupRouter(cfg *config.Config, db *postgres.DB, rdb *redis.Client, authService *service.AuthService, oppService *service.OpportunityService, profileService *service.ProfileService) *gin.Engine {
+// scoringService: 评分服务实例
+// recService: 个性化推荐服务实例
+// similarityService: 语义相似检索服务实例
+// gapService: 能力缺口诊断服务实例
+// taxonomyService: 技能/专业分类体系服务实例
+// userOppService: 用户机会生命周期服务实例
+// scheduleService: 日程与学期服务实例
+// conflictService: 时间冲突检测服务实例
+// calendarService: 日历导出与订阅服务实例
+// notificationService: 站内通知服务实例
+// digestService: 摘要邮件服务实例
+// accountService: 邮箱验证与密码找回服务实例
+// ssoService: 校园统一身份认证服务实例
+// userService: 账号资料与头像服务实例
+// privacyService: 数据导出与账号注销服务实例
+// adminService: 用户管理服务实例
+func setupRouter(cfg *config.Config, db *postgres.DB, rdb *redis.Client, authService *service.AuthService, oppService *service.OpportunityService, profileService *service.ProfileService, scoringService *service.ScoringService, recService *service.RecommendationService, similarityService *service.SimilarityService, gapService *service.GapService, taxonomyService *service.TaxonomyService, userOppService *service.UserOpportunityService, scheduleService *service.ScheduleService, conflictService *service.ConflictService, calendarService *service.CalendarService, notificationService *service.NotificationService, digestService *service.DigestService, accountService *service.AccountService, ssoService *service.SSOService, userService *service.UserService, privacyService *service.PrivacyService, adminService *service.AdminService) *gin.Engine {
 	router := gin.New()
+	// 只信任配置的反向代理转发的客户端IP，登录防护与限流按真实IP计数
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatalf("Invalid server.trusted_proxies: %v", err)
+	}
 
 	// 中间件
 	router.Use(gin.Recovery())
@@ -125,10 +246,34 @@ func setupRouter(cfg *config.Config, db *postgres.DB, rdb *redis.Client, authSer
 	router.Use(loggerMiddleware())
 
 	// 初始化handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	oppHandler := handlers.NewOpportunityHandler(oppService)
+	authHandler := handlers.NewAuthHandler(authService, accountService)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	ssoHandler := handlers.NewSSOHandler(ssoService)
+	oppHandler := handlers.NewOpportunityHandler(oppService, conflictService)
 	profileHandler := handlers.NewProfileHandler(profileService)
 	metricsHandler := handlers.NewMetricsHandler()
+	scoringHandler := handlers.NewScoringHandler(scoringService)
+	recHandler := handlers.NewRecommendationHandler(recService)
+	similarityHandler := handlers.NewSimilarityHandler(similarityService)
+	gapHandler := handlers.NewGapHandler(gapService)
+	taxonomyHandler := handlers.NewTaxonomyHandler(taxonomyService)
+	userOppHandler := handlers.NewUserOpportunityHandler(userOppService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	userHandler := handlers.NewUserHandler(userService)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+
+	// 限流（滑动窗口，计数保存在Redis）
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		limiter = ratelimit.NewLimiter(rdb)
+	}
+	authLimit := middleware.RateLimit(limiter, "auth", cfg.RateLimit.AuthPerMinute, time.Minute, middleware.ByIP)
+	mailLimit := middleware.RateLimit(limiter, "mail", cfg.RateLimit.PasswordResetPerHour, time.Hour, middleware.ByIP)
+	uploadLimit := middleware.RateLimit(limiter, "upload", cfg.RateLimit.UploadPerHour, time.Hour, middleware.ByUser)
 
 	// 健康检查
 	router.GET("/health", func(c *gin.Context) {
@@ -142,34 +287,171 @@ func setupRouter(cfg *config.Config, db *postgres.DB, rdb *redis.Client, authSer
 	// 监控指标
 	router.GET("/api/v1/metrics", metricsHandler.GetMetrics)
 
+	// 本地存储的上传文件（头像）；public_url 为完整地址时由外部服务提供
+	if cfg.Storage.Driver == "local" && strings.HasPrefix(cfg.Storage.PublicURL, "/") {
+		router.Static(cfg.Storage.PublicURL, cfg.Storage.LocalDir)
+	}
+
 	// API路由组
 	v1 := router.Group("/api/v1")
 	{
 		// 认证路由（无需JWT）
 		auth := v1.Group("/auth")
+		auth.Use(authLimit)
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/logout", middleware.AuthMiddleware(authService), authHandler.Logout)
+			auth.POST("/verify-email", accountHandler.VerifyEmail)
+			auth.POST("/forgot-password", mailLimit, accountHandler.ForgotPassword)
+			auth.POST("/reset-password", accountHandler.ResetPassword)
+
+			// 校园统一身份认证（OIDC/CAS）
+			auth.GET("/sso", ssoHandler.Providers)
+			auth.GET("/sso/:provider/login", ssoHandler.Login)
+			auth.POST("/sso/:provider/callback", ssoHandler.Callback)
 		}
 
 		// 公开的机会查询路由（无需认证）
 		v1.GET("/opportunities", oppHandler.List)
-		v1.GET("/opportunities/:id", oppHandler.GetByID)
-
-		// 需要认证的路由
+		v1.GET("/opportunities/:id", middleware.OptionalAuthMiddleware(authService), oppHandler.GetByID)
+		v1.GET("/opportunities/:id/similar", similarityHandler.Similar)
+		v1.GET("/opportunities/:id/calendar.ics", calendarHandler.Opportunity)
+
+		// 日历订阅（使用订阅令牌而非JWT认证，供手机日历定期拉取）
+		v1.GET("/users/me/calendar.ics", calendarHandler.Feed)
+
+		// 摘要邮件退订（邮件中的签名链接，POST用于邮件客户端一键退订）
+		v1.GET("/digest/unsubscribe", digestHandler.Unsubscribe)
+		v1.POST("/digest/unsubscribe", digestHandler.Unsubscribe)
+
+		// 技能/专业分类查询（用于前端联想输入）
+		v1.GET("/taxonomy", taxonomyHandler.List)
+
+		// 也可使用个人访问令牌调用的接口（浏览器插件、脚本），令牌需具有对应权限范围
+		readOpportunities := middleware.AuthMiddleware(authService, domain.ScopeOpportunitiesRead)
+		submitOpportunities := middleware.AuthMiddleware(authService, domain.ScopeOpportunitiesSubmit)
+		readProfile := middleware.AuthMiddleware(authService, domain.ScopeProfileRead)
+		// 发布机会需要发布者及以上角色
+		opportunityWriter := middleware.RequireRole(domain.OpportunityWriterRoles...)
+		v1.POST("/opportunities", submitOpportunities, opportunityWriter, oppHandler.Create)
+		v1.GET("/users/me", readProfile, userHandler.GetMe)
+		v1.GET("/users/me/profile", readProfile, profileHandler.GetProfile)
+		v1.GET("/users/me/opportunities", readOpportunities, userOppHandler.List)
+		v1.POST("/users/me/opportunities", submitOpportunities, userOppHandler.Save)
+		v1.PUT("/users/me/opportunities/:id/status", submitOpportunities, userOppHandler.UpdateStatus)
+		v1.GET("/users/me/recommendations", readOpportunities, recHandler.Feed)
+		v1.GET("/users/me/opportunities/:id/conflicts", readOpportunities, oppHandler.Conflicts)
+
+		// 通知实时推送流：浏览器 EventSource 无法携带请求头，也接受查询参数中的一次性流令牌
+		v1.GET("/users/me/notifications/stream", middleware.StreamAuthMiddleware(authService), notificationHandler.Stream)
+
+		// 需要认证的路由（只接受JWT）
 		authorized := v1.Group("")
 		authorized.Use(middleware.AuthMiddleware(authService))
 		{
-			// 机会管理（需要认证）
-			authorized.POST("/opportunities", oppHandler.Create)
-			authorized.PUT("/opportunities/:id", oppHandler.Update)
-			authorized.DELETE("/opportunities/:id", oppHandler.Delete)
+			// 机会管理（需要发布者及以上角色）
+			authorized.PUT("/opportunities/:id", opportunityWriter, oppHandler.Update)
+			authorized.DELETE("/opportunities/:id", opportunityWriter, oppHandler.Delete)
 
 			// 用户画像管理
-			authorized.GET("/users/me/profile", profileHandler.GetProfile)
 			authorized.PUT("/users/me/profile", profileHandler.UpdateProfile)
-			authorized.POST("/users/me/profile/resume", profileHandler.UploadResume)
+			authorized.POST("/users/me/profile/resume", uploadLimit, profileHandler.UploadResume)
+
+			// 推荐反馈（感兴趣/不感兴趣/不相关）
+			authorized.POST("/users/me/opportunities/:id/feedback", userOppHandler.Feedback)
+
+			// 机会评分
+			authorized.POST("/users/me/opportunities/:id/score", scoringHandler.Score)
+
+			// 简历语义匹配
+			authorized.GET("/users/me/resume/matches", similarityHandler.ResumeMatches)
+
+			// 能力缺口诊断
+			authorized.GET("/users/me/opportunities/:id/gap", gapHandler.Diagnose)
+			authorized.GET("/users/me/skill-gaps", gapHandler.TopGaps)
+
+			// 日程管理（课程/考试/活动）
+			authorized.GET("/users/me/schedules", scheduleHandler.List)
+			authorized.POST("/users/me/schedules", scheduleHandler.Create)
+			authorized.GET("/users/me/schedules/occurrences", scheduleHandler.Occurrences)
+			authorized.POST("/users/me/schedules/import", uploadLimit, scheduleHandler.Import)
+			authorized.GET("/users/me/schedules/:id", scheduleHandler.GetByID)
+			authorized.PUT("/users/me/schedules/:id", scheduleHandler.Update)
+			authorized.DELETE("/users/me/schedules/:id", scheduleHandler.Delete)
+			authorized.GET("/users/me/semesters", scheduleHandler.ListSemesters)
+			authorized.POST("/users/me/semesters", scheduleHandler.CreateSemester)
+			authorized.PUT("/users/me/semesters/:id", scheduleHandler.UpdateSemester)
+			authorized.DELETE("/users/me/semesters/:id", scheduleHandler.DeleteSemester)
+
+			// 日历订阅令牌
+			authorized.GET("/users/me/calendar/token", calendarHandler.GetFeedToken)
+			authorized.POST("/users/me/calendar/token", calendarHandler.CreateFeedToken)
+			authorized.DELETE("/users/me/calendar/token", calendarHandler.RevokeFeedToken)
+
+			// 站内通知中心（stream-token 用于建立SSE实时推送连接）
+			authorized.GET("/users/me/notifications", notificationHandler.List)
+			authorized.GET("/users/me/notifications/unread-count", notificationHandler.UnreadCount)
+			authorized.POST("/users/me/notifications/stream-token", authHandler.CreateStreamToken)
+			authorized.PUT("/users/me/notifications/read-all", notificationHandler.MarkAllRead)
+			authorized.PUT("/users/me/notifications/:id/read", notificationHandler.MarkRead)
+			authorized.GET("/users/me/notification-preferences", notificationHandler.GetPreferences)
+			authorized.PUT("/users/me/notification-preferences", notificationHandler.UpdatePreferences)
+
+			// 摘要邮件频率
+			authorized.GET("/users/me/digest", digestHandler.GetPreference)
+			authorized.PUT("/users/me/digest", digestHandler.UpdatePreference)
+
+			// 账号安全
+			authorized.PUT("/users/me/password", accountHandler.ChangePassword)
+			authorized.POST("/users/me/email/verification", mailLimit, accountHandler.ResendVerification)
+
+			// 登录会话（查看与远程登出）
+			authorized.GET("/users/me/sessions", authHandler.ListSessions)
+			authorized.DELETE("/users/me/sessions", authHandler.RevokeOtherSessions)
+			authorized.DELETE("/users/me/sessions/:id", authHandler.RevokeSession)
+
+			// 个人访问令牌
+			authorized.GET("/users/me/api-keys", authHandler.ListAPIKeys)
+			authorized.POST("/users/me/api-keys", authHandler.CreateAPIKey)
+			authorized.DELETE("/users/me/api-keys/:id", authHandler.RevokeAPIKey)
+
+			// 账号资料与头像
+			authorized.PATCH("/users/me", userHandler.UpdateMe)
+			authorized.PUT("/users/me/avatar", uploadLimit, userHandler.UploadAvatar)
+			authorized.DELETE("/users/me/avatar", userHandler.DeleteAvatar)
+
+			// 个人数据导出与账号注销
+			authorized.GET("/users/me/export", privacyHandler.Export)
+			authorized.DELETE("/users/me", privacyHandler.RequestDeletion)
+			authorized.GET("/users/me/deletion", privacyHandler.GetDeletion)
+			authorized.DELETE("/users/me/deletion", privacyHandler.CancelDeletion)
+
+			// 管理后台
+			admin := authorized.Group("/admin")
+			admin.Use(middleware.RequireRole(domain.RoleAdmin))
+			{
+				// 分类体系维护
+				admin.GET("/taxonomy", taxonomyHandler.List)
+				admin.POST("/taxonomy", taxonomyHandler.Create)
+				admin.GET("/taxonomy/:id", taxonomyHandler.GetByID)
+				admin.PUT("/taxonomy/:id", taxonomyHandler.Update)
+				admin.DELETE("/taxonomy/:id", taxonomyHandler.Delete)
+
+				// 反馈统计
+				admin.GET("/feedback/irrelevant", userOppHandler.FeedbackReport)
+
+				// 用户管理（操作写入审计日志）
+				admin.GET("/users", adminHandler.SearchUsers)
+				admin.GET("/users/:id", adminHandler.GetUser)
+				admin.GET("/users/:id/audit-logs", adminHandler.ListAuditLogs)
+				admin.POST("/users/:id/suspend", adminHandler.Suspend)
+				admin.POST("/users/:id/reactivate", adminHandler.Reactivate)
+				admin.POST("/users/:id/password-reset", adminHandler.ForcePasswordReset)
+				admin.PUT("/users/:id/role", adminHandler.ChangeRole)
+				admin.POST("/users/:id/revoke-tokens", adminHandler.RevokeTokens)
+			}
 		}
 	}
 
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// AccountHandler handles email verification and password recovery HTTP requests
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// VerifyEmail handles confirming an email address with the token from the verification email
+// @Summary Verify email
+// @Tags auth
+// @Accept json
+// @Param request body domain.VerifyEmailRequest true "Token from the verification email"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/auth/verify-email [post]
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification handles sending a new verification email to the current user
+// @Summary Resend verification email
+// @Tags auth
+// @Success 202
+// @Router /api/v1/users/me/email/verification [post]
+func (h *AccountHandler) ResendVerification(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.accountService.ResendVerification(c.Request.Context(), userID); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ForgotPassword handles requesting a password reset email
+// @Summary Forgot password
+// @Description Always accepted, whether or not the address has an account
+// @Tags auth
+// @Accept json
+// @Param request body domain.ForgotPasswordRequest true "Account email"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/auth/forgot-password [post]
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req domain.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.accountService.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword handles setting a new password with the token from the reset email
+// @Summary Reset password
+// @Description Sets the new password and signs out every session of the account
+// @Tags auth
+// @Accept json
+// @Param request body domain.ResetPasswordRequest true "Token and new password"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/auth/reset-password [post]
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req domain.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.accountService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword handles changing the current user's password
+// @Summary Change password
+// @Description Signs out every session, including the current one, and returns tokens for a new session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body domain.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} domain.TokenPair
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/password [put]
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.accountService.ChangePassword(c.Request.Context(), userID, &req, clientInfo(c))
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// respondAccountError 将账号服务错误映射为HTTP状态码
+func respondAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAccountToken), errors.Is(err, service.ErrIncorrectPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
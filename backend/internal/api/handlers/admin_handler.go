@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// AdminHandler handles user administration HTTP requests
+type AdminHandler struct {
+	adminService *service.AdminService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService *service.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// SearchUsers handles searching users (admin)
+// @Summary Search users
+// @Tags admin
+// @Produce json
+// @Param email query string false "Part of the email address"
+// @Param school query string false "School"
+// @Param major query string false "Major"
+// @Param role query string false "student, publisher, school_admin or admin"
+// @Param status query string false "active or suspended"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/users [get]
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	var filter domain.AdminUserFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, total, err := h.adminService.SearchUsers(c.Request.Context(), &filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   users,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// GetUser handles viewing a user's account, profile and activity (admin)
+// @Summary Get a user
+// @Description The view is recorded in the user's audit log
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} domain.AdminUserDetail
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	adminID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.adminService.GetUser(c.Request.Context(), adminID, userID, clientInfo(c))
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// ListAuditLogs handles listing the audit trail of a user (admin)
+// @Summary List a user's audit logs
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/admin/users/{id}/audit-logs [get]
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	_, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	logs, err := h.adminService.ListAuditLogs(c.Request.Context(), userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": logs})
+}
+
+// Suspend handles suspending a user (admin)
+// @Summary Suspend a user
+// @Description Suspended users cannot sign in, and their tokens and API keys are rejected until reactivated
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body domain.SuspendUserRequest true "Reason"
+// @Success 200 {object} domain.User
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/users/{id}/suspend [post]
+func (h *AdminHandler) Suspend(c *gin.Context) {
+	adminID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	var req domain.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.adminService.Suspend(c.Request.Context(), adminID, userID, req.Reason, clientInfo(c))
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Reactivate handles lifting a user's suspension (admin)
+// @Summary Reactivate a user
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} domain.User
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/users/{id}/reactivate [post]
+func (h *AdminHandler) Reactivate(c *gin.Context) {
+	adminID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.Reactivate(c.Request.Context(), adminID, userID, clientInfo(c))
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ForcePasswordReset handles resetting a user's password (admin)
+// @Summary Force a password reset
+// @Description Invalidate the password, sign the user out everywhere and email them a reset link
+// @Tags admin
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/users/{id}/password-reset [post]
+func (h *AdminHandler) ForcePasswordReset(c *gin.Context) {
+	adminID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.ForcePasswordReset(c.Request.Context(), adminID, userID, clientInfo(c)); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangeRole handles changing a user's role (admin)
+// @Summary Change a user's role
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body domain.GrantRoleRequest true "student, publisher, school_admin or admin"
+// @Success 200 {object} domain.User
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/users/{id}/role [put]
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	adminID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	var req domain.GrantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.adminService.ChangeRole(c.Request.Context(), adminID, userID, req.Role, clientInfo(c))
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// RevokeTokens handles revoking every token of a user (admin)
+// @Summary Revoke all tokens of a user
+// @Description Invalidate all access tokens issued to the user, end all of their sessions and revoke their API keys
+// @Tags admin
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/users/{id}/revoke-tokens [post]
+func (h *AdminHandler) RevokeTokens(c *gin.Context) {
+	adminID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.RevokeTokens(c.Request.Context(), adminID, userID, clientInfo(c)); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// adminTarget 读取当前管理员与路径中的用户ID；失败时已写入响应
+func adminTarget(c *gin.Context) (int64, int64, bool) {
+	adminID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, 0, false
+	}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return 0, 0, false
+	}
+	return adminID, userID, true
+}
+
+// respondAdminError 将用户管理错误映射为HTTP状态码
+func respondAdminError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCannotModifySelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserAlreadySuspended), errors.Is(err, service.ErrUserNotSuspended):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
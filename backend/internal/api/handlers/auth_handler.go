@@ -1,29 +1,36 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
 	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/ratelimit"
 	"github.com/unifocus/backend/internal/service"
+	"github.com/unifocus/backend/pkg/logger"
 )
 
 // AuthHandler handles authentication HTTP requests
 type AuthHandler struct {
-	authService *service.AuthService
+	authService    *service.AuthService
+	accountService *service.AccountService
 }
 
 // NewAuthHandler creates a new authentication handler
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, accountService *service.AccountService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:    authService,
+		accountService: accountService,
 	}
 }
 
 // Register handles user registration
 // @Summary Register a new user
-// @Description Create a new user account
+// @Description Create a new user account and email a verification link. Tokens are omitted when the email must be verified before signing in.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -39,7 +46,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, token, err := h.authService.Register(c.Request.Context(), &req)
+	user, tokens, err := h.authService.Register(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "email already exists" || err.Error() == "username already exists" {
@@ -49,12 +56,21 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// 验证邮件异步发送，失败时用户可在登录后重新发送
+	verifyUser := *user
+	go func() {
+		if err := h.accountService.SendVerification(context.Background(), &verifyUser); err != nil {
+			logger.Warnf("failed to send verification email to user %d: %v", verifyUser.ID, err)
+		}
+	}()
+
 	// Clear password from response
 	user.Password = ""
 
 	c.JSON(http.StatusCreated, domain.LoginResponse{
-		Token: token,
-		User:  user,
+		TokenPair:            tokens,
+		User:                 user,
+		VerificationRequired: tokens == nil,
 	})
 }
 
@@ -67,6 +83,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Param request body domain.LoginRequest true "Login request"
 // @Success 200 {object} domain.LoginResponse
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req domain.LoginRequest
@@ -75,9 +93,19 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := h.authService.Login(c.Request.Context(), &req)
+	user, tokens, err := h.authService.Login(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		var limitErr *ratelimit.LimitError
+		switch {
+		case errors.As(err, &limitErr):
+			retryAfter := limitErr.RetryAfterSeconds()
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "retry_after": retryAfter})
+		case errors.Is(err, service.ErrEmailNotVerified), errors.Is(err, service.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -85,40 +113,268 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	user.Password = ""
 
 	c.JSON(http.StatusOK, domain.LoginResponse{
-		Token: token,
-		User:  user,
+		TokenPair: tokens,
+		User:      user,
 	})
 }
 
 // RefreshToken handles token refresh
-// @Summary Refresh JWT token
-// @Description Generate a new token from an existing valid token
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and a rotated refresh token. Reusing a rotated refresh token signs the session out.
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param Authorization header string true "Bearer token"
-// @Success 200 {object} map[string]string
+// @Param request body domain.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} domain.TokenPair
 // @Failure 401 {object} map[string]string
 // @Router /api/v1/auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
+	var req domain.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRefreshToken), errors.Is(err, service.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case err.Error() == "user not found":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout handles signing out the current session
+// @Summary Logout
+// @Description Revoke the presented access token and end its session, including its refresh token
+// @Tags auth
+// @Param Authorization header string true "Bearer token"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Extract token from "Bearer <token>"
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+	c.Status(http.StatusNoContent)
+}
+
+// ListSessions handles listing the current user's active sessions
+// @Summary List my sessions
+// @Description Active logins across devices; the one making the request is flagged as current
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
+	sessionID, _ := middleware.GetSessionID(c)
 
-	token, err := h.authService.RefreshToken(c.Request.Context(), parts[1])
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID, sessionID)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, gin.H{"data": sessions})
+}
+
+// CreateStreamToken handles issuing a token for opening the notification stream
+// @Summary Issue a notification stream token
+// @Description Single-use token for GET /users/me/notifications/stream?token=..., for browser EventSource
+// @Description which cannot send the Authorization header. It must be used within expires_in seconds.
+// @Tags notifications
+// @Produce json
+// @Success 201 {object} domain.StreamToken
+// @Router /api/v1/users/me/notifications/stream-token [post]
+func (h *AuthHandler) CreateStreamToken(c *gin.Context) {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	token, err := h.authService.IssueStreamToken(c.Request.Context(), claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// RevokeSession handles signing out one of the current user's sessions
+// @Summary Sign out a session
+// @Tags auth
+// @Param id path int true "Session ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, id); err != nil {
+		if err.Error() == "session not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessions handles signing out every other session of the current user
+// @Summary Sign out all other sessions
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Router /api/v1/users/me/sessions [delete]
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	sessionID, _ := middleware.GetSessionID(c)
+
+	revoked, err := h.authService.RevokeOtherSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// ListAPIKeys handles listing the current user's personal API keys
+// @Summary List my API keys
+// @Description Keys that have not been revoked, including expired ones. Tokens are never shown again after creation.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/api-keys [get]
+func (h *AuthHandler) ListAPIKeys(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	keys, err := h.authService.ListAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+// CreateAPIKey handles creating a personal API key
+// @Summary Create an API key
+// @Description Create a personal access token for the browser extension or scripts. Send it as "Authorization: Bearer ufk_..."; it only works on endpoints that accept its scopes. The token is returned only in this response.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body domain.CreateAPIKeyRequest true "Name, scopes (opportunities:read, opportunities:submit, profile:read) and expiry (1-365 days, default 90)"
+// @Success 201 {object} domain.CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/users/me/api-keys [post]
+func (h *AuthHandler) CreateAPIKey(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, err := h.authService.CreateAPIKey(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyAPIKeys) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// RevokeAPIKey handles revoking one of the current user's API keys
+// @Summary Revoke an API key
+// @Tags auth
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/api-keys/{id} [delete]
+func (h *AuthHandler) RevokeAPIKey(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid api key id"})
+		return
+	}
+
+	if err := h.authService.RevokeAPIKey(c.Request.Context(), userID, id); err != nil {
+		if err.Error() == "api key not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// clientInfo 提取登录请求的客户端信息
+func clientInfo(c *gin.Context) *domain.ClientInfo {
+	return &domain.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
 }
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/ical"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// calendarContentType iCalendar响应类型
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// CalendarHandler handles iCalendar export and feed token HTTP requests
+type CalendarHandler struct {
+	calendarService *service.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(calendarService *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// Feed handles the calendar subscription feed
+// @Summary Subscribe to my calendar
+// @Description Saved and applied opportunity deadlines, event dates and schedules as iCalendar, authenticated by a feed token instead of a JWT
+// @Tags calendar
+// @Produce text/calendar
+// @Param token query string true "Feed token"
+// @Success 200 {string} string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/users/me/calendar.ics [get]
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	userID, err := h.calendarService.FeedUserID(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFeedToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		} else if errors.Is(err, service.ErrAccountSuspended) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	cal, err := h.calendarService.UserCalendar(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	writeCalendar(c, cal, "unifocus.ics", false)
+}
+
+// Opportunity handles exporting a single opportunity
+// @Summary Export an opportunity to my calendar
+// @Description Download the deadline and event date of an opportunity as an .ics file
+// @Tags calendar
+// @Produce text/calendar
+// @Param id path int true "Opportunity ID"
+// @Success 200 {string} string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/opportunities/{id}/calendar.ics [get]
+func (h *CalendarHandler) Opportunity(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
+		return
+	}
+
+	cal, err := h.calendarService.OpportunityCalendar(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "opportunity not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	writeCalendar(c, cal, fmt.Sprintf("opportunity-%d.ics", id), true)
+}
+
+// GetFeedToken handles getting the state of the current user's feed token
+// @Summary Get my calendar feed
+// @Description Return when the feed token was created and last used; the token itself is never returned again
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} domain.CalendarFeed
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/calendar/token [get]
+func (h *CalendarHandler) GetFeedToken(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	feed, err := h.calendarService.GetFeed(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "calendar feed not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// CreateFeedToken handles generating a new feed token
+// @Summary Create my calendar feed token
+// @Description Generate a new feed token and subscription URL; any previous token stops working
+// @Tags calendar
+// @Produce json
+// @Success 201 {object} domain.CalendarFeedToken
+// @Router /api/v1/users/me/calendar/token [post]
+func (h *CalendarHandler) CreateFeedToken(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	token, feed, err := h.calendarService.CreateFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &domain.CalendarFeedToken{
+		Token:     token,
+		URL:       h.calendarService.FeedURL(token),
+		CreatedAt: feed.CreatedAt,
+	})
+}
+
+// RevokeFeedToken handles revoking the current user's feed token
+// @Summary Revoke my calendar feed token
+// @Description Subscriptions using the token stop working immediately
+// @Tags calendar
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/calendar/token [delete]
+func (h *CalendarHandler) RevokeFeedToken(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.calendarService.RevokeFeedToken(c.Request.Context(), userID); err != nil {
+		if err.Error() == "calendar feed not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeCalendar 输出iCalendar内容，attachment为true时提示浏览器下载
+func writeCalendar(c *gin.Context, cal *ical.Calendar, filename string, attachment bool) {
+	disposition := "inline"
+	if attachment {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, filename))
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, calendarContentType, []byte(cal.String()))
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// unsubscribedPage 通过邮件链接退订后展示的页面
+const unsubscribedPage = `<!DOCTYPE html>
+<html lang="zh-CN"><head><meta charset="UTF-8"><title>已退订</title></head>
+<body style="font-family:sans-serif;text-align:center;padding-top:80px;color:#1f2329;">
+<h2>已退订 UniFocus 摘要邮件</h2>
+<p style="color:#646a73;">你可以随时在个人设置中重新开启。</p>
+</body></html>`
+
+// DigestHandler handles digest email preference HTTP requests
+type DigestHandler struct {
+	digestService *service.DigestService
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(digestService *service.DigestService) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+	}
+}
+
+// GetPreference handles getting the current user's digest frequency
+// @Summary Get my digest preference
+// @Tags digest
+// @Produce json
+// @Success 200 {object} domain.DigestPreference
+// @Router /api/v1/users/me/digest [get]
+func (h *DigestHandler) GetPreference(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	pref, err := h.digestService.GetPreference(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+// UpdatePreference handles changing the current user's digest frequency
+// @Summary Update my digest preference
+// @Tags digest
+// @Accept json
+// @Produce json
+// @Param request body domain.UpdateDigestPreferenceRequest true "daily, weekly or off"
+// @Success 200 {object} domain.DigestPreference
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/digest [put]
+func (h *DigestHandler) UpdatePreference(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.UpdateDigestPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pref, err := h.digestService.UpdatePreference(c.Request.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+// Unsubscribe handles the unsubscribe link of digest emails
+// @Summary Unsubscribe from digest emails
+// @Description GET is the link in the email and returns a confirmation page; POST is the RFC 8058 one-click unsubscribe used by mail clients
+// @Tags digest
+// @Produce html
+// @Param token query string true "Unsubscribe token from the email"
+// @Success 200 {string} string
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/digest/unsubscribe [get]
+// @Router /api/v1/digest/unsubscribe [post]
+func (h *DigestHandler) Unsubscribe(c *gin.Context) {
+	if err := h.digestService.Unsubscribe(c.Request.Context(), c.Query("token")); err != nil {
+		if errors.Is(err, service.ErrInvalidUnsubscribeToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if c.Request.Method == http.MethodPost {
+		c.JSON(http.StatusOK, gin.H{"message": "unsubscribed"})
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(unsubscribedPage))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// GapHandler handles skill gap diagnosis HTTP requests
+type GapHandler struct {
+	gapService *service.GapService
+}
+
+// NewGapHandler creates a new skill gap handler
+func NewGapHandler(gapService *service.GapService) *GapHandler {
+	return &GapHandler{
+		gapService: gapService,
+	}
+}
+
+// Diagnose handles the skill gap diagnosis of one opportunity
+// @Summary Diagnose skill gap for an opportunity
+// @Description Compare the current user's skills, certificates, grade and major with the opportunity requirements
+// @Tags skill-gap
+// @Produce json
+// @Param id path int true "Opportunity ID"
+// @Success 200 {object} domain.SkillGapReport
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/opportunities/{id}/gap [get]
+func (h *GapHandler) Diagnose(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
+		return
+	}
+
+	report, err := h.gapService.Diagnose(c.Request.Context(), userID, id)
+	if err != nil {
+		if err.Error() == "opportunity not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// TopGaps handles the aggregated skill gap view
+// @Summary Get skills that unlock the most opportunities
+// @Description Aggregate missing skills over the current user's candidate opportunities
+// @Tags skill-gap
+// @Produce json
+// @Param limit query int false "Limit (default: 20)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/skill-gaps [get]
+func (h *GapHandler) TopGaps(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	gaps, err := h.gapService.TopSkillGaps(c.Request.Context(), userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gaps})
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// streamHeartbeat 实时推送连接的心跳间隔，避免代理因空闲断开连接
+const streamHeartbeat = 25 * time.Second
+
+// NotificationHandler handles notification center HTTP requests
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// List handles listing the current user's notifications
+// @Summary List my notifications
+// @Description Newest first; optionally only unread ones or one type
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param type query string false "deadline_reminder, status_change or new_match"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var filter domain.NotificationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	notifications, total, err := h.notificationService.List(c.Request.Context(), userID, &filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	unread, err := h.notificationService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   notifications,
+		"total":  total,
+		"unread": unread,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// UnreadCount handles getting the number of unread notifications
+// @Summary Count my unread notifications
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Router /api/v1/users/me/notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	count, err := h.notificationService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// MarkRead handles marking a notification as read
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} domain.Notification
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
+		return
+	}
+
+	notification, err := h.notificationService.MarkRead(c.Request.Context(), userID, id)
+	if err != nil {
+		if err.Error() == "notification not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// MarkAllRead handles marking all notifications as read
+// @Summary Mark all my notifications as read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Router /api/v1/users/me/notifications/read-all [put]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	updated, err := h.notificationService.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// Stream handles the live notification stream
+// @Summary Stream my notifications
+// @Description Server-Sent Events: "notification" events carry new notifications with their ID as the event ID,
+// @Description "ping" events are heartbeats. Reconnecting with Last-Event-ID replays what was missed.
+// @Description Authenticate with the Authorization header, or — for browser EventSource, which cannot set
+// @Description headers — with a single-use token from POST /users/me/notifications/stream-token. A stream
+// @Description token cannot be reused, so when the connection drops close the EventSource, request a new
+// @Description token and reconnect with last_event_id set to the last received event ID.
+// @Tags notifications
+// @Produce text/event-stream
+// @Param token query string false "Stream token (instead of the Authorization header)"
+// @Param last_event_id query int false "Last received event ID, for clients that cannot send Last-Event-ID"
+// @Success 200 {string} string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/users/me/notifications/stream [get]
+func (h *NotificationHandler) Stream(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	// 先订阅再补发，避免两者之间产生的通知丢失（重复的由客户端按ID去重）
+	notifications, unsubscribe := h.notificationService.Subscribe(userID)
+	defer unsubscribe()
+
+	var missed []*domain.Notification
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastID, err := strconv.ParseInt(lastEventID, 10, 64); err == nil {
+		missed, err = h.notificationService.Missed(c.Request.Context(), userID, lastID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// 长连接不受服务器写超时限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for _, n := range missed {
+		writeNotificationEvent(c.Writer, n)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case n, ok := <-notifications:
+			if !ok {
+				return false
+			}
+			writeNotificationEvent(w, n)
+		case t := <-heartbeat.C:
+			fmt.Fprintf(w, "event: ping\ndata: %d\n\n", t.Unix())
+		}
+		return true
+	})
+}
+
+// writeNotificationEvent 以SSE事件输出通知，事件ID为通知ID
+func writeNotificationEvent(w io.Writer, n *domain.Notification) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", n.ID, data)
+}
+
+// GetPreferences handles getting the current user's notification preferences
+// @Summary Get my notification preferences
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} domain.NotificationPreferences
+// @Router /api/v1/users/me/notification-preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles replacing the current user's notification preferences
+// @Summary Update my notification preferences
+// @Description Event types, channels, deadline reminder lead times and quiet hours; omitted types use the defaults
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body domain.NotificationPreferences true "Notification preferences"
+// @Success 200 {object} domain.NotificationPreferences
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/notification-preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var prefs domain.NotificationPreferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.notificationService.UpdatePreferences(c.Request.Context(), userID, &prefs)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidNotificationPreferences):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err.Error() == "user not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
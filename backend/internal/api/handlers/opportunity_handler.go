@@ -1,44 +1,56 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
 	"github.com/unifocus/backend/internal/domain"
 	"github.com/unifocus/backend/internal/service"
+	"github.com/unifocus/backend/pkg/logger"
 )
 
 // OpportunityHandler handles opportunity HTTP requests
 type OpportunityHandler struct {
-	oppService *service.OpportunityService
+	oppService      *service.OpportunityService
+	conflictService *service.ConflictService // 登录用户查看详情时附带时间冲突分析，可为nil
 }
 
 // NewOpportunityHandler creates a new opportunity handler
-func NewOpportunityHandler(oppService *service.OpportunityService) *OpportunityHandler {
+func NewOpportunityHandler(oppService *service.OpportunityService, conflictService *service.ConflictService) *OpportunityHandler {
 	return &OpportunityHandler{
-		oppService: oppService,
+		oppService:      oppService,
+		conflictService: conflictService,
 	}
 }
 
 // Create handles opportunity creation
 // @Summary Create a new opportunity
-// @Description Create a new opportunity (requires authentication)
+// @Description Create a new opportunity (requires the publisher, school_admin or admin role)
 // @Tags opportunities
 // @Accept json
 // @Produce json
 // @Param request body domain.CreateOpportunityRequest true "Opportunity creation request"
 // @Success 201 {object} domain.Opportunity
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Router /api/v1/opportunities [post]
 func (h *OpportunityHandler) Create(c *gin.Context) {
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
 	var req domain.CreateOpportunityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	opp, err := h.oppService.Create(c.Request.Context(), &req)
+	opp, err := h.oppService.Create(c.Request.Context(), user, &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -73,7 +85,57 @@ func (h *OpportunityHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, opp)
+	// 携带有效令牌时附带与个人日程的时间冲突分析
+	userID, ok := middleware.GetUserID(c)
+	if !ok || h.conflictService == nil {
+		c.JSON(http.StatusOK, opp)
+		return
+	}
+
+	detail := &domain.OpportunityDetail{Opportunity: opp}
+	report, err := h.conflictService.AnalyzeOpportunity(c.Request.Context(), userID, opp)
+	if err != nil {
+		logger.Warnf("failed to analyze time conflicts for user %d, opportunity %d: %v", userID, id, err)
+	} else {
+		detail.TimeConflict = report
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// Conflicts handles the time conflict analysis of an opportunity
+// @Summary Get time conflicts with my schedule
+// @Description Overlap the opportunity's event date, start date and deadline windows with the user's expanded schedule and exam weeks
+// @Tags opportunities
+// @Produce json
+// @Param id path int true "Opportunity ID"
+// @Success 200 {object} domain.TimeConflictReport
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/opportunities/{id}/conflicts [get]
+func (h *OpportunityHandler) Conflicts(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
+		return
+	}
+
+	report, err := h.conflictService.Analyze(c.Request.Context(), userID, id)
+	if err != nil {
+		if err.Error() == "opportunity not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
 }
 
 // List handles listing opportunities with filtering
@@ -115,7 +177,7 @@ func (h *OpportunityHandler) List(c *gin.Context) {
 
 // Update handles opportunity update
 // @Summary Update an opportunity
-// @Description Update an existing opportunity (requires authentication)
+// @Description Update an existing opportunity; publishers may only update their own
 // @Tags opportunities
 // @Accept json
 // @Produce json
@@ -123,9 +185,16 @@ func (h *OpportunityHandler) List(c *gin.Context) {
 // @Param request body domain.CreateOpportunityRequest true "Opportunity update request"
 // @Success 200 {object} domain.Opportunity
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /api/v1/opportunities/{id} [put]
 func (h *OpportunityHandler) Update(c *gin.Context) {
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
@@ -138,9 +207,11 @@ func (h *OpportunityHandler) Update(c *gin.Context) {
 		return
 	}
 
-	opp, err := h.oppService.Update(c.Request.Context(), id, &req)
+	opp, err := h.oppService.Update(c.Request.Context(), user, id, &req)
 	if err != nil {
-		if err.Error() == "opportunity not found" {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if err.Error() == "opportunity not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -153,22 +224,31 @@ func (h *OpportunityHandler) Update(c *gin.Context) {
 
 // Delete handles opportunity deletion
 // @Summary Delete an opportunity
-// @Description Soft delete an opportunity (requires authentication)
+// @Description Soft delete an opportunity; publishers may only delete their own
 // @Tags opportunities
 // @Produce json
 // @Param id path int true "Opportunity ID"
 // @Success 204 "No Content"
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /api/v1/opportunities/{id} [delete]
 func (h *OpportunityHandler) Delete(c *gin.Context) {
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
 		return
 	}
 
-	if err := h.oppService.Delete(c.Request.Context(), id); err != nil {
-		if err.Error() == "opportunity not found" {
+	if err := h.oppService.Delete(c.Request.Context(), user, id); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if err.Error() == "opportunity not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// PrivacyHandler handles personal data export and account deletion HTTP requests
+type PrivacyHandler struct {
+	privacyService *service.PrivacyService
+}
+
+// NewPrivacyHandler creates a new privacy handler
+func NewPrivacyHandler(privacyService *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// Export handles downloading everything stored about the current user
+// @Summary Export my data
+// @Description Download a zip archive with the user, profile, resume versions, opportunities, schedules and notifications as JSON
+// @Tags users
+// @Produce application/zip
+// @Success 200 {file} binary
+// @Router /api/v1/users/me/export [get]
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	archive, err := h.privacyService.Export(c.Request.Context(), userID, clientInfo(c))
+	if err != nil {
+		respondPrivacyError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("unifocus-export-%s.zip", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// RequestDeletion handles scheduling the current user's account for deletion
+// @Summary Delete my account
+// @Description Schedule the account and all its data for deletion after a grace period, during which it can be cancelled
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body domain.DeleteAccountRequest true "Current password"
+// @Success 202 {object} domain.AccountDeletion
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me [delete]
+func (h *PrivacyHandler) RequestDeletion(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deletion, err := h.privacyService.RequestDeletion(c.Request.Context(), userID, req.Password, clientInfo(c))
+	if err != nil {
+		respondPrivacyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, deletion)
+}
+
+// GetDeletion handles retrieving the current user's pending deletion request
+// @Summary Get my pending account deletion
+// @Tags users
+// @Produce json
+// @Success 200 {object} domain.AccountDeletion
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/deletion [get]
+func (h *PrivacyHandler) GetDeletion(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	deletion, err := h.privacyService.GetDeletion(c.Request.Context(), userID)
+	if err != nil {
+		respondPrivacyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deletion)
+}
+
+// CancelDeletion handles withdrawing the current user's pending deletion request
+// @Summary Cancel my account deletion
+// @Tags users
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/deletion [delete]
+func (h *PrivacyHandler) CancelDeletion(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.privacyService.CancelDeletion(c.Request.Context(), userID, clientInfo(c)); err != nil {
+		respondPrivacyError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondPrivacyError 将数据导出与注销错误映射为HTTP状态码
+func respondPrivacyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrIncorrectPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDeletionNotRequested), err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// RecommendationHandler handles personalized recommendation HTTP requests
+type RecommendationHandler struct {
+	recService *service.RecommendationService
+}
+
+// NewRecommendationHandler creates a new recommendation handler
+func NewRecommendationHandler(recService *service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recService: recService,
+	}
+}
+
+// Feed handles the personalized recommendation feed
+// @Summary Get personalized recommendations
+// @Description Rank active opportunities by relevance, accessibility and deadline urgency
+// @Tags recommendations
+// @Produce json
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Limit (default: 20, max: 100)"
+// @Success 200 {object} domain.RecommendationPage
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/recommendations [get]
+func (h *RecommendationHandler) Feed(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var query domain.RecommendationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.recService.Feed(c.Request.Context(), userID, &query)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
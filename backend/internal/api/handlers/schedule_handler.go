@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// maxTimetableFileSize 课表文件大小上限
+const maxTimetableFileSize = 2 << 20
+
+// ScheduleHandler handles schedule and semester HTTP requests
+type ScheduleHandler struct {
+	scheduleService *service.ScheduleService
+}
+
+// NewScheduleHandler creates a new schedule handler
+func NewScheduleHandler(scheduleService *service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// List handles listing the current user's schedules
+// @Summary List my schedules
+// @Description List courses, exams and events as stored, without expanding recurrence
+// @Tags schedules
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/schedules [get]
+func (h *ScheduleHandler) List(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	schedules, err := h.scheduleService.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedules})
+}
+
+// Occurrences handles expanding schedules into a time window
+// @Summary Expand my schedules
+// @Description Expand recurring schedules into concrete occurrences within [from, to), honoring semester bounds, holidays and exception dates
+// @Tags schedules
+// @Produce json
+// @Param from query string true "Window start (YYYY-MM-DD)"
+// @Param to query string true "Window end, exclusive (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/schedules/occurrences [get]
+func (h *ScheduleHandler) Occurrences(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var query domain.ScheduleWindowQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	occurrences, err := h.scheduleService.Occurrences(c.Request.Context(), userID, query.From, query.To)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": occurrences})
+}
+
+// GetByID handles getting one schedule
+// @Summary Get my schedule by ID
+// @Tags schedules
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} domain.Schedule
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/schedules/{id} [get]
+func (h *ScheduleHandler) GetByID(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	schedule, err := h.scheduleService.GetByID(c.Request.Context(), userID, id)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// Create handles creating a schedule
+// @Summary Create schedule
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param request body domain.CreateScheduleRequest true "Schedule"
+// @Success 201 {object} domain.Schedule
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/schedules [post]
+func (h *ScheduleHandler) Create(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.scheduleService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// Update handles updating a schedule
+// @Summary Update schedule
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Param request body domain.CreateScheduleRequest true "Schedule"
+// @Success 200 {object} domain.Schedule
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/schedules/{id} [put]
+func (h *ScheduleHandler) Update(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	var req domain.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.scheduleService.Update(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// Delete handles deleting a schedule
+// @Summary Delete schedule
+// @Tags schedules
+// @Param id path int true "Schedule ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/schedules/{id} [delete]
+func (h *ScheduleHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return
+	}
+
+	if err := h.scheduleService.Delete(c.Request.Context(), userID, id); err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListSemesters handles listing the current user's semesters
+// @Summary List my semesters
+// @Tags schedules
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/semesters [get]
+func (h *ScheduleHandler) ListSemesters(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	semesters, err := h.scheduleService.ListSemesters(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": semesters})
+}
+
+// CreateSemester handles creating a semester
+// @Summary Create semester
+// @Description Create a semester whose dates bound recurring schedules and whose holidays suspend them
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param request body domain.CreateSemesterRequest true "Semester"
+// @Success 201 {object} domain.Semester
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/semesters [post]
+func (h *ScheduleHandler) CreateSemester(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.CreateSemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	semester, err := h.scheduleService.CreateSemester(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, semester)
+}
+
+// UpdateSemester handles updating a semester
+// @Summary Update semester
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Semester ID"
+// @Param request body domain.CreateSemesterRequest true "Semester"
+// @Success 200 {object} domain.Semester
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/semesters/{id} [put]
+func (h *ScheduleHandler) UpdateSemester(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid semester ID"})
+		return
+	}
+
+	var req domain.CreateSemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	semester, err := h.scheduleService.UpdateSemester(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, semester)
+}
+
+// DeleteSemester handles deleting a semester
+// @Summary Delete semester
+// @Tags schedules
+// @Param id path int true "Semester ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/semesters/{id} [delete]
+func (h *ScheduleHandler) DeleteSemester(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid semester ID"})
+		return
+	}
+
+	if err := h.scheduleService.DeleteSemester(c.Request.Context(), userID, id); err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Import handles importing a timetable file into schedules
+// @Summary Import my timetable
+// @Description Import an .ics file or a CSV timetable (week ranges, weekday, class periods) as recurring schedules; importing the same file again updates the existing entries
+// @Tags schedules
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Timetable file (.ics or .csv)"
+// @Param format formData string false "ics or csv, detected from the file extension when empty"
+// @Param semester_id formData int false "Semester the timetable belongs to, required for CSV"
+// @Param type formData string false "Schedule type, defaults to 课程"
+// @Success 200 {object} domain.ScheduleImportResult
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/users/me/schedules/import [post]
+func (h *ScheduleHandler) Import(c *gin.Context) {
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.ScheduleImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if file.Size > maxTimetableFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open file"})
+		return
+	}
+	defer src.Close()
+
+	result, err := h.scheduleService.Import(c.Request.Context(), user.ID, user.School, &req, file.Filename, src)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondScheduleError maps schedule service errors to HTTP status codes
+func respondScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err.Error() == "schedule not found" || err.Error() == "semester not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
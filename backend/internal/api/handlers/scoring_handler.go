@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// ScoringHandler handles opportunity scoring HTTP requests
+type ScoringHandler struct {
+	scoringService *service.ScoringService
+}
+
+// NewScoringHandler creates a new scoring handler
+func NewScoringHandler(scoringService *service.ScoringService) *ScoringHandler {
+	return &ScoringHandler{
+		scoringService: scoringService,
+	}
+}
+
+// Score handles computing the scores of an opportunity for the current user
+// @Summary Score an opportunity for the current user
+// @Description Compute accessibility and relevance scores and store them on the user-opportunity relation
+// @Tags scoring
+// @Produce json
+// @Param id path int true "Opportunity ID"
+// @Success 200 {object} domain.UserOpportunity
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/opportunities/{id}/score [post]
+func (h *ScoringHandler) Score(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
+		return
+	}
+
+	uo, err := h.scoringService.ScoreAndSave(c.Request.Context(), userID, id)
+	if err != nil {
+		if err.Error() == "opportunity not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, uo)
+}
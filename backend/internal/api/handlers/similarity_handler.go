@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// SimilarityHandler handles semantic similarity HTTP requests
+type SimilarityHandler struct {
+	similarityService *service.SimilarityService
+}
+
+// NewSimilarityHandler creates a new similarity handler
+func NewSimilarityHandler(similarityService *service.SimilarityService) *SimilarityHandler {
+	return &SimilarityHandler{
+		similarityService: similarityService,
+	}
+}
+
+// Similar handles listing opportunities similar to a given one
+// @Summary Get similar opportunities
+// @Description Retrieve opportunities whose descriptions are semantically closest to the given opportunity
+// @Tags opportunities
+// @Produce json
+// @Param id path int true "Opportunity ID"
+// @Param limit query int false "Limit (default: 10)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/v1/opportunities/{id}/similar [get]
+func (h *SimilarityHandler) Similar(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	results, err := h.similarityService.SimilarOpportunities(c.Request.Context(), id, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results})
+}
+
+// ResumeMatches handles listing opportunities matching the current user's resume
+// @Summary Get opportunities matching my resume
+// @Description Retrieve opportunities whose descriptions are semantically closest to the user's resume
+// @Tags opportunities
+// @Produce json
+// @Param limit query int false "Limit (default: 10)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/v1/users/me/resume/matches [get]
+func (h *SimilarityHandler) ResumeMatches(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	results, err := h.similarityService.ResumeMatches(c.Request.Context(), userID, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results})
+}
+
+// handleError maps similarity service errors to HTTP responses
+func (h *SimilarityHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrVectorUnavailable):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case err.Error() == "opportunity not found" || err.Error() == "profile not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+	"github.com/unifocus/backend/internal/sso"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// ssoStateCookie 绑定登录发起浏览器的 state，回调时与身份提供方返回的 state 比对
+const ssoStateCookie = "unifocus_sso_state"
+
+// ssoCookiePath 登录与回调接口共用的Cookie路径
+const ssoCookiePath = "/api/v1/auth/sso"
+
+// SSOHandler handles campus single sign-on HTTP requests
+type SSOHandler struct {
+	ssoService *service.SSOService
+}
+
+// NewSSOHandler creates a new SSO handler
+func NewSSOHandler(ssoService *service.SSOService) *SSOHandler {
+	return &SSOHandler{
+		ssoService: ssoService,
+	}
+}
+
+// Providers handles listing the configured campus login providers
+// @Summary List campus SSO providers
+// @Tags auth
+// @Produce json
+// @Success 200 {array} domain.SSOProvider
+// @Router /api/v1/auth/sso [get]
+func (h *SSOHandler) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, h.ssoService.Providers())
+}
+
+// Login handles starting a campus login
+// @Summary Start campus SSO login
+// @Description Redirect to the school's OpenID Connect or CAS login page. After signing in the provider redirects to the frontend page {auth.base_url}/sso/{provider}/callback, which posts the received parameters to the callback endpoint.
+// @Tags auth
+// @Param provider path string true "Provider ID"
+// @Success 302
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/auth/sso/{provider}/login [get]
+func (h *SSOHandler) Login(c *gin.Context) {
+	authURL, state, err := h.ssoService.Begin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	setSSOStateCookie(c, state, int(service.SSOStateTTL/time.Second))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback handles finishing a campus login
+// @Summary Finish campus SSO login
+// @Description Exchange the authorization code (OIDC) or ticket (CAS) for UniFocus tokens. The state must match the HttpOnly cookie set by the login endpoint in the same browser. The account is linked to an existing user by verified email, or a new user is created from the school's identity attributes.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider ID"
+// @Param request body domain.SSOCallbackRequest true "Parameters received by the callback page"
+// @Success 200 {object} domain.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/auth/sso/{provider}/callback [post]
+func (h *SSOHandler) Callback(c *gin.Context) {
+	var req domain.SSOCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	params := url.Values{}
+	for name, value := range map[string]string{
+		"state":             req.State,
+		"code":              req.Code,
+		"ticket":            req.Ticket,
+		"error":             req.Error,
+		"error_description": req.ErrorDescription,
+	} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+
+	// state 只能使用一次，无论成败都清除Cookie
+	browserState, _ := c.Cookie(ssoStateCookie)
+	setSSOStateCookie(c, "", -1)
+
+	user, tokens, err := h.ssoService.Complete(c.Request.Context(), c.Param("provider"), params, browserState, clientInfo(c))
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	// Clear password from response
+	user.Password = ""
+
+	c.JSON(http.StatusOK, domain.LoginResponse{
+		TokenPair: tokens,
+		User:      user,
+	})
+}
+
+// setSSOStateCookie 写入或清除（maxAge<0）state Cookie；Lax 允许从身份提供方跳回后的同站请求携带
+func setSSOStateCookie(c *gin.Context, state string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoStateCookie, state, maxAge, ssoCookiePath, "", secure, true)
+}
+
+// respondSSOError 把SSO错误映射为HTTP状态码
+func respondSSOError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSSOProviderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidSSOState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, sso.ErrAuthenticationFailed):
+		logger.Warnf("[SECURITY] sso login failed for provider %s: %v", c.Param("provider"), err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": sso.ErrAuthenticationFailed.Error()})
+	case errors.Is(err, service.ErrSSOEmailNotVerified), errors.Is(err, service.ErrAccountSuspended):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
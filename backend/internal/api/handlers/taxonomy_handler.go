@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// TaxonomyHandler handles skill and major taxonomy HTTP requests
+type TaxonomyHandler struct {
+	taxonomyService *service.TaxonomyService
+}
+
+// NewTaxonomyHandler creates a new taxonomy handler
+func NewTaxonomyHandler(taxonomyService *service.TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{
+		taxonomyService: taxonomyService,
+	}
+}
+
+// List handles listing and searching taxonomy terms
+// @Summary List taxonomy terms
+// @Description Search skills and majors by name, code or synonym
+// @Tags taxonomy
+// @Produce json
+// @Param kind query string false "Term kind (skill/major)"
+// @Param level query string false "Major level (discipline/category/major)"
+// @Param parent_id query int false "Parent term ID"
+// @Param q query string false "Search keyword"
+// @Param limit query int false "Limit (default: 50, max: 200)"
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/taxonomy [get]
+func (h *TaxonomyHandler) List(c *gin.Context) {
+	var filter domain.TaxonomyFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	terms, total, err := h.taxonomyService.List(c.Request.Context(), &filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   terms,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// GetByID handles getting a taxonomy term by ID
+// @Summary Get taxonomy term by ID
+// @Tags taxonomy
+// @Produce json
+// @Param id path int true "Term ID"
+// @Success 200 {object} domain.TaxonomyTerm
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/taxonomy/{id} [get]
+func (h *TaxonomyHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+		return
+	}
+
+	term, err := h.taxonomyService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, term)
+}
+
+// Create handles creating a taxonomy term
+// @Summary Create taxonomy term
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Param request body domain.TaxonomyTermRequest true "Taxonomy term"
+// @Success 201 {object} domain.TaxonomyTerm
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/taxonomy [post]
+func (h *TaxonomyHandler) Create(c *gin.Context) {
+	var req domain.TaxonomyTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	term, err := h.taxonomyService.Create(c.Request.Context(), &req)
+	if err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, term)
+}
+
+// Update handles updating a taxonomy term
+// @Summary Update taxonomy term
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Param id path int true "Term ID"
+// @Param request body domain.TaxonomyTermRequest true "Taxonomy term"
+// @Success 200 {object} domain.TaxonomyTerm
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/taxonomy/{id} [put]
+func (h *TaxonomyHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+		return
+	}
+
+	var req domain.TaxonomyTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	term, err := h.taxonomyService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, term)
+}
+
+// Delete handles deactivating a taxonomy term
+// @Summary Delete taxonomy term
+// @Description Deactivate a taxonomy term; existing values are kept as-is
+// @Tags taxonomy
+// @Param id path int true "Term ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/taxonomy/{id} [delete]
+func (h *TaxonomyHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid term ID"})
+		return
+	}
+
+	if err := h.taxonomyService.Delete(c.Request.Context(), id); err != nil {
+		respondTaxonomyError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondTaxonomyError maps taxonomy service errors to HTTP status codes
+func respondTaxonomyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTaxonomyTerm):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err.Error() == "taxonomy term not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err.Error() == "taxonomy term already exists":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// UserHandler handles account record HTTP requests
+type UserHandler struct {
+	userService *service.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService *service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// GetMe handles retrieving the current user's account
+// @Summary Get my account
+// @Tags users
+// @Produce json
+// @Success 200 {object} domain.User
+// @Router /api/v1/users/me [get]
+func (h *UserHandler) GetMe(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	user, err := h.userService.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateMe handles changing the current user's username, school, major or grade
+// @Summary Update my account
+// @Description Only the fields present in the request are changed
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body domain.UpdateUserRequest true "Fields to change"
+// @Success 200 {object} domain.User
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/users/me [patch]
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.userService.UpdateUser(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UploadAvatar handles replacing the current user's avatar
+// @Summary Upload my avatar
+// @Description Upload a JPEG or PNG image as multipart field "file"
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Avatar image"
+// @Success 200 {object} domain.User
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /api/v1/users/me/avatar [put]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open file"})
+		return
+	}
+	defer src.Close()
+
+	user, err := h.userService.UploadAvatar(c.Request.Context(), userID, src)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteAvatar handles removing the current user's avatar
+// @Summary Delete my avatar
+// @Tags users
+// @Success 204
+// @Router /api/v1/users/me/avatar [delete]
+func (h *UserHandler) DeleteAvatar(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.userService.DeleteAvatar(c.Request.Context(), userID); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondUserError 将账号资料错误映射为HTTP状态码
+func respondUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUsernameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidUser), errors.Is(err, service.ErrInvalidAvatar):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAvatarTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case err.Error() == "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
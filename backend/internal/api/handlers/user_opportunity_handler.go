@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/api/middleware"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/service"
+)
+
+// UserOpportunityHandler handles "my opportunities" HTTP requests
+type UserOpportunityHandler struct {
+	uoService *service.UserOpportunityService
+}
+
+// NewUserOpportunityHandler creates a new user-opportunity handler
+func NewUserOpportunityHandler(uoService *service.UserOpportunityService) *UserOpportunityHandler {
+	return &UserOpportunityHandler{
+		uoService: uoService,
+	}
+}
+
+// List handles listing the current user's opportunities
+// @Summary List my opportunities
+// @Description List saved, applied, completed or abandoned opportunities of the current user
+// @Tags user-opportunities
+// @Produce json
+// @Param status query string false "Status (saved/applied/completed/abandoned)"
+// @Param limit query int false "Limit (default: 20, max: 100)"
+// @Param offset query int false "Offset (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/users/me/opportunities [get]
+func (h *UserOpportunityHandler) List(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var filter domain.UserOpportunityFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, total, err := h.uoService.List(c.Request.Context(), userID, &filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   items,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// Save handles saving an opportunity
+// @Summary Save an opportunity
+// @Description Save an opportunity to the current user's list; saving again is a no-op
+// @Tags user-opportunities
+// @Accept json
+// @Produce json
+// @Param request body domain.SaveOpportunityRequest true "Opportunity to save"
+// @Success 201 {object} domain.UserOpportunity
+// @Success 200 {object} domain.UserOpportunity
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/opportunities [post]
+func (h *UserOpportunityHandler) Save(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.SaveOpportunityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	uo, created, err := h.uoService.Save(c.Request.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "opportunity not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, uo)
+}
+
+// UpdateStatus handles moving an opportunity through the lifecycle
+// @Summary Update my opportunity status
+// @Description Move a saved opportunity to applied, completed or abandoned (or re-save an abandoned one)
+// @Tags user-opportunities
+// @Accept json
+// @Produce json
+// @Param id path int true "Opportunity ID"
+// @Param request body domain.UpdateOpportunityStatusRequest true "New status"
+// @Success 200 {object} domain.UserOpportunity
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/users/me/opportunities/{id}/status [put]
+func (h *UserOpportunityHandler) UpdateStatus(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
+		return
+	}
+
+	var req domain.UpdateOpportunityStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	uo, err := h.uoService.UpdateStatus(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidStatusTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err.Error() == "user opportunity not found" || err.Error() == "opportunity not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, uo)
+}
+
+// Feedback handles recording feedback on an opportunity
+// @Summary Give feedback on an opportunity
+// @Description Mark an opportunity as interested, not interested or irrelevant; negative feedback hides it and down-weights similar ones
+// @Tags user-opportunities
+// @Accept json
+// @Produce json
+// @Param id path int true "Opportunity ID"
+// @Param request body domain.FeedbackRequest true "Feedback"
+// @Success 200 {object} domain.UserOpportunity
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/users/me/opportunities/{id}/feedback [post]
+func (h *UserOpportunityHandler) Feedback(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opportunity ID"})
+		return
+	}
+
+	var req domain.FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	uo, err := h.uoService.Feedback(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		if err.Error() == "opportunity not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, uo)
+}
+
+// FeedbackReport handles the admin report of irrelevant feedback
+// @Summary Get irrelevant feedback report
+// @Description List the opportunities and crawl sources most often marked irrelevant
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Limit per section (default: 20, max: 100)"
+// @Success 200 {object} domain.FeedbackReport
+// @Router /api/v1/admin/feedback/irrelevant [get]
+func (h *UserOpportunityHandler) FeedbackReport(c *gin.Context) {
+	var query domain.FeedbackReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.uoService.FeedbackReport(c.Request.Context(), &query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -11,7 +12,9 @@ import (
 )
 
 // AuthMiddleware creates a middleware that validates JWT tokens
-func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
+// When scopes are given, personal API keys carrying all of them are accepted as
+// well; without scopes the endpoint only accepts JWTs. Suspended users get 403.
+func AuthMiddleware(authService *service.AuthService, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -31,11 +34,20 @@ func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 
 		token := parts[1]
 
+		if service.IsAPIKey(token) {
+			authenticateAPIKey(c, authService, token, scopes)
+			return
+		}
+
 		// Validate token and get user
-		user, err := authService.ValidateToken(c.Request.Context(), token)
+		user, claims, err := authService.ValidateToken(c.Request.Context(), token)
 		if err != nil {
 			if err == jwt.ErrExpiredToken {
 				c.JSON(http.StatusUnauthorized, gin.H{"error": "token expired"})
+			} else if errors.Is(err, service.ErrAccountSuspended) {
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			} else if errors.Is(err, service.ErrTokenRevoked) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
 			} else {
 				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			}
@@ -46,11 +58,114 @@ func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 		// Store user in context
 		c.Set("user_id", user.ID)
 		c.Set("user", user)
+		c.Set("session_id", claims.SessionID)
+		c.Set("claims", claims)
+
+		c.Next()
+	}
+}
+
+// authenticateAPIKey 校验个人访问令牌及其权限范围；未声明权限范围的接口不接受访问令牌
+func authenticateAPIKey(c *gin.Context, authService *service.AuthService, token string, scopes []string) {
+	if len(scopes) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "api keys are not accepted for this endpoint"})
+		c.Abort()
+		return
+	}
+
+	user, key, err := authService.ValidateAPIKey(c.Request.Context(), token, c.ClientIP(), scopes...)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientScope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "required_scopes": scopes})
+		} else if errors.Is(err, service.ErrAccountSuspended) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set("user_id", user.ID)
+	c.Set("user", user)
+	c.Set("api_key", key)
+
+	c.Next()
+}
+
+// StreamAuthMiddleware authenticates long-lived streams: a single-use stream token
+// in the "token" query parameter is accepted in place of the Authorization header,
+// which the browser EventSource cannot send. Without it AuthMiddleware applies.
+func StreamAuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
+	auth := AuthMiddleware(authService)
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" {
+			auth(c)
+			return
+		}
+
+		user, sessionID, err := authService.ValidateStreamToken(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, service.ErrAccountSuspended) {
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			} else if errors.Is(err, service.ErrTokenRevoked) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+			} else {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid stream token"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", user.ID)
+		c.Set("user", user)
+		c.Set("session_id", sessionID)
 
 		c.Next()
 	}
 }
 
+// OptionalAuthMiddleware identifies the user when a valid bearer token is present,
+// and lets anonymous or invalid requests through unchanged
+func OptionalAuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if user, claims, err := authService.ValidateToken(c.Request.Context(), parts[1]); err == nil {
+				c.Set("user_id", user.ID)
+				c.Set("user", user)
+				c.Set("session_id", claims.SessionID)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole allows only users whose role is one of roles; it must run after AuthMiddleware
+// The role is read from the user loaded for this request, so role changes apply immediately
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := GetUser(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+		c.Abort()
+	}
+}
+
 // GetUserID retrieves the user ID from the context (set by AuthMiddleware)
 func GetUserID(c *gin.Context) (int64, bool) {
 	userID, exists := c.Get("user_id")
@@ -62,6 +177,39 @@ func GetUserID(c *gin.Context) (int64, bool) {
 	return id, ok
 }
 
+// GetSessionID retrieves the login session of the request's access token (set by AuthMiddleware)
+func GetSessionID(c *gin.Context) (int64, bool) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		return 0, false
+	}
+
+	id, ok := sessionID.(int64)
+	return id, ok
+}
+
+// GetClaims retrieves the claims of the request's access token (set by AuthMiddleware)
+func GetClaims(c *gin.Context) (*jwt.Claims, bool) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return nil, false
+	}
+
+	cl, ok := claims.(*jwt.Claims)
+	return cl, ok
+}
+
+// GetAPIKey retrieves the personal API key the request was made with (set by AuthMiddleware)
+func GetAPIKey(c *gin.Context) (*domain.APIKey, bool) {
+	key, exists := c.Get("api_key")
+	if !exists {
+		return nil, false
+	}
+
+	k, ok := key.(*domain.APIKey)
+	return k, ok
+}
+
 // GetUser retrieves the user from the context (set by AuthMiddleware)
 func GetUser(c *gin.Context) (*domain.User, bool) {
 	user, exists := c.Get("user")
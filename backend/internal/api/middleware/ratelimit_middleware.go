@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unifocus/backend/internal/ratelimit"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// RateLimitKeyFunc 返回限流计数的主体（IP、用户等）
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// ByIP 按客户端IP限流
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser 按登录用户限流，未登录时退回按IP限流；需放在 AuthMiddleware 之后
+func ByUser(c *gin.Context) string {
+	if userID, ok := GetUserID(c); ok {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	return ByIP(c)
+}
+
+// RateLimit 滑动窗口限流中间件：同一主体在 window 内最多请求 limit 次，
+// 超出时返回 429 并带上 Retry-After。name 区分不同接口的计数。
+// limiter 为nil（未启用限流）时直接放行；Redis出错时放行并记录日志
+func RateLimit(limiter *ratelimit.Limiter, name string, limit int, window time.Duration, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil || limit <= 0 {
+			c.Next()
+			return
+		}
+
+		key := fmt.Sprintf("ratelimit:%s:%s", name, keyFunc(c))
+		result, err := limiter.Allow(c.Request.Context(), key, limit, window)
+		if err != nil {
+			logger.Warnf("[RATELIMIT] %s: %v", key, err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		if !result.Allowed {
+			limitErr := &ratelimit.LimitError{Reason: "rate limit exceeded", RetryAfter: result.RetryAfter}
+			retryAfter := limitErr.RetryAfterSeconds()
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": limitErr.Error(), "retry_after": retryAfter})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -11,21 +12,33 @@ import (
 
 // Config 应用配置结构
 type Config struct {
-	Server     ServerConfig     `yaml:"server"`
-	Database   DatabaseConfig   `yaml:"database"`
-	Redis      RedisConfig      `yaml:"redis"`
-	JWT        JWTConfig        `yaml:"jwt"`
-	Crawler    CrawlerConfig    `yaml:"crawler"`
-	NLPService NLPServiceConfig `yaml:"nlp_service"`
-	Log        LogConfig        `yaml:"log"`
+	Server         ServerConfig         `yaml:"server"`
+	Database       DatabaseConfig       `yaml:"database"`
+	Redis          RedisConfig          `yaml:"redis"`
+	JWT            JWTConfig            `yaml:"jwt"`
+	Crawler        CrawlerConfig        `yaml:"crawler"`
+	NLPService     NLPServiceConfig     `yaml:"nlp_service"`
+	Scoring        ScoringConfig        `yaml:"scoring"`
+	Recommendation RecommendationConfig `yaml:"recommendation"`
+	Calendar       CalendarConfig       `yaml:"calendar"`
+	Timetable      TimetableConfig      `yaml:"timetable"`
+	Mail           MailConfig           `yaml:"mail"`
+	Auth           AuthConfig           `yaml:"auth"`
+	RateLimit      RateLimitConfig      `yaml:"rate_limit"`
+	Privacy        PrivacyConfig        `yaml:"privacy"`
+	Users          UsersConfig          `yaml:"users"`
+	Storage        StorageConfig        `yaml:"storage"`
+	Notification   NotificationConfig   `yaml:"notification"`
+	Log            LogConfig            `yaml:"log"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port         int    `yaml:"port"`
-	Mode         string `yaml:"mode"`
-	ReadTimeout  int    `yaml:"read_timeout"`
-	WriteTimeout int    `yaml:"write_timeout"`
+	Port           int      `yaml:"port"`
+	Mode           string   `yaml:"mode"`
+	ReadTimeout    int      `yaml:"read_timeout"`
+	WriteTimeout   int      `yaml:"write_timeout"`
+	TrustedProxies []string `yaml:"trusted_proxies"` // 可信反向代理的IP/CIDR，仅信任其 X-Forwarded-For；为空时使用连接的对端地址
 }
 
 // DatabaseConfig 数据库配置
@@ -65,13 +78,19 @@ func (r *RedisConfig) GetAddr() string {
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	Secret      string `yaml:"secret"`
-	ExpireHours int    `yaml:"expire_hours"`
+	Secret              string `yaml:"secret"`
+	AccessExpireMinutes int    `yaml:"access_expire_minutes"` // 访问令牌有效期，应保持较短
+	RefreshExpireDays   int    `yaml:"refresh_expire_days"`   // 会话（刷新令牌）闲置多久后失效
 }
 
-// GetExpireDuration 返回过期时间
+// GetExpireDuration 返回访问令牌过期时间
 func (j *JWTConfig) GetExpireDuration() time.Duration {
-	return time.Duration(j.ExpireHours) * time.Hour
+	return time.Duration(j.AccessExpireMinutes) * time.Minute
+}
+
+// GetRefreshDuration 返回刷新令牌过期时间
+func (j *JWTConfig) GetRefreshDuration() time.Duration {
+	return time.Duration(j.RefreshExpireDays) * 24 * time.Hour
 }
 
 // CrawlerConfig 爬虫配置
@@ -90,8 +109,415 @@ type RateLimit struct {
 
 // NLPServiceConfig NLP服务配置
 type NLPServiceConfig struct {
+	URL                     string `yaml:"url"` // 为空时不启用简历解析与向量检索
+	Timeout                 int    `yaml:"timeout"`
+	BackfillIntervalMinutes int    `yaml:"backfill_interval_minutes"` // 补齐缺失向量的扫描间隔
+}
+
+// GetBackfillInterval 返回补齐缺失向量的扫描间隔
+func (n *NLPServiceConfig) GetBackfillInterval() time.Duration {
+	return time.Duration(n.BackfillIntervalMinutes) * time.Minute
+}
+
+// ScoringConfig 评分引擎配置
+type ScoringConfig struct {
+	Accessibility AccessibilityWeights `yaml:"accessibility"`
+	Relevance     RelevanceWeights     `yaml:"relevance"`
+}
+
+// AccessibilityWeights 匹配度各分项权重
+type AccessibilityWeights struct {
+	Eligibility float64 `yaml:"eligibility"`
+	SkillsMatch float64 `yaml:"skills_match"`
+	TimeCost    float64 `yaml:"time_cost"`
+}
+
+// RelevanceWeights 专业度各分项权重
+type RelevanceWeights struct {
+	MajorMatch        float64 `yaml:"major_match"`
+	SkillOverlap      float64 `yaml:"skill_overlap"`
+	CareerAlignment   float64 `yaml:"career_alignment"`
+	PeerParticipation float64 `yaml:"peer_participation"`
+}
+
+// DefaultScoringConfig 返回默认评分权重（配置文件未指定时使用）
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		Accessibility: AccessibilityWeights{
+			Eligibility: 0.5,
+			SkillsMatch: 0.3,
+			TimeCost:    0.2,
+		},
+		Relevance: RelevanceWeights{
+			MajorMatch:        0.35,
+			SkillOverlap:      0.25,
+			CareerAlignment:   0.25,
+			PeerParticipation: 0.15,
+		},
+	}
+}
+
+// Sum 返回匹配度权重之和
+func (w AccessibilityWeights) Sum() float64 {
+	return w.Eligibility + w.SkillsMatch + w.TimeCost
+}
+
+// Sum 返回专业度权重之和
+func (w RelevanceWeights) Sum() float64 {
+	return w.MajorMatch + w.SkillOverlap + w.CareerAlignment + w.PeerParticipation
+}
+
+// RecommendationConfig 个性化推荐配置
+type RecommendationConfig struct {
+	RelevanceWeight     float64 `yaml:"relevance_weight"`
+	AccessibilityWeight float64 `yaml:"accessibility_weight"`
+	UrgencyWeight       float64 `yaml:"urgency_weight"`
+	UrgencyWindowDays   int     `yaml:"urgency_window_days"` // 截止日期在此天数内开始计入紧迫度
+	CandidateLimit      int     `yaml:"candidate_limit"`     // 排序后保留的推荐数上限（全部候选都参与排序）
+	CacheTTL            int     `yaml:"cache_ttl"`           // seconds
+	FeedbackPenalty     float64 `yaml:"feedback_penalty"`    // 与负反馈机会完全相似时的最大降权比例 0-1
+}
+
+// GetCacheTTL 返回推荐结果缓存时间
+func (r *RecommendationConfig) GetCacheTTL() time.Duration {
+	return time.Duration(r.CacheTTL) * time.Second
+}
+
+// CalendarConfig 日历导出配置
+type CalendarConfig struct {
+	Timezone             string `yaml:"timezone"`               // 日程墙上时间所在时区（IANA名称）
+	DeadlineAlarmDays    []int  `yaml:"deadline_alarm_days"`    // 截止日期提前提醒天数
+	EventAlarmDays       []int  `yaml:"event_alarm_days"`       // 活动日期提前提醒天数
+	ScheduleAlarmMinutes int    `yaml:"schedule_alarm_minutes"` // 课程/日程开始前提醒分钟数，0表示不提醒
+	RefreshMinutes       int    `yaml:"refresh_minutes"`        // 建议订阅客户端的刷新间隔
+}
+
+// GetLocation 返回日历时区，Timezone已在加载时校验
+func (c *CalendarConfig) GetLocation() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// TimetableConfig 课表导入配置
+type TimetableConfig struct {
+	DefaultPeriods []ClassPeriod            `yaml:"default_periods"` // 未单独配置的学校使用的作息时间
+	Schools        map[string][]ClassPeriod `yaml:"schools"`         // 学校名称 -> 作息时间
+}
+
+// ClassPeriod 一节课的上下课时间
+type ClassPeriod struct {
+	Period int    `yaml:"period"`
+	Start  string `yaml:"start"` // HH:MM
+	End    string `yaml:"end"`   // HH:MM
+}
+
+// PeriodsFor 返回学校的作息时间，未配置时使用默认作息
+func (t *TimetableConfig) PeriodsFor(school string) []ClassPeriod {
+	if periods, ok := t.Schools[school]; ok && len(periods) > 0 {
+		return periods
+	}
+	return t.DefaultPeriods
+}
+
+// DefaultClassPeriods 返回默认作息时间（每节45分钟，上午4节、下午4节、晚上3节）
+func DefaultClassPeriods() []ClassPeriod {
+	return []ClassPeriod{
+		{Period: 1, Start: "08:00", End: "08:45"},
+		{Period: 2, Start: "08:55", End: "09:40"},
+		{Period: 3, Start: "10:00", End: "10:45"},
+		{Period: 4, Start: "10:55", End: "11:40"},
+		{Period: 5, Start: "14:00", End: "14:45"},
+		{Period: 6, Start: "14:55", End: "15:40"},
+		{Period: 7, Start: "16:00", End: "16:45"},
+		{Period: 8, Start: "16:55", End: "17:40"},
+		{Period: 9, Start: "19:00", End: "19:45"},
+		{Period: 10, Start: "19:55", End: "20:40"},
+		{Period: 11, Start: "20:50", End: "21:35"},
+	}
+}
+
+// validatePeriods 校验作息时间格式与先后顺序
+func validatePeriods(name string, periods []ClassPeriod) error {
+	seen := make(map[int]bool, len(periods))
+	for _, p := range periods {
+		start, err := time.Parse("15:04", p.Start)
+		if err != nil {
+			return fmt.Errorf("timetable %s period %d: invalid start %q", name, p.Period, p.Start)
+		}
+		end, err := time.Parse("15:04", p.End)
+		if err != nil {
+			return fmt.Errorf("timetable %s period %d: invalid end %q", name, p.Period, p.End)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("timetable %s period %d: end must be after start", name, p.Period)
+		}
+		if p.Period <= 0 || seen[p.Period] {
+			return fmt.Errorf("timetable %s: invalid or duplicate period %d", name, p.Period)
+		}
+		seen[p.Period] = true
+	}
+	return nil
+}
+
+// MailConfig 邮件发送配置
+type MailConfig struct {
+	From   string     `yaml:"from"`   // 发件人，如 "UniFocus <no-reply@unifocus.cn>"
+	Driver string     `yaml:"driver"` // smtp/file/console，后两者用于本地开发
+	Dir    string     `yaml:"dir"`    // file驱动写入.eml文件的目录
+	SMTP   SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig SMTP服务器配置
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	TLS      string `yaml:"tls"`     // starttls/tls/none
+	Timeout  int    `yaml:"timeout"` // seconds
+}
+
+// GetAddr 返回SMTP服务器地址
+func (s *SMTPConfig) GetAddr() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+// AuthConfig 账号安全配置
+type AuthConfig struct {
+	BaseURL                  string                `yaml:"base_url"`                   // 邮件与日历订阅链接的站点地址，如 https://unifocus.cn（/api 由前端代理到后端）
+	RequireEmailVerification bool                  `yaml:"require_email_verification"` // 未验证邮箱的用户不能登录
+	VerifyExpireHours        int                   `yaml:"verify_expire_hours"`        // 邮箱验证链接有效期
+	ResetExpireMinutes       int                   `yaml:"reset_expire_minutes"`       // 重置密码链接有效期
+	Login                    LoginProtectionConfig `yaml:"login"`
+	SSO                      []SSOProviderConfig   `yaml:"sso"` // 校园统一身份认证，每个学校一项
+}
+
+// GetVerifyDuration 返回邮箱验证链接有效期
+func (a *AuthConfig) GetVerifyDuration() time.Duration {
+	return time.Duration(a.VerifyExpireHours) * time.Hour
+}
+
+// GetResetDuration 返回重置密码链接有效期
+func (a *AuthConfig) GetResetDuration() time.Duration {
+	return time.Duration(a.ResetExpireMinutes) * time.Minute
+}
+
+// LoginProtectionConfig 登录防暴力破解配置
+// 窗口内失败超过 FreeAttempts 次后，每次重试需等待的时间逐次翻倍（上限 MaxDelaySeconds）；
+// 账号失败达到 MaxFailures 次或同一IP失败达到 IPMaxFailures 次后锁定 LockoutMinutes 分钟
+type LoginProtectionConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	WindowMinutes   int  `yaml:"window_minutes"`    // 统计失败次数的滑动窗口
+	FreeAttempts    int  `yaml:"free_attempts"`     // 不需要等待的失败次数
+	IPFreeAttempts  int  `yaml:"ip_free_attempts"`  // 同一IP不需要等待的失败次数（校园网出口IP为多人共用）
+	MaxDelaySeconds int  `yaml:"max_delay_seconds"` // 渐进等待的上限
+	MaxFailures     int  `yaml:"max_failures"`      // 账号锁定阈值
+	IPMaxFailures   int  `yaml:"ip_max_failures"`   // IP锁定阈值
+	LockoutMinutes  int  `yaml:"lockout_minutes"`   // 锁定时长
+}
+
+// GetWindow 返回失败统计窗口
+func (l *LoginProtectionConfig) GetWindow() time.Duration {
+	return time.Duration(l.WindowMinutes) * time.Minute
+}
+
+// GetLockout 返回锁定时长
+func (l *LoginProtectionConfig) GetLockout() time.Duration {
+	return time.Duration(l.LockoutMinutes) * time.Minute
+}
+
+// SSO协议
+const (
+	SSOProtocolOIDC = "oidc"
+	SSOProtocolCAS  = "cas"
+)
+
+// SSOProviderConfig 学校的统一身份认证服务（OpenID Connect 或 CAS 2.0/3.0）
+type SSOProviderConfig struct {
+	ID       string `yaml:"id"`       // 路由中的标识，如 pku
+	Name     string `yaml:"name"`     // 登录按钮上显示的名称
+	School   string `yaml:"school"`   // 自动创建的用户所属学校
+	Protocol string `yaml:"protocol"` // oidc, cas
+
+	// OpenID Connect（授权码模式）
+	Issuer       string   `yaml:"issuer"` // 通过 {issuer}/.well-known/openid-configuration 发现各端点
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	Scopes       []string `yaml:"scopes"` // 默认 openid email profile
+
+	// CAS
+	CASURL     string `yaml:"cas_url"`     // CAS服务地址，如 https://cas.example.edu.cn/cas
+	CASVersion string `yaml:"cas_version"` // 2.0, 3.0（默认）
+
+	TrustEmail   bool                `yaml:"trust_email"`   // 身份提供方未声明 email_verified 时视为已验证（CAS没有该属性）
+	EmailDomains []string            `yaml:"email_domains"` // 学校邮箱域名（含子域名），trust_email 只对这些域名的邮箱生效
+	Attributes   SSOAttributeMapping `yaml:"attributes"`
+}
+
+// SSOAttributeMapping 身份属性名映射，未配置时使用默认名称
+type SSOAttributeMapping struct {
+	Username string `yaml:"username"` // 默认 OIDC 为 preferred_username，CAS 为登录名
+	Email    string `yaml:"email"`    // 默认 email
+	Name     string `yaml:"name"`     // 默认 name
+	Major    string `yaml:"major"`    // 默认 major
+	Grade    string `yaml:"grade"`    // 默认 grade；值为年级(1-4)或入学年份
+}
+
+// validateSSOProvider 校验身份认证服务配置并补全默认值
+func validateSSOProvider(p *SSOProviderConfig) error {
+	if p.ID == "" {
+		return fmt.Errorf("sso provider id cannot be empty")
+	}
+	if p.Name == "" {
+		p.Name = p.ID
+	}
+	switch p.Protocol {
+	case SSOProtocolOIDC:
+		if p.Issuer == "" || p.ClientID == "" {
+			return fmt.Errorf("sso provider %s: oidc requires issuer and client_id", p.ID)
+		}
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "email", "profile"}
+		}
+	case SSOProtocolCAS:
+		if p.CASURL == "" {
+			return fmt.Errorf("sso provider %s: cas requires cas_url", p.ID)
+		}
+		if p.CASVersion == "" {
+			p.CASVersion = "3.0"
+		}
+		if p.CASVersion != "2.0" && p.CASVersion != "3.0" {
+			return fmt.Errorf("sso provider %s: invalid cas_version %s", p.ID, p.CASVersion)
+		}
+	default:
+		return fmt.Errorf("sso provider %s: invalid protocol %s", p.ID, p.Protocol)
+	}
+
+	domains := make([]string, 0, len(p.EmailDomains))
+	for _, d := range p.EmailDomains {
+		if d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@")); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	p.EmailDomains = domains
+	if p.TrustEmail && len(p.EmailDomains) == 0 {
+		return fmt.Errorf("sso provider %s: trust_email requires email_domains", p.ID)
+	}
+
+	if p.Attributes.Email == "" {
+		p.Attributes.Email = "email"
+	}
+	if p.Attributes.Name == "" {
+		p.Attributes.Name = "name"
+	}
+	if p.Attributes.Major == "" {
+		p.Attributes.Major = "major"
+	}
+	if p.Attributes.Grade == "" {
+		p.Attributes.Grade = "grade"
+	}
+	if p.Attributes.Username == "" && p.Protocol == SSOProtocolOIDC {
+		p.Attributes.Username = "preferred_username"
+	}
+	return nil
+}
+
+// RateLimitConfig 按IP/用户的请求频率限制（滑动窗口）
+type RateLimitConfig struct {
+	Enabled              bool `yaml:"enabled"`
+	AuthPerMinute        int  `yaml:"auth_per_minute"`         // 每个IP每分钟可调用认证接口的次数
+	PasswordResetPerHour int  `yaml:"password_reset_per_hour"` // 每个IP每小时可申请重置密码/重发验证邮件的次数
+	UploadPerHour        int  `yaml:"upload_per_hour"`         // 每个用户每小时可上传/导入文件的次数
+}
+
+// PrivacyConfig 个人数据导出与注销配置
+type PrivacyConfig struct {
+	DeletionGraceDays    int `yaml:"deletion_grace_days"`    // 申请注销后保留多少天，期间可撤销
+	PurgeIntervalMinutes int `yaml:"purge_interval_minutes"` // 清除到期账号的扫描间隔
+}
+
+// GetPurgeInterval 返回清除到期账号的扫描间隔
+func (p *PrivacyConfig) GetPurgeInterval() time.Duration {
+	return time.Duration(p.PurgeIntervalMinutes) * time.Minute
+}
+
+// UsersConfig 用户资料配置
+type UsersConfig struct {
+	GradeRolloverEnabled bool `yaml:"grade_rollover_enabled"`
+	GradeRolloverMonth   int  `yaml:"grade_rollover_month"` // 每学年升级的日期（calendar.timezone），默认9月1日
+	GradeRolloverDay     int  `yaml:"grade_rollover_day"`
+	AvatarMaxSizeKB      int  `yaml:"avatar_max_size_kb"`   // 头像文件大小上限
+	AvatarMaxDimension   int  `yaml:"avatar_max_dimension"` // 头像宽高上限（像素）
+}
+
+// GetAvatarMaxSize 返回头像文件大小上限（字节）
+func (u *UsersConfig) GetAvatarMaxSize() int64 {
+	return int64(u.AvatarMaxSizeKB) << 10
+}
+
+// StorageConfig 文件存储配置（头像等用户上传的文件）
+type StorageConfig struct {
+	Driver    string `yaml:"driver"`     // local, s3
+	LocalDir  string `yaml:"local_dir"`  // local：文件保存目录
+	PublicURL string `yaml:"public_url"` // 文件的访问地址前缀；local 时由本服务在该路径下提供文件，s3 时为空则使用存储桶地址
+
+	// S3兼容对象存储（AWS S3、MinIO、阿里云OSS、腾讯云COS等）
+	Endpoint        string `yaml:"endpoint"` // 服务地址，如 https://s3.ap-east-1.amazonaws.com、http://minio:9000
+	Region          string `yaml:"region"`   // 签名使用的区域，默认 us-east-1
+	Bucket          string `yaml:"bucket"`   // 存储桶，需允许公开读取或通过 public_url 的CDN访问
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	PathStyle       bool   `yaml:"path_style"` // 使用 {endpoint}/{bucket}/{key} 形式的地址（MinIO需要）
+}
+
+// NotificationConfig 通知推送配置
+type NotificationConfig struct {
+	Channels []string       `yaml:"channels"` // 默认推送渠道：in_app/email/webhook
+	Reminder ReminderConfig `yaml:"reminder"`
+	Digest   DigestConfig   `yaml:"digest"`
+	Webhook  WebhookConfig  `yaml:"webhook"`
+}
+
+// ReminderConfig 截止日期提醒配置
+type ReminderConfig struct {
+	Enabled         bool  `yaml:"enabled"`
+	IntervalMinutes int   `yaml:"interval_minutes"` // 扫描间隔
+	OffsetDays      []int `yaml:"offset_days"`      // 截止前多少天提醒
+}
+
+// GetInterval 返回提醒扫描间隔
+func (r *ReminderConfig) GetInterval() time.Duration {
+	return time.Duration(r.IntervalMinutes) * time.Minute
+}
+
+// DigestConfig 每日/每周摘要邮件配置
+type DigestConfig struct {
+	Enabled          bool    `yaml:"enabled"`
+	IntervalMinutes  int     `yaml:"interval_minutes"`  // 扫描间隔
+	DefaultFrequency string  `yaml:"default_frequency"` // 用户未设置时的频率：daily/weekly/off
+	SendHour         int     `yaml:"send_hour"`         // 发送时刻（calendar.timezone中的小时）
+	WeeklyDay        int     `yaml:"weekly_day"`        // 每周摘要的发送日：1-7（周一至周日）
+	MaxItems         int     `yaml:"max_items"`         // 每个板块最多列出的条目数
+	MinScore         float64 `yaml:"min_score"`         // 新机会进入摘要的最低推荐得分 0-100
+	DeadlineDays     int     `yaml:"deadline_days"`     // 列出多少天内截止的已收藏机会
+	BaseURL          string  `yaml:"base_url"`          // 邮件中链接的站点地址，如 https://unifocus.cn
+	DryRun           bool    `yaml:"dry_run"`           // 不发送邮件，写入DryRunDir下的.eml文件
+	DryRunDir        string  `yaml:"dry_run_dir"`
+}
+
+// GetInterval 返回摘要扫描间隔
+func (d *DigestConfig) GetInterval() time.Duration {
+	return time.Duration(d.IntervalMinutes) * time.Minute
+}
+
+// WebhookConfig Webhook推送配置
+type WebhookConfig struct {
 	URL     string `yaml:"url"`
-	Timeout int    `yaml:"timeout"`
+	Secret  string `yaml:"secret"`  // 非空时以HMAC-SHA256签名请求体（X-UniFocus-Signature）
+	Timeout int    `yaml:"timeout"` // seconds
 }
 
 // LogConfig 日志配置
@@ -173,6 +599,275 @@ func (c *Config) validate() error {
 	if c.JWT.Secret == "" {
 		return fmt.Errorf("JWT secret cannot be empty")
 	}
+	if c.JWT.AccessExpireMinutes <= 0 {
+		c.JWT.AccessExpireMinutes = 15
+	}
+	if c.JWT.RefreshExpireDays <= 0 {
+		c.JWT.RefreshExpireDays = 30
+	}
+
+	// 未配置评分权重时使用默认值
+	if c.Scoring.Accessibility.Sum() == 0 {
+		c.Scoring.Accessibility = DefaultScoringConfig().Accessibility
+	}
+	if c.Scoring.Relevance.Sum() == 0 {
+		c.Scoring.Relevance = DefaultScoringConfig().Relevance
+	}
+	a, r := c.Scoring.Accessibility, c.Scoring.Relevance
+	if a.Eligibility < 0 || a.SkillsMatch < 0 || a.TimeCost < 0 ||
+		r.MajorMatch < 0 || r.SkillOverlap < 0 || r.CareerAlignment < 0 || r.PeerParticipation < 0 {
+		return fmt.Errorf("scoring weights cannot be negative")
+	}
+
+	if c.Recommendation.RelevanceWeight+c.Recommendation.AccessibilityWeight+c.Recommendation.UrgencyWeight == 0 {
+		c.Recommendation.RelevanceWeight = 0.5
+		c.Recommendation.AccessibilityWeight = 0.3
+		c.Recommendation.UrgencyWeight = 0.2
+	}
+	if c.Recommendation.UrgencyWindowDays <= 0 {
+		c.Recommendation.UrgencyWindowDays = 30
+	}
+	if c.Recommendation.CandidateLimit <= 0 {
+		c.Recommendation.CandidateLimit = 500
+	}
+	if c.Recommendation.CacheTTL <= 0 {
+		c.Recommendation.CacheTTL = 600
+	}
+	if c.Recommendation.FeedbackPenalty <= 0 {
+		c.Recommendation.FeedbackPenalty = 0.5
+	}
+	if c.Recommendation.FeedbackPenalty > 1 {
+		return fmt.Errorf("recommendation feedback_penalty must be between 0 and 1")
+	}
+
+	if c.Calendar.Timezone == "" {
+		c.Calendar.Timezone = "Asia/Shanghai"
+	}
+	if _, err := time.LoadLocation(c.Calendar.Timezone); err != nil {
+		return fmt.Errorf("invalid calendar timezone %q: %w", c.Calendar.Timezone, err)
+	}
+	if c.Calendar.DeadlineAlarmDays == nil {
+		c.Calendar.DeadlineAlarmDays = []int{3, 1}
+	}
+	if c.Calendar.EventAlarmDays == nil {
+		c.Calendar.EventAlarmDays = []int{1}
+	}
+	if c.Calendar.ScheduleAlarmMinutes < 0 {
+		return fmt.Errorf("calendar schedule_alarm_minutes cannot be negative")
+	}
+	if c.Calendar.RefreshMinutes <= 0 {
+		c.Calendar.RefreshMinutes = 60
+	}
+
+	c.Auth.BaseURL = strings.TrimRight(c.Auth.BaseURL, "/")
+	if c.Auth.VerifyExpireHours <= 0 {
+		c.Auth.VerifyExpireHours = 48
+	}
+	if c.Auth.ResetExpireMinutes <= 0 {
+		c.Auth.ResetExpireMinutes = 60
+	}
+	login := &c.Auth.Login
+	if login.WindowMinutes <= 0 {
+		login.WindowMinutes = 15
+	}
+	if login.FreeAttempts <= 0 {
+		login.FreeAttempts = 3
+	}
+	if login.IPFreeAttempts <= 0 {
+		login.IPFreeAttempts = 15
+	}
+	if login.MaxDelaySeconds <= 0 {
+		login.MaxDelaySeconds = 60
+	}
+	if login.MaxFailures <= 0 {
+		login.MaxFailures = 10
+	}
+	if login.IPMaxFailures <= 0 {
+		login.IPMaxFailures = 50
+	}
+	if login.LockoutMinutes <= 0 {
+		login.LockoutMinutes = 15
+	}
+
+	seenSSO := make(map[string]bool, len(c.Auth.SSO))
+	for i := range c.Auth.SSO {
+		if err := validateSSOProvider(&c.Auth.SSO[i]); err != nil {
+			return err
+		}
+		if seenSSO[c.Auth.SSO[i].ID] {
+			return fmt.Errorf("duplicate sso provider id: %s", c.Auth.SSO[i].ID)
+		}
+		seenSSO[c.Auth.SSO[i].ID] = true
+	}
+
+	if c.RateLimit.AuthPerMinute <= 0 {
+		c.RateLimit.AuthPerMinute = 20
+	}
+	if c.RateLimit.PasswordResetPerHour <= 0 {
+		c.RateLimit.PasswordResetPerHour = 5
+	}
+	if c.RateLimit.UploadPerHour <= 0 {
+		c.RateLimit.UploadPerHour = 30
+	}
+
+	if c.NLPService.Timeout <= 0 {
+		c.NLPService.Timeout = 60
+	}
+	if c.NLPService.BackfillIntervalMinutes <= 0 {
+		c.NLPService.BackfillIntervalMinutes = 10
+	}
+
+	if c.Privacy.DeletionGraceDays <= 0 {
+		c.Privacy.DeletionGraceDays = 30
+	}
+	if c.Privacy.PurgeIntervalMinutes <= 0 {
+		c.Privacy.PurgeIntervalMinutes = 60
+	}
+
+	if c.Users.GradeRolloverMonth == 0 {
+		c.Users.GradeRolloverMonth = 9
+	}
+	if c.Users.GradeRolloverDay == 0 {
+		c.Users.GradeRolloverDay = 1
+	}
+	if c.Users.GradeRolloverMonth < 1 || c.Users.GradeRolloverMonth > 12 || c.Users.GradeRolloverDay < 1 || c.Users.GradeRolloverDay > 28 {
+		return fmt.Errorf("users grade rollover date must be a valid month and a day between 1 and 28")
+	}
+	if c.Users.AvatarMaxSizeKB <= 0 {
+		c.Users.AvatarMaxSizeKB = 2048
+	}
+	if c.Users.AvatarMaxDimension <= 0 {
+		c.Users.AvatarMaxDimension = 4096
+	}
+
+	if c.Storage.Driver == "" {
+		c.Storage.Driver = "local"
+	}
+	c.Storage.PublicURL = strings.TrimRight(c.Storage.PublicURL, "/")
+	switch c.Storage.Driver {
+	case "local":
+		if c.Storage.LocalDir == "" {
+			c.Storage.LocalDir = "uploads"
+		}
+		if c.Storage.PublicURL == "" {
+			c.Storage.PublicURL = "/uploads"
+		}
+	case "s3":
+		if c.Storage.Endpoint == "" || c.Storage.Bucket == "" {
+			return fmt.Errorf("s3 storage requires endpoint and bucket")
+		}
+		if c.Storage.AccessKeyID == "" || c.Storage.SecretAccessKey == "" {
+			return fmt.Errorf("s3 storage requires access_key_id and secret_access_key")
+		}
+		if c.Storage.Region == "" {
+			c.Storage.Region = "us-east-1"
+		}
+	default:
+		return fmt.Errorf("unsupported storage driver: %s", c.Storage.Driver)
+	}
+
+	if c.Mail.Driver == "" {
+		c.Mail.Driver = "smtp"
+	}
+	switch c.Mail.Driver {
+	case "smtp", "console":
+	case "file":
+		if c.Mail.Dir == "" {
+			c.Mail.Dir = "tmp/mail"
+		}
+	default:
+		return fmt.Errorf("invalid mail driver: %s", c.Mail.Driver)
+	}
+	if c.Mail.SMTP.Port == 0 {
+		c.Mail.SMTP.Port = 587
+	}
+	if c.Mail.SMTP.TLS == "" {
+		c.Mail.SMTP.TLS = "starttls"
+	}
+	if c.Mail.SMTP.TLS != "starttls" && c.Mail.SMTP.TLS != "tls" && c.Mail.SMTP.TLS != "none" {
+		return fmt.Errorf("invalid smtp tls mode: %s", c.Mail.SMTP.TLS)
+	}
+	if c.Mail.SMTP.Timeout <= 0 {
+		c.Mail.SMTP.Timeout = 10
+	}
+
+	if len(c.Notification.Channels) == 0 {
+		c.Notification.Channels = []string{"in_app"}
+	}
+	for _, channel := range c.Notification.Channels {
+		switch channel {
+		case "in_app":
+		case "email":
+			if c.Mail.Driver == "smtp" && c.Mail.SMTP.Host == "" {
+				return fmt.Errorf("notification channel email requires mail.smtp.host")
+			}
+		case "webhook":
+			if c.Notification.Webhook.URL == "" {
+				return fmt.Errorf("notification channel webhook requires notification.webhook.url")
+			}
+		default:
+			return fmt.Errorf("invalid notification channel: %s", channel)
+		}
+	}
+	if c.Notification.Reminder.IntervalMinutes <= 0 {
+		c.Notification.Reminder.IntervalMinutes = 15
+	}
+	if len(c.Notification.Reminder.OffsetDays) == 0 {
+		c.Notification.Reminder.OffsetDays = []int{7, 3, 1}
+	}
+	for _, days := range c.Notification.Reminder.OffsetDays {
+		if days < 0 {
+			return fmt.Errorf("notification reminder offset_days cannot be negative")
+		}
+	}
+	if c.Notification.Webhook.Timeout <= 0 {
+		c.Notification.Webhook.Timeout = 10
+	}
+
+	digest := &c.Notification.Digest
+	if digest.IntervalMinutes <= 0 {
+		digest.IntervalMinutes = 30
+	}
+	if digest.DefaultFrequency == "" {
+		digest.DefaultFrequency = "weekly"
+	}
+	if digest.DefaultFrequency != "daily" && digest.DefaultFrequency != "weekly" && digest.DefaultFrequency != "off" {
+		return fmt.Errorf("invalid digest default_frequency: %s", digest.DefaultFrequency)
+	}
+	if digest.SendHour < 0 || digest.SendHour > 23 {
+		return fmt.Errorf("digest send_hour must be between 0 and 23")
+	}
+	if digest.WeeklyDay == 0 {
+		digest.WeeklyDay = 1
+	}
+	if digest.WeeklyDay < 1 || digest.WeeklyDay > 7 {
+		return fmt.Errorf("digest weekly_day must be between 1 and 7")
+	}
+	if digest.MaxItems <= 0 {
+		digest.MaxItems = 10
+	}
+	if digest.DeadlineDays <= 0 {
+		digest.DeadlineDays = 7
+	}
+	digest.BaseURL = strings.TrimRight(digest.BaseURL, "/")
+	if digest.DryRun && digest.DryRunDir == "" {
+		digest.DryRunDir = "tmp/digests"
+	}
+	if digest.Enabled && !digest.DryRun && c.Mail.SMTP.Host == "" {
+		return fmt.Errorf("notification digest requires mail.smtp.host (or dry_run)")
+	}
+
+	if len(c.Timetable.DefaultPeriods) == 0 {
+		c.Timetable.DefaultPeriods = DefaultClassPeriods()
+	}
+	if err := validatePeriods("default", c.Timetable.DefaultPeriods); err != nil {
+		return err
+	}
+	for school, periods := range c.Timetable.Schools {
+		if err := validatePeriods(school, periods); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,89 @@
+// Package digest renders the periodic digest email.
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var (
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/digest.html.tmpl"))
+	textTemplate = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/digest.txt.tmpl"))
+)
+
+// Data is the content of one digest email; dates are preformatted for display
+type Data struct {
+	UserName       string
+	Period         string // 每日/每周
+	Since          string
+	Matches        []Match
+	Deadlines      []Deadline
+	Changes        []Change
+	DashboardURL   string
+	UnsubscribeURL string
+}
+
+// Match is a newly published opportunity matching the user's profile
+type Match struct {
+	OpportunityID int64
+	Title         string
+	Organizer     string
+	Deadline      string // 空表示无截止日期
+	Score         int
+	URL           string
+}
+
+// Deadline is a saved or applied opportunity closing soon
+type Deadline struct {
+	Title    string
+	Deadline string
+	DaysLeft int
+	Status   string
+	URL      string
+}
+
+// Change is an update to an opportunity the user follows
+type Change struct {
+	Title string
+	Body  string
+	Time  string
+	URL   string
+}
+
+// Empty reports whether the digest has nothing to tell
+func (d *Data) Empty() bool {
+	return len(d.Matches) == 0 && len(d.Deadlines) == 0 && len(d.Changes) == 0
+}
+
+// Subject returns the email subject
+func (d *Data) Subject() string {
+	subject := fmt.Sprintf("UniFocus %s摘要", d.Period)
+	switch {
+	case len(d.Matches) > 0 && len(d.Deadlines) > 0:
+		return fmt.Sprintf("%s：%d个新机会，%d个即将截止", subject, len(d.Matches), len(d.Deadlines))
+	case len(d.Matches) > 0:
+		return fmt.Sprintf("%s：%d个新机会", subject, len(d.Matches))
+	case len(d.Deadlines) > 0:
+		return fmt.Sprintf("%s：%d个即将截止", subject, len(d.Deadlines))
+	default:
+		return fmt.Sprintf("%s：%d条机会动态", subject, len(d.Changes))
+	}
+}
+
+// Render returns the plain-text and HTML bodies
+func Render(d *Data) (string, string, error) {
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, d); err != nil {
+		return "", "", fmt.Errorf("failed to render text digest: %w", err)
+	}
+	if err := htmlTemplate.Execute(&html, d); err != nil {
+		return "", "", fmt.Errorf("failed to render html digest: %w", err)
+	}
+	return text.String(), html.String(), nil
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// 账号令牌用途
+const (
+	AccountTokenVerifyEmail   = "verify_email"
+	AccountTokenResetPassword = "reset_password"
+)
+
+// VerifyEmailRequest 邮箱验证请求
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPasswordRequest 忘记密码请求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest 重置密码请求
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// AccountDeletion 账号注销申请，宽限期内可撤销
+type AccountDeletion struct {
+	UserID       int64     `json:"-" db:"user_id"`
+	RequestedAt  time.Time `json:"requested_at" db:"requested_at"`
+	ScheduledFor time.Time `json:"scheduled_for" db:"scheduled_for"` // 到期后删除账号及全部数据
+}
+
+// DeleteAccountRequest 注销账号请求，需要确认当前密码
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// 账号状态，用于管理员筛选用户
+const (
+	UserStatusActive    = "active"    // 正常
+	UserStatusSuspended = "suspended" // 已停用
+)
+
+// AdminUserFilter 管理员搜索用户的筛选条件
+type AdminUserFilter struct {
+	Email  string `form:"email"` // 按邮箱模糊匹配
+	School string `form:"school"`
+	Major  string `form:"major"`
+	Role   string `form:"role" binding:"omitempty,oneof=student publisher school_admin admin"`
+	Status string `form:"status" binding:"omitempty,oneof=active suspended"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// UserActivity 用户活跃情况概览
+type UserActivity struct {
+	Opportunities  map[string]int64 `json:"opportunities"`   // 各状态下收藏的机会数
+	Schedules      int              `json:"schedules"`       // 日程数
+	ActiveSessions int              `json:"active_sessions"` // 未过期的登录会话数
+	APIKeys        int              `json:"api_keys"`        // 有效的个人API密钥数
+	LastActiveAt   *time.Time       `json:"last_active_at"`  // 最近一次使用会话的时间
+}
+
+// AdminUserDetail 管理员查看的用户详情
+type AdminUserDetail struct {
+	User            *User            `json:"user"`
+	Profile         *UserProfile     `json:"profile,omitempty"`
+	Activity        *UserActivity    `json:"activity"`
+	PendingDeletion *AccountDeletion `json:"pending_deletion,omitempty"` // 尚未到期的注销申请
+	RecentAudit     []*AuditLog      `json:"recent_audit"`               // 最近的审计日志
+}
+
+// SuspendUserRequest 停用账号请求
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
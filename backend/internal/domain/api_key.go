@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// APIKeyPrefix 个人访问令牌的前缀，用于与JWT区分
+const APIKeyPrefix = "ufk_"
+
+// 个人访问令牌的权限范围
+const (
+	ScopeOpportunitiesRead   = "opportunities:read"   // 查看我的机会、推荐与时间冲突
+	ScopeOpportunitiesSubmit = "opportunities:submit" // 收藏机会、更新状态；发布者可提交新机会
+	ScopeProfileRead         = "profile:read"         // 查看我的画像
+)
+
+// APIKeyScopes 全部权限范围
+var APIKeyScopes = []string{ScopeOpportunitiesRead, ScopeOpportunitiesSubmit, ScopeProfileRead}
+
+// APIKey 个人访问令牌（仅保存摘要，明文只在创建时返回一次）
+type APIKey struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     int64      `json:"-" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"` // 令牌开头几位，便于用户辨认
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip" db:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt  *time.Time `json:"-" db:"revoked_at"`
+}
+
+// HasScope 令牌是否具有权限范围
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKeyRequest 创建个人访问令牌请求
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=opportunities:read opportunities:submit profile:read"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"` // 默认90天
+}
+
+// CreateAPIKeyResponse 创建结果，Token 只返回这一次
+type CreateAPIKeyResponse struct {
+	*APIKey
+	Token string `json:"token"`
+}
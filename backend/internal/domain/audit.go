@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// 审计日志动作
+const (
+	AuditAccountExported          = "account.exported"           // 导出个人数据
+	AuditAccountDeletionRequested = "account.deletion_requested" // 申请注销账号
+	AuditAccountDeletionCancelled = "account.deletion_cancelled" // 撤销注销申请
+	AuditAccountDeleted           = "account.deleted"            // 宽限期满，账号及数据已删除
+
+	AuditAdminUserViewed          = "admin.user_viewed"           // 管理员查看用户详情
+	AuditAdminUserSuspended       = "admin.user_suspended"        // 管理员停用账号
+	AuditAdminUserReactivated     = "admin.user_reactivated"      // 管理员恢复账号
+	AuditAdminPasswordResetForced = "admin.password_reset_forced" // 管理员强制重置密码
+	AuditAdminRoleChanged         = "admin.role_changed"          // 管理员修改角色
+	AuditAdminTokensRevoked       = "admin.tokens_revoked"        // 管理员吊销全部令牌
+)
+
+// AuditLog 审计日志；账号删除后仍保留，不含个人数据
+type AuditLog struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    int64     `json:"user_id" db:"user_id"`   // 被操作的账号
+	ActorID   *int64    `json:"actor_id" db:"actor_id"` // 操作者：本人或管理员，系统任务为空
+	Action    string    `json:"action" db:"action"`
+	Detail    JSONB     `json:"detail,omitempty" db:"detail"`
+	IP        string    `json:"ip,omitempty" db:"ip"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
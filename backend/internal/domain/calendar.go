@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// CalendarFeed 日历订阅令牌（仅保存摘要）
+type CalendarFeed struct {
+	ID             int64      `json:"id" db:"id"`
+	UserID         int64      `json:"user_id" db:"user_id"`
+	TokenHash      string     `json:"-" db:"token_hash"`
+	LastAccessedAt *time.Time `json:"last_accessed_at" db:"last_accessed_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CalendarFeedToken 新生成的订阅令牌，明文只在生成时返回一次
+type CalendarFeedToken struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"` // 可直接添加到手机日历的订阅地址
+	CreatedAt time.Time `json:"created_at"`
+}
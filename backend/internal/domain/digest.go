@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// 摘要邮件频率
+const (
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+	DigestFrequencyOff    = "off"
+)
+
+// DigestPreference 用户的摘要邮件偏好
+type DigestPreference struct {
+	UserID     int64      `json:"user_id"`
+	Frequency  string     `json:"frequency"`  // daily/weekly/off（生效值）
+	IsDefault  bool       `json:"is_default"` // 用户未设置，使用系统默认频率
+	LastSentAt *time.Time `json:"last_sent_at"`
+}
+
+// UpdateDigestPreferenceRequest 修改摘要邮件频率请求
+type UpdateDigestPreferenceRequest struct {
+	Frequency string `json:"frequency" binding:"required,oneof=daily weekly off"`
+}
+
+// DigestRecipient 待发送摘要的用户
+type DigestRecipient struct {
+	User       *User
+	Frequency  string // 用户设置的频率，空表示使用默认值
+	LastSentAt *time.Time
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// 通知类型
+const (
+	NotificationTypeDeadlineReminder = "deadline_reminder" // 收藏/报名的机会临近截止
+	NotificationTypeStatusChange     = "status_change"     // 收藏/报名的机会被修改或下架
+	NotificationTypeNewMatch         = "new_match"         // 新发布的机会与用户画像匹配
+	NotificationTypeBatch            = "batch"             // 免打扰结束后合并发送的多条推送
+)
+
+// 推送渠道
+const (
+	NotificationChannelInApp   = "in_app"
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+)
+
+// Notification 通知实体（站内通知持久化，其他渠道使用相同内容）
+type Notification struct {
+	ID            int64      `json:"id" db:"id"`
+	UserID        int64      `json:"user_id" db:"user_id"`
+	Type          string     `json:"type" db:"type"`
+	Title         string     `json:"title" db:"title"`
+	Body          string     `json:"body" db:"body"`
+	URL           string     `json:"url" db:"url"`
+	OpportunityID *int64     `json:"opportunity_id,omitempty" db:"opportunity_id"`
+	Data          JSONB      `json:"data,omitempty" db:"data"`
+	ReadAt        *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NotificationFilter 通知列表筛选条件
+type NotificationFilter struct {
+	Unread bool   `form:"unread"` // 仅未读
+	Type   string `form:"type" binding:"omitempty,oneof=deadline_reminder status_change new_match"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// DueReminder 临近截止、待提醒的用户机会
+type DueReminder struct {
+	UserOpportunityID int64
+	Status            string
+	User              *User
+	Opportunity       *Opportunity
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// MaxReminderOffsetDays 截止提醒最多提前的天数
+const MaxReminderOffsetDays = 30
+
+// NotificationTypes 用户可单独设置的通知类型
+var NotificationTypes = []string{NotificationTypeDeadlineReminder, NotificationTypeStatusChange, NotificationTypeNewMatch}
+
+// NotificationPreferences 用户通知偏好（存储于 user_profiles.notification_preferences）
+// 未设置的项使用系统默认值
+type NotificationPreferences struct {
+	Types              map[string]*NotificationTypePreference `json:"types"`                // 按通知类型设置，键为 deadline_reminder/status_change/new_match
+	ReminderOffsetDays []int                                  `json:"reminder_offset_days"` // 截止前多少天提醒，为空使用系统默认
+	QuietHours         QuietHours                             `json:"quiet_hours"`
+}
+
+// NotificationTypePreference 某类通知的开关与渠道
+type NotificationTypePreference struct {
+	Enabled  bool     `json:"enabled"`
+	Channels []string `json:"channels"` // in_app/email/webhook
+}
+
+// QuietHours 免打扰时段，期间站内通知只入库不实时推送，其他渠道的推送暂存并在结束后合并发送
+// Start 晚于 End 表示跨越午夜（如 22:00-07:00）
+type QuietHours struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM
+	Timezone string `json:"timezone"` // IANA时区，为空使用系统时区
+}
+
+// Value 实现 NotificationPreferences 的 driver.Valuer 接口
+func (p NotificationPreferences) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan 实现 NotificationPreferences 的 sql.Scanner 接口
+func (p *NotificationPreferences) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal NotificationPreferences value: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, p)
+}
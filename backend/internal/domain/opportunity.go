@@ -44,6 +44,7 @@ type Opportunity struct {
 	ViewCount         int          `json:"view_count" db:"view_count"`
 	SaveCount         int          `json:"save_count" db:"save_count"`
 
+	CreatedBy *int64    `json:"created_by,omitempty" db:"created_by"` // 发布者，爬虫导入的为空
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -147,3 +148,9 @@ type OpportunityFilter struct {
 	Limit            int        `form:"limit"`
 	Offset           int        `form:"offset"`
 }
+
+// SimilarOpportunity 语义相似的机会
+type SimilarOpportunity struct {
+	Opportunity *Opportunity `json:"opportunity"`
+	Similarity  float64      `json:"similarity"` // 余弦相似度 -1~1
+}
@@ -0,0 +1,30 @@
+package domain
+
+// RecommendationItem 推荐流中的单个机会
+type RecommendationItem struct {
+	Opportunity        *Opportunity  `json:"opportunity"`
+	Score              float64       `json:"score"`               // 综合得分 0-100
+	AccessibilityScore float64       `json:"accessibility_score"` // 匹配度 0-100
+	RelevanceScore     float64       `json:"relevance_score"`     // 专业度 0-100
+	Urgency            float64       `json:"urgency"`             // 截止紧迫度 0-1
+	FeedbackPenalty    float64       `json:"feedback_penalty"`    // 因相似负反馈被扣减的比例 0-1
+	Reasons            []ScoreReason `json:"reasons"`             // 贡献最大的评分分项
+}
+
+// ScoreReason 评分分项对综合得分的贡献
+type ScoreReason struct {
+	Component    string  `json:"component"`    // 如 major_match/skills_match/deadline_urgency
+	Contribution float64 `json:"contribution"` // 对综合得分贡献的分值
+}
+
+// RecommendationQuery 推荐流查询参数
+type RecommendationQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// RecommendationPage 推荐流分页结果
+type RecommendationPage struct {
+	Items      []*RecommendationItem `json:"items"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+// 用户角色
+const (
+	RoleStudent     = "student"      // 学生（默认）
+	RolePublisher   = "publisher"    // 发布者：可发布机会，只能修改自己发布的
+	RoleSchoolAdmin = "school_admin" // 学校管理员：可管理全部机会
+	RoleAdmin       = "admin"        // 系统管理员
+)
+
+// Roles 全部角色
+var Roles = []string{RoleStudent, RolePublisher, RoleSchoolAdmin, RoleAdmin}
+
+// OpportunityWriterRoles 可发布、修改机会的角色
+var OpportunityWriterRoles = []string{RolePublisher, RoleSchoolAdmin, RoleAdmin}
+
+// GrantRoleRequest 授予角色请求
+type GrantRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=student publisher school_admin admin"`
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 日程类型
+const (
+	ScheduleTypeCourse      = "课程"
+	ScheduleTypeExam        = "考试"
+	ScheduleTypeEvent       = "活动"
+	ScheduleTypeOpportunity = "机会"
+)
+
+// Semester 学期（重复日程的起止边界）
+type Semester struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`             // 如 2025-2026学年第一学期
+	StartDate time.Time `json:"start_date" db:"start_date"` // 第一周周一
+	EndDate   time.Time `json:"end_date" db:"end_date"`
+	Holidays  Holidays  `json:"holidays" db:"holidays"` // JSONB
+	// ExamPeriods 考试周，与节假日使用相同的日期区间结构
+	ExamPeriods Holidays  `json:"exam_periods" db:"exam_periods"` // JSONB
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Holiday 节假日（期间不展开重复日程）
+type Holiday struct {
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"` // 含当天
+}
+
+// Holidays 节假日列表
+type Holidays []Holiday
+
+// Value 实现 Holidays 的 driver.Valuer 接口
+func (h Holidays) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(h)
+}
+
+// Scan 实现 Holidays 的 sql.Scanner 接口
+func (h *Holidays) Scan(value interface{}) error {
+	if value == nil {
+		*h = Holidays{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal Holidays value: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, h)
+}
+
+// Contains 判断某天是否落在任一节假日内
+func (h Holidays) Contains(day time.Time) bool {
+	_, ok := h.Find(day)
+	return ok
+}
+
+// Find 返回包含某天的日期区间
+func (h Holidays) Find(day time.Time) (Holiday, bool) {
+	d := day.Format("2006-01-02")
+	for _, holiday := range h {
+		if d >= holiday.StartDate.Format("2006-01-02") && d <= holiday.EndDate.Format("2006-01-02") {
+			return holiday, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// ScheduleOccurrence 日程在具体时间窗口内的一次发生
+type ScheduleOccurrence struct {
+	ScheduleID int64     `json:"schedule_id"`
+	Title      string    `json:"title"`
+	Type       string    `json:"type"`
+	Location   string    `json:"location"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+}
+
+// CreateScheduleRequest 创建/更新日程请求
+type CreateScheduleRequest struct {
+	Title          string      `json:"title" binding:"required,max=200"`
+	Type           string      `json:"type" binding:"omitempty,oneof=课程 考试 活动 机会"`
+	StartTime      time.Time   `json:"start_time" binding:"required"`
+	EndTime        time.Time   `json:"end_time" binding:"required,gtfield=StartTime"`
+	Location       string      `json:"location" binding:"max=200"`
+	Description    string      `json:"description"`
+	IsRecurring    bool        `json:"is_recurring"`
+	RecurrenceRule string      `json:"recurrence_rule"` // WEEKLY_MON_WED 或 FREQ=WEEKLY;BYDAY=MO,WE
+	SemesterID     *int64      `json:"semester_id"`
+	ExceptionDates []time.Time `json:"exception_dates"`
+}
+
+// ScheduleWindowQuery 日程展开时间窗口
+type ScheduleWindowQuery struct {
+	From time.Time `form:"from" binding:"required" time_format:"2006-01-02"`
+	To   time.Time `form:"to" binding:"required,gtfield=From" time_format:"2006-01-02"` // 不含当天
+}
+
+// CreateSemesterRequest 创建/更新学期请求
+type CreateSemesterRequest struct {
+	Name        string    `json:"name" binding:"required,max=100"`
+	StartDate   time.Time `json:"start_date" binding:"required"`
+	EndDate     time.Time `json:"end_date" binding:"required,gtefield=StartDate"`
+	Holidays    Holidays  `json:"holidays"`
+	ExamPeriods Holidays  `json:"exam_periods"`
+}
+
+// 课表导入格式
+const (
+	ScheduleImportFormatICS = "ics"
+	ScheduleImportFormatCSV = "csv"
+)
+
+// ScheduleImportRequest 课表导入参数（multipart表单，文件字段为file）
+type ScheduleImportRequest struct {
+	Format     string `form:"format" binding:"omitempty,oneof=ics csv"`   // 为空时按文件扩展名判断
+	SemesterID *int64 `form:"semester_id"`                                // CSV必填：第1周从学期开始日期所在周起算
+	Type       string `form:"type" binding:"omitempty,oneof=课程 考试 活动 机会"` // 默认为课程
+}
+
+// ScheduleImportResult 课表导入结果
+type ScheduleImportResult struct {
+	Created   int                    `json:"created"`
+	Updated   int                    `json:"updated"`
+	Skipped   int                    `json:"skipped"`
+	Errors    []*ScheduleImportError `json:"errors"`
+	Schedules []*Schedule            `json:"schedules"`
+}
+
+// ScheduleImportError 单条记录的导入错误
+type ScheduleImportError struct {
+	Line    int    `json:"line,omitempty"` // CSV行号或ICS事件起始行号
+	UID     string `json:"uid,omitempty"`  // ICS事件UID
+	Message string `json:"message"`
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Session 登录会话，对应一个刷新令牌家族
+type Session struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     int64      `json:"-" db:"user_id"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	IP         string     `json:"ip" db:"ip"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"-" db:"revoked_at"`
+	Current    bool       `json:"current"` // 是否为发起请求的会话
+}
+
+// RefreshToken 刷新令牌记录（仅保存摘要）
+type RefreshToken struct {
+	ID        int64      `db:"id"`
+	SessionID int64      `db:"session_id"`
+	TokenHash string     `db:"token_hash"`
+	UsedAt    *time.Time `db:"used_at"` // 已轮换的时间，非空表示令牌已失效
+}
+
+// ClientInfo 登录请求的客户端信息，用于会话列表展示
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// TokenPair 访问令牌与刷新令牌
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+}
+
+// StreamToken 连接通知实时推送流的一次性令牌（浏览器 EventSource 无法携带 Authorization 请求头）
+type StreamToken struct {
+	Token     string `json:"token"`
+	ExpiresIn int64  `json:"expires_in"` // 有效期（秒）
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+// SkillGapReport 单个机会的能力缺口诊断结果
+type SkillGapReport struct {
+	OpportunityID     int64            `json:"opportunity_id"`
+	MatchedRule       string           `json:"matched_rule,omitempty"` // 匹配到的竞赛认定规则名称
+	RequiredSkills    []string         `json:"required_skills"`
+	MatchedSkills     []string         `json:"matched_skills"`
+	MissingSkills     []string         `json:"missing_skills"`
+	UnmetCertificates []CertificateGap `json:"unmet_certificates"`
+	UnmetConstraints  []ConstraintGap  `json:"unmet_constraints"`
+	Eligible          bool             `json:"eligible"` // 年级、专业和证书要求均已满足
+}
+
+// CertificateGap 未满足的证书要求
+// RequiredScore为0表示只需持有证书
+type CertificateGap struct {
+	Name          string  `json:"name"`
+	RequiredScore float64 `json:"required_score,omitempty"`
+	CurrentScore  float64 `json:"current_score,omitempty"`
+	Owned         bool    `json:"owned"`
+}
+
+// ConstraintGap 未满足的年级/专业约束
+type ConstraintGap struct {
+	Type     string   `json:"type"` // grade/major
+	Required []string `json:"required"`
+	Current  string   `json:"current"`
+}
+
+// SkillDemand 某项技能在用户候选机会中的需求情况
+type SkillDemand struct {
+	Skill         string `json:"skill"`
+	Opportunities int    `json:"opportunities"` // 要求该技能且用户尚未掌握的机会数
+	Unlocks       int    `json:"unlocks"`       // 掌握该技能即可满足全部技能要求的机会数
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// UserIdentity 用户在学校身份提供方的账号
+type UserIdentity struct {
+	ID          int64     `json:"id" db:"id"`
+	UserID      int64     `json:"user_id" db:"user_id"`
+	Provider    string    `json:"provider" db:"provider"` // 配置中的身份提供方ID
+	Subject     string    `json:"subject" db:"subject"`   // 身份提供方内的唯一标识
+	Email       string    `json:"email" db:"email"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at" db:"last_login_at"`
+}
+
+// SSOProvider 可用的统一身份认证入口，用于登录页展示
+type SSOProvider struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	School   string `json:"school"`
+	Protocol string `json:"protocol"` // oidc, cas
+}
+
+// SSOCallbackRequest 前端回调页收到的查询参数，原样提交给后端完成登录
+type SSOCallbackRequest struct {
+	State            string `json:"state" binding:"required"`
+	Code             string `json:"code"`   // OIDC 授权码
+	Ticket           string `json:"ticket"` // CAS 票据
+	Error            string `json:"error"`  // OIDC 授权失败时的错误码
+	ErrorDescription string `json:"error_description"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+)
+
+// TaxonomyTerm 分类词条（技能/专业）
+type TaxonomyTerm struct {
+	ID        int64     `json:"id" db:"id"`
+	Kind      string    `json:"kind" db:"kind"`   // skill/major
+	Code      string    `json:"code" db:"code"`   // 规范ID，专业使用本科专业目录代码
+	Name      string    `json:"name" db:"name"`   // 规范名称
+	Level     string    `json:"level" db:"level"` // 专业: discipline/category/major
+	ParentID  *int64    `json:"parent_id" db:"parent_id"`
+	Synonyms  []string  `json:"synonyms" db:"synonyms"` // 数组
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TaxonomyTermRequest 创建/更新分类词条请求
+type TaxonomyTermRequest struct {
+	Kind     string   `json:"kind" binding:"required,oneof=skill major"`
+	Code     string   `json:"code" binding:"required,max=50"`
+	Name     string   `json:"name" binding:"required,max=100"`
+	Level    string   `json:"level" binding:"omitempty,oneof=discipline category major"`
+	ParentID *int64   `json:"parent_id"`
+	Synonyms []string `json:"synonyms"`
+	IsActive *bool    `json:"is_active"`
+}
+
+// TaxonomyFilter 分类词条筛选条件
+type TaxonomyFilter struct {
+	Kind     string `form:"kind"`
+	Level    string `form:"level"`
+	ParentID *int64 `form:"parent_id"`
+	Query    string `form:"q"` // 按名称或同义词模糊匹配
+	Limit    int    `form:"limit"`
+	Offset   int    `form:"offset"`
+}
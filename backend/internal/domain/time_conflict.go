@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// 时间窗口类型
+const (
+	ConflictWindowEvent    = "event"    // 活动/比赛当天
+	ConflictWindowStart    = "start"    // 开始日期当天
+	ConflictWindowDeadline = "deadline" // 截止前的准备期
+)
+
+// TimeConflictReport 机会与用户日程的时间冲突分析
+type TimeConflictReport struct {
+	OpportunityID int64              `json:"opportunity_id"`
+	TimeCost      float64            `json:"time_cost"` // 归一化时间成本 0-1，与AccessibilityDetail.TimeCost一致
+	Windows       []ConflictWindow   `json:"windows"`
+	Conflicts     []ScheduleConflict `json:"conflicts"`
+	FreeSlots     []FreeSlot         `json:"free_slots"` // 建议的准备时间段
+}
+
+// ConflictWindow 参与计算的时间窗口
+type ConflictWindow struct {
+	Kind      string    `json:"kind"` // event/start/deadline
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"` // 不含
+	Weight    float64   `json:"weight"`
+	Cost      float64   `json:"cost"` // 窗口内平均每日占用比例 0-1
+}
+
+// ScheduleConflict 与时间窗口重叠的日程或考试周
+type ScheduleConflict struct {
+	Window     string    `json:"window"`      // event/start/deadline
+	ScheduleID int64     `json:"schedule_id"` // 考试周为0
+	Title      string    `json:"title"`
+	Type       string    `json:"type"` // 课程/考试/活动/机会/考试周
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+}
+
+// FreeSlot 空闲时间段
+type FreeSlot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Hours     float64   `json:"hours"`
+}
+
+// OpportunityDetail 机会详情（登录用户附带时间冲突分析）
+type OpportunityDetail struct {
+	*Opportunity
+	TimeConflict *TimeConflictReport `json:"time_conflict,omitempty"`
+}
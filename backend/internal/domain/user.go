@@ -6,16 +6,19 @@ import (
 
 // User 用户实体
 type User struct {
-	ID        int64     `json:"id" db:"id"`
-	Username  string    `json:"username" db:"username"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password_hash"` // 不在JSON中返回密码
-	School    string    `json:"school" db:"school"`
-	Major     string    `json:"major" db:"major"`
-	Grade     int       `json:"grade" db:"grade"` // 年级: 1-4
-	AvatarURL string    `json:"avatar_url" db:"avatar_url"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID              int64      `json:"id" db:"id"`
+	Username        string     `json:"username" db:"username"`
+	Email           string     `json:"email" db:"email"`
+	Password        string     `json:"-" db:"password_hash"` // 不在JSON中返回密码
+	School          string     `json:"school" db:"school"`
+	Major           string     `json:"major" db:"major"`
+	Grade           int        `json:"grade" db:"grade"` // 年级: 1-4
+	AvatarURL       string     `json:"avatar_url" db:"avatar_url"`
+	Role            string     `json:"role" db:"role"`                           // student/publisher/school_admin/admin
+	EmailVerifiedAt *time.Time `json:"email_verified_at" db:"email_verified_at"` // 为空表示邮箱未验证
+	SuspendedAt     *time.Time `json:"suspended_at,omitempty" db:"suspended_at"` // 非空表示账号已被管理员停用
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // UserProfile 用户画像
@@ -32,9 +35,9 @@ type UserProfile struct {
 
 // Cert 证书信息
 type Cert struct {
-	Name  string `json:"name"`
-	Score int    `json:"score,omitempty"`
-	Date  string `json:"date,omitempty"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score,omitempty"` // 雅思等成绩可含小数
+	Date  string  `json:"date,omitempty"`
 }
 
 // CreateUserRequest 创建用户请求
@@ -53,10 +56,11 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// LoginResponse 登录响应
+// LoginResponse 登录响应；需要先验证邮箱时不签发令牌
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  *User  `json:"user"`
+	*TokenPair
+	User                 *User `json:"user"`
+	VerificationRequired bool  `json:"verification_required,omitempty"`
 }
 
 // UpdateProfileRequest 更新画像请求
@@ -66,3 +70,11 @@ type UpdateProfileRequest struct {
 	Certificates []Cert   `json:"certificates"`
 	Interests    []string `json:"interests"`
 }
+
+// UpdateUserRequest 更新账号资料请求，只修改提供的字段；邮箱与头像另有接口
+type UpdateUserRequest struct {
+	Username *string `json:"username" binding:"omitempty,min=3,max=50"`
+	School   *string `json:"school" binding:"omitempty,min=1,max=100"`
+	Major    *string `json:"major" binding:"omitempty,min=1,max=100"`
+	Grade    *int    `json:"grade" binding:"omitempty,min=1,max=4"`
+}
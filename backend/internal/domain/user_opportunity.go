@@ -1,9 +1,27 @@
 package domain
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 )
 
+// 用户-机会关联状态
+const (
+	UserOpportunityStatusSaved     = "saved"
+	UserOpportunityStatusApplied   = "applied"
+	UserOpportunityStatusCompleted = "completed"
+	UserOpportunityStatusAbandoned = "abandoned"
+)
+
+// 用户反馈
+const (
+	UserFeedbackInterested    = "interested"
+	UserFeedbackNotInterested = "not_interested"
+	UserFeedbackIrrelevant    = "irrelevant"
+)
+
 // UserOpportunity 用户-机会关联实体
 type UserOpportunity struct {
 	ID               int64      `json:"id" db:"id"`
@@ -18,7 +36,7 @@ type UserOpportunity struct {
 
 	// 推送记录
 	PushedAt    *time.Time `json:"pushed_at" db:"pushed_at"`
-	PushChannel string     `json:"push_channel" db:"push_channel"` // desktop/email/wechat
+	PushChannel string     `json:"push_channel" db:"push_channel"` // in_app/email/webhook，逗号分隔
 
 	// 用户行为
 	ViewedAt    *time.Time `json:"viewed_at" db:"viewed_at"`
@@ -37,6 +55,27 @@ type ScoreDetail struct {
 	Relevance     RelevanceDetail     `json:"relevance"`
 }
 
+// Value 实现 ScoreDetail 的 driver.Valuer 接口
+// 将评分详情序列化为JSON字节流，供PostgreSQL JSONB列存储
+func (s ScoreDetail) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan 实现 ScoreDetail 的 sql.Scanner 接口
+// 从PostgreSQL JSONB列读取数据并反序列化为ScoreDetail结构体
+func (s *ScoreDetail) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal ScoreDetail value: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, s)
+}
+
 // AccessibilityDetail 匹配度详情
 type AccessibilityDetail struct {
 	Eligibility   float64 `json:"eligibility"`    // 硬性门槛 0-1
@@ -65,8 +104,12 @@ type Schedule struct {
 	Location       string    `json:"location" db:"location"`
 	Description    string    `json:"description" db:"description"`
 	IsRecurring    bool      `json:"is_recurring" db:"is_recurring"`
-	RecurrenceRule string    `json:"recurrence_rule" db:"recurrence_rule"` // WEEKLY_MON_WED
+	RecurrenceRule string    `json:"recurrence_rule" db:"recurrence_rule"` // WEEKLY_MON_WED 或 RRULE
+	SemesterID     *int64    `json:"semester_id" db:"semester_id"`         // 重复日程限定在学期范围内
+	ExceptionDates []time.Time `json:"exception_dates" db:"exception_dates"` // 单独取消的日期（调课/停课）
+	ImportKey      string    `json:"import_key,omitempty" db:"import_key"` // 导入来源键，重复导入时据此更新
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // CrawlTask 爬虫任务实体
@@ -119,3 +162,47 @@ type FeedbackRequest struct {
 	Feedback string `json:"feedback" binding:"required,oneof=interested not_interested irrelevant"`
 	Reason   string `json:"reason"`
 }
+
+// UserOpportunityDetail 用户-机会关联及机会详情（"我的机会"列表项）
+type UserOpportunityDetail struct {
+	UserOpportunity
+	Opportunity *Opportunity `json:"opportunity"`
+}
+
+// UserOpportunityFilter "我的机会"筛选条件
+type UserOpportunityFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=saved applied completed abandoned"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// FeedbackReportQuery 负反馈统计查询参数
+type FeedbackReportQuery struct {
+	Limit int `form:"limit"`
+}
+
+// IrrelevantOpportunityStat 被标记为无关的机会统计
+type IrrelevantOpportunityStat struct {
+	OpportunityID   int64   `json:"opportunity_id"`
+	Title           string  `json:"title"`
+	SourceURL       string  `json:"source_url"`
+	SourceType      string  `json:"source_type"`
+	IrrelevantCount int64   `json:"irrelevant_count"`
+	FeedbackCount   int64   `json:"feedback_count"`  // 全部反馈数
+	IrrelevantRate  float64 `json:"irrelevant_rate"` // 无关反馈占全部反馈比例
+}
+
+// IrrelevantSourceStat 按来源站点汇总的无关反馈统计
+type IrrelevantSourceStat struct {
+	Host             string `json:"host"`      // 来源域名
+	SiteName         string `json:"site_name"` // 对应爬虫任务的站点名称
+	SourceType       string `json:"source_type"`
+	IrrelevantCount  int64  `json:"irrelevant_count"`
+	OpportunityCount int64  `json:"opportunity_count"` // 被标记为无关的机会数
+}
+
+// FeedbackReport 负反馈统计报告
+type FeedbackReport struct {
+	Opportunities []*IrrelevantOpportunityStat `json:"opportunities"`
+	Sources       []*IrrelevantSourceStat      `json:"sources"`
+}
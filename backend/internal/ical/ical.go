@@ -0,0 +1,293 @@
+// Package ical reads and writes RFC 5545 iCalendar documents.
+//
+// Only the components needed for calendar exports are written: VCALENDAR,
+// VTIMEZONE (a single fixed-offset STANDARD rule), VEVENT and VALARM.
+// Timed events are written as wall-clock times in the calendar time zone,
+// all-day events as DATE values. Parsing reads VEVENTs and ignores every
+// other component.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// 行长度上限（RFC 5545 3.1，单位为字节，不含CRLF）
+const maxLineOctets = 75
+
+const (
+	dateLayout     = "20060102"
+	localLayout    = "20060102T150405"
+	utcLayout      = "20060102T150405Z"
+	defaultProdID  = "-//UniFocus//Calendar Export//ZH"
+	defaultVersion = "2.0"
+)
+
+// Calendar is a VCALENDAR document
+type Calendar struct {
+	ProdID   string
+	Name     string         // X-WR-CALNAME，订阅时显示的日历名称
+	Location *time.Location // 定时事件的时区，nil表示UTC
+	Refresh  time.Duration  // 建议客户端刷新间隔，0表示不声明
+	Events   []*Event
+}
+
+// Event is a VEVENT
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Categories   []string
+	Start        time.Time
+	End          time.Time // 全天事件为结束日期（不含）
+	AllDay       bool
+	RRule        string      // 不含"RRULE:"前缀
+	RecurrenceID *time.Time  // 非空表示覆盖重复事件的某次发生
+	ExDates      []time.Time // 被排除的重复实例开始时间
+	Stamp        time.Time   // DTSTAMP，零值时使用生成时间
+	Alarms       []Alarm
+}
+
+// Alarm is a VALARM with a DISPLAY action
+type Alarm struct {
+	Before      time.Duration // 在事件开始前多久提醒
+	Description string
+}
+
+// Encode writes the calendar to w
+func (c *Calendar) Encode(w io.Writer) error {
+	enc := &encoder{w: w}
+	now := time.Now().UTC()
+
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	tzid := ""
+	if loc != time.UTC {
+		tzid = loc.String()
+	}
+
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = defaultProdID
+	}
+
+	enc.line("BEGIN:VCALENDAR")
+	enc.line("VERSION:" + defaultVersion)
+	enc.line("PRODID:" + prodID)
+	enc.line("CALSCALE:GREGORIAN")
+	enc.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		enc.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if tzid != "" {
+		enc.line("X-WR-TIMEZONE:" + tzid)
+	}
+	if c.Refresh > 0 {
+		enc.line("REFRESH-INTERVAL;VALUE=DURATION:" + formatDuration(c.Refresh))
+		enc.line("X-PUBLISHED-TTL:" + formatDuration(c.Refresh))
+	}
+	if tzid != "" {
+		writeTimezone(enc, loc, tzid)
+	}
+
+	for _, e := range c.Events {
+		writeEvent(enc, e, loc, tzid, now)
+	}
+
+	enc.line("END:VCALENDAR")
+	return enc.err
+}
+
+// String returns the encoded calendar
+func (c *Calendar) String() string {
+	var sb strings.Builder
+	_ = c.Encode(&sb)
+	return sb.String()
+}
+
+// writeTimezone 写出固定偏移的VTIMEZONE（取当前偏移，不描述夏令时切换）
+func writeTimezone(enc *encoder, loc *time.Location, tzid string) {
+	name, offset := time.Now().In(loc).Zone()
+
+	enc.line("BEGIN:VTIMEZONE")
+	enc.line("TZID:" + tzid)
+	enc.line("BEGIN:STANDARD")
+	enc.line("DTSTART:19700101T000000")
+	enc.line("TZOFFSETFROM:" + formatOffset(offset))
+	enc.line("TZOFFSETTO:" + formatOffset(offset))
+	enc.line("TZNAME:" + name)
+	enc.line("END:STANDARD")
+	enc.line("END:VTIMEZONE")
+}
+
+func writeEvent(enc *encoder, e *Event, loc *time.Location, tzid string, now time.Time) {
+	stamp := e.Stamp
+	if stamp.IsZero() {
+		stamp = now
+	}
+
+	enc.line("BEGIN:VEVENT")
+	enc.line("UID:" + e.UID)
+	enc.line("DTSTAMP:" + stamp.UTC().Format(utcLayout))
+	if e.AllDay {
+		enc.line("DTSTART;VALUE=DATE:" + e.Start.Format(dateLayout))
+		end := e.End
+		if !end.After(e.Start) {
+			end = e.Start.AddDate(0, 0, 1)
+		}
+		enc.line("DTEND;VALUE=DATE:" + end.Format(dateLayout))
+	} else {
+		enc.line("DTSTART" + formatTime(e.Start, loc, tzid))
+		if e.End.After(e.Start) {
+			enc.line("DTEND" + formatTime(e.End, loc, tzid))
+		}
+	}
+	enc.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		enc.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.Location != "" {
+		enc.line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.URL != "" {
+		enc.line("URL:" + e.URL)
+	}
+	if len(e.Categories) > 0 {
+		categories := make([]string, len(e.Categories))
+		for i, category := range e.Categories {
+			categories[i] = escapeText(category)
+		}
+		enc.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+	if e.RecurrenceID != nil {
+		enc.line("RECURRENCE-ID" + formatTime(*e.RecurrenceID, loc, tzid))
+	}
+	if e.RRule != "" {
+		enc.line("RRULE:" + e.RRule)
+		for _, d := range e.ExDates {
+			if e.AllDay {
+				enc.line("EXDATE;VALUE=DATE:" + d.Format(dateLayout))
+			} else {
+				enc.line("EXDATE" + formatTime(d, loc, tzid))
+			}
+		}
+	}
+
+	for _, alarm := range e.Alarms {
+		description := alarm.Description
+		if description == "" {
+			description = e.Summary
+		}
+		enc.line("BEGIN:VALARM")
+		enc.line("ACTION:DISPLAY")
+		enc.line("DESCRIPTION:" + escapeText(description))
+		enc.line("TRIGGER:-" + formatDuration(alarm.Before))
+		enc.line("END:VALARM")
+	}
+
+	enc.line("END:VEVENT")
+}
+
+// formatTime 返回带参数的属性值部分，如 ";TZID=Asia/Shanghai:20250303T080000"
+// 输入视为目标时区的墙上时间（数据库TIMESTAMP列不带时区）
+func formatTime(t time.Time, loc *time.Location, tzid string) string {
+	if tzid == "" {
+		return ":" + t.UTC().Format(utcLayout)
+	}
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+	return ";TZID=" + tzid + ":" + wall.Format(localLayout)
+}
+
+// formatDuration 格式化为RFC 5545 DURATION，如 P1D、PT15M、P1DT2H
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	d = d.Truncate(time.Second)
+	if d == 0 {
+		return "PT0S"
+	}
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	var sb strings.Builder
+	sb.WriteString("P")
+	if days > 0 {
+		fmt.Fprintf(&sb, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 {
+		sb.WriteString("T")
+		if hours > 0 {
+			fmt.Fprintf(&sb, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&sb, "%dM", minutes)
+		}
+		if seconds > 0 {
+			fmt.Fprintf(&sb, "%dS", seconds)
+		}
+	}
+	return sb.String()
+}
+
+// formatOffset 格式化UTC偏移，如 +0800
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// escapeText 转义TEXT值中的反斜杠、分号、逗号和换行
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// encoder writes folded content lines and keeps the first write error
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+// line 写出一行内容，超过75字节时按UTF-8字符边界折行
+func (e *encoder) line(content string) {
+	if e.err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > maxLineOctets {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(r)
+		width += size
+	}
+	sb.WriteString("\r\n")
+
+	_, e.err = io.WriteString(e.w, sb.String())
+}
@@ -0,0 +1,223 @@
+package ical
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a,b;c", `a\,b\;c`},
+		{`C:\path`, `C:\\path`},
+		{"line1\nline2", `line1\nline2`},
+		{"line1\r\nline2", `line1\nline2`},
+		{"全角标点：不转义，", "全角标点：不转义，"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := escapeText(tt.in)
+			if got != tt.want {
+				t.Errorf("escapeText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if back := unescapeText(got); back != strings.ReplaceAll(tt.in, "\r\n", "\n") {
+				t.Errorf("unescapeText(%q) = %q, want %q", got, back, tt.in)
+			}
+		})
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"short", "SUMMARY:Go"},
+		{"exactly 75 octets", "DESCRIPTION:" + strings.Repeat("x", 75-len("DESCRIPTION:"))},
+		{"long ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"long multibyte", "DESCRIPTION:" + strings.Repeat("全国大学生数学建模竞赛", 10)},
+		{"mixed", "LOCATION:" + strings.Repeat("教学楼A-101 ", 12)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			enc := &encoder{w: &sb}
+			enc.line(tt.content)
+			if enc.err != nil {
+				t.Fatalf("line() error = %v", enc.err)
+			}
+
+			out := sb.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q does not end with CRLF", out)
+			}
+			physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, line := range physical {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d has %d octets, want at most %d", i, len(line), maxLineOctets)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 character: %q", i, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, line)
+				}
+			}
+
+			lines, err := unfold(strings.NewReader(out))
+			if err != nil {
+				t.Fatalf("unfold() error = %v", err)
+			}
+			if len(lines) != 1 || lines[0] != tt.content {
+				t.Errorf("unfold() = %q, want [%q]", lines, tt.content)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{15 * time.Minute, "PT15M"},
+		{24 * time.Hour, "P1D"},
+		{26*time.Hour + 30*time.Minute, "P1DT2H30M"},
+		{90 * time.Second, "PT1M30S"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatDuration(tt.d); got != tt.want {
+				t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+			if got, err := parseDuration(tt.want); err != nil || got != tt.d {
+				t.Errorf("parseDuration(%q) = %v, %v, want %v", tt.want, got, err, tt.d)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stamp := time.Date(2025, 2, 20, 1, 2, 3, 0, time.UTC)
+
+	events := []*Event{
+		{
+			UID:         "opportunity-1-deadline@unifocus",
+			Summary:     `截止：数学建模, 第一轮; 提交\材料`,
+			Description: strings.Repeat("请在截止前完成线上报名并上传作品。", 8) + "\n详情见官网",
+			Location:    "线上",
+			URL:         "https://example.com/contest?id=1&from=ics",
+			Categories:  []string{"竞赛", "数学,建模"},
+			Start:       time.Date(2025, 3, 10, 23, 59, 0, 0, loc),
+			End:         time.Date(2025, 3, 11, 0, 0, 0, 0, loc),
+			Stamp:       stamp,
+			Alarms:      []Alarm{{Before: 24 * time.Hour}},
+		},
+		{
+			UID:     "schedule-7@unifocus",
+			Summary: "高等数学",
+			Start:   time.Date(2025, 3, 3, 8, 0, 0, 0, loc),
+			End:     time.Date(2025, 3, 3, 9, 40, 0, 0, loc),
+			RRule:   "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20250630T000000Z",
+			ExDates: []time.Time{
+				time.Date(2025, 4, 7, 8, 0, 0, 0, loc),
+				time.Date(2025, 5, 5, 8, 0, 0, 0, loc),
+			},
+			Stamp: stamp,
+		},
+		{
+			UID:     "opportunity-2-event@unifocus",
+			Summary: "宣讲会",
+			Start:   time.Date(2025, 4, 1, 0, 0, 0, 0, loc),
+			End:     time.Date(2025, 4, 2, 0, 0, 0, 0, loc),
+			AllDay:  true,
+			Stamp:   stamp,
+		},
+	}
+
+	cal := &Calendar{Name: "UniFocus", Location: loc, Refresh: time.Hour, Events: events}
+	encoded := cal.String()
+	for i, line := range strings.Split(strings.TrimSuffix(encoded, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("encoded line %d has %d octets", i, len(line))
+		}
+	}
+
+	parsed, invalid, err := Parse(strings.NewReader(encoded), loc)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(invalid) > 0 {
+		t.Fatalf("Parse() invalid events = %v", invalid)
+	}
+	if len(parsed) != len(events) {
+		t.Fatalf("Parse() returned %d events, want %d", len(parsed), len(events))
+	}
+
+	for i, want := range events {
+		got := parsed[i]
+		t.Run(want.UID, func(t *testing.T) {
+			if got.UID != want.UID || got.Summary != want.Summary || got.Description != want.Description ||
+				got.Location != want.Location || got.URL != want.URL || got.RRule != want.RRule || got.AllDay != want.AllDay {
+				t.Errorf("Parse() = %+v, want %+v", got, want)
+			}
+			if !reflect.DeepEqual(got.Categories, want.Categories) {
+				t.Errorf("Categories = %q, want %q", got.Categories, want.Categories)
+			}
+			if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+				t.Errorf("time range = %v - %v, want %v - %v", got.Start, got.End, want.Start, want.End)
+			}
+			if !got.Stamp.Equal(want.Stamp) {
+				t.Errorf("Stamp = %v, want %v", got.Stamp, want.Stamp)
+			}
+			if len(got.ExDates) != len(want.ExDates) {
+				t.Fatalf("ExDates = %v, want %v", got.ExDates, want.ExDates)
+			}
+			for j := range want.ExDates {
+				if !got.ExDates[j].Equal(want.ExDates[j]) {
+					t.Errorf("ExDates[%d] = %v, want %v", j, got.ExDates[j], want.ExDates[j])
+				}
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantErr     bool
+		wantInvalid int
+	}{
+		{"not a calendar", "hello\r\n", true, 0},
+		{"missing dtstart", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", false, 1},
+		{"end before start", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:b\r\nDTSTART:20250302T100000Z\r\nDTEND:20250302T090000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", false, 1},
+		{"unterminated event", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:c\r\nDTSTART:20250302\r\n", false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, invalid, err := Parse(strings.NewReader(tt.input), time.UTC)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(invalid) != tt.wantInvalid {
+				t.Errorf("Parse() invalid = %v, want %d", invalid, tt.wantInvalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxEvents 单个文件允许解析的事件数量上限
+const maxEvents = 5000
+
+// ErrInvalidCalendar indicates the input is not an iCalendar document
+var ErrInvalidCalendar = errors.New("invalid iCalendar data")
+
+// EventError describes a VEVENT that could not be parsed
+type EventError struct {
+	UID  string
+	Line int // BEGIN:VEVENT所在行号（展开折行后）
+	Err  error
+}
+
+func (e *EventError) Error() string {
+	return fmt.Sprintf("event %q at line %d: %v", e.UID, e.Line, e.Err)
+}
+
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+// property is one unfolded content line
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENTs of an iCalendar document
+// Timed values are converted to wall-clock times in loc (UTC values and values
+// with a known TZID are converted, floating values are kept as written) and
+// returned with location loc. Events with invalid properties are reported in
+// the second return value instead of failing the whole document.
+func Parse(r io.Reader, loc *time.Location) ([]*Event, []*EventError, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		events    []*Event
+		invalid   []*EventError
+		current   []property
+		startLine int
+		depth     int // VEVENT内部嵌套组件（如VALARM）的层数
+		inEvent   bool
+		calendar  bool
+	)
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseProperty(line)
+		if err != nil {
+			if inEvent {
+				// 记入事件，结束时统一报告
+				current = append(current, property{name: "X-INVALID", value: err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, i+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			calendar = true
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && !inEvent:
+			inEvent, current, startLine, depth = true, nil, i+1, 0
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && inEvent && depth == 0:
+			inEvent = false
+			event, err := buildEvent(current, loc)
+			if err != nil {
+				invalid = append(invalid, &EventError{UID: uidOf(current), Line: startLine, Err: err})
+				continue
+			}
+			events = append(events, event)
+			if len(events) > maxEvents {
+				return nil, nil, fmt.Errorf("%w: more than %d events", ErrInvalidCalendar, maxEvents)
+			}
+		case inEvent && prop.name == "BEGIN":
+			depth++
+		case inEvent && prop.name == "END":
+			depth--
+		case inEvent && depth == 0:
+			current = append(current, prop)
+		}
+	}
+
+	if !calendar {
+		return nil, nil, fmt.Errorf("%w: missing BEGIN:VCALENDAR", ErrInvalidCalendar)
+	}
+	if inEvent {
+		invalid = append(invalid, &EventError{UID: uidOf(current), Line: startLine, Err: errors.New("missing END:VEVENT")})
+	}
+
+	return events, invalid, nil
+}
+
+// unfold 读取全部内容行并合并折行（以空格或制表符开头的行）
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+
+	return lines, nil
+}
+
+// parseProperty 解析 NAME;PARAM=VALUE;...:VALUE 格式的内容行
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+
+	// 名称与参数部分以第一个不在引号内的冒号结束
+	inQuote := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	prop.name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		prop.params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+	}
+	prop.value = value
+
+	return prop, nil
+}
+
+// buildEvent 将VEVENT的属性转换为Event
+func buildEvent(props []property, loc *time.Location) (*Event, error) {
+	event := &Event{}
+	var duration time.Duration
+	hasDuration := false
+
+	for _, prop := range props {
+		switch prop.name {
+		case "X-INVALID":
+			return nil, errors.New(prop.value)
+		case "UID":
+			event.UID = strings.TrimSpace(prop.value)
+		case "SUMMARY":
+			event.Summary = unescapeText(prop.value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(prop.value)
+		case "LOCATION":
+			event.Location = unescapeText(prop.value)
+		case "URL":
+			event.URL = strings.TrimSpace(prop.value)
+		case "CATEGORIES":
+			for _, category := range splitText(prop.value) {
+				if category != "" {
+					event.Categories = append(event.Categories, category)
+				}
+			}
+		case "DTSTART":
+			t, allDay, err := parseTime(prop, loc)
+			if err != nil {
+				return nil, fmt.Errorf("DTSTART: %w", err)
+			}
+			event.Start, event.AllDay = t, allDay
+		case "DTEND":
+			t, _, err := parseTime(prop, loc)
+			if err != nil {
+				return nil, fmt.Errorf("DTEND: %w", err)
+			}
+			event.End = t
+		case "DURATION":
+			d, err := parseDuration(prop.value)
+			if err != nil {
+				return nil, fmt.Errorf("DURATION: %w", err)
+			}
+			duration, hasDuration = d, true
+		case "DTSTAMP", "LAST-MODIFIED":
+			if t, _, err := parseTime(prop, time.UTC); err == nil && t.After(event.Stamp) {
+				event.Stamp = t
+			}
+		case "RRULE":
+			event.RRule = strings.TrimSpace(prop.value)
+		case "RECURRENCE-ID":
+			t, _, err := parseTime(prop, loc)
+			if err != nil {
+				return nil, fmt.Errorf("RECURRENCE-ID: %w", err)
+			}
+			event.RecurrenceID = &t
+		case "EXDATE":
+			for _, value := range strings.Split(prop.value, ",") {
+				t, _, err := parseTime(property{name: prop.name, params: prop.params, value: value}, loc)
+				if err != nil {
+					return nil, fmt.Errorf("EXDATE: %w", err)
+				}
+				event.ExDates = append(event.ExDates, t)
+			}
+		}
+	}
+
+	if event.Start.IsZero() {
+		return nil, errors.New("missing DTSTART")
+	}
+	if event.End.IsZero() {
+		switch {
+		case hasDuration:
+			event.End = event.Start.Add(duration)
+		case event.AllDay:
+			event.End = event.Start.AddDate(0, 0, 1)
+		default:
+			event.End = event.Start
+		}
+	}
+	if event.End.Before(event.Start) {
+		return nil, errors.New("DTEND is before DTSTART")
+	}
+
+	return event, nil
+}
+
+// parseTime 解析DATE或DATE-TIME值，返回loc中的墙上时间
+func parseTime(prop property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcLayout, value)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return t.In(loc), false, nil
+	}
+
+	source := loc
+	if tzid := prop.params["TZID"]; tzid != "" {
+		// 未知时区（如Outlook的Windows时区名）按日历时区处理
+		if tz, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			source = tz
+		}
+	}
+	t, err := time.ParseInLocation(localLayout, value, source)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t.In(loc), false, nil
+}
+
+// parseDuration 解析RFC 5545 DURATION，如 PT1H30M、P1D、P2W
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimLeft(value, "+-")
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var total time.Duration
+	n := 0
+	inTime := false
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			n = n*10 + int(r-'0')
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		case r == 'W':
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D':
+			total += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			total += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			total += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		n = 0
+	}
+
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+// unescapeText 还原TEXT值中的转义字符
+func unescapeText(value string) string {
+	var sb strings.Builder
+	escaped := false
+	for _, r := range value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				sb.WriteRune('\n')
+			default:
+				sb.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// splitText 按未转义的逗号拆分多值TEXT
+func splitText(value string) []string {
+	var parts []string
+	start := 0
+	escaped := false
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			parts = append(parts, unescapeText(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, unescapeText(value[start:]))
+}
+
+// uidOf 返回属性列表中的UID
+func uidOf(props []property) string {
+	for _, prop := range props {
+		if prop.name == "UID" {
+			return strings.TrimSpace(prop.value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,281 @@
+// Package mail composes MIME messages and sends them through pluggable senders.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// ErrNoRecipients indicates a message has no recipients
+var ErrNoRecipients = errors.New("mail has no recipients")
+
+// Message is an email with a plain-text and an optional HTML body
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string // 额外的邮件头，如 List-Unsubscribe
+}
+
+// Sender delivers email messages
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewSender creates the sender for the configured driver: smtp, file or console
+func NewSender(cfg config.MailConfig) Sender {
+	switch cfg.Driver {
+	case "file":
+		return NewFileSender(cfg.From, cfg.Dir)
+	case "console":
+		return NewConsoleSender()
+	default:
+		return NewSMTPSender(cfg)
+	}
+}
+
+// SMTPSender sends email through an SMTP server
+type SMTPSender struct {
+	cfg  config.SMTPConfig
+	from string
+}
+
+// NewSMTPSender creates a new SMTP sender
+func NewSMTPSender(cfg config.MailConfig) *SMTPSender {
+	return &SMTPSender{cfg: cfg.SMTP, from: cfg.From}
+}
+
+// Send delivers a message; the context bounds dialing and the whole SMTP session
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.from, err)
+	}
+	data, err := msg.Bytes(s.from)
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(s.cfg.Timeout) * time.Second
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.cfg.GetAddr())
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host}
+	if s.cfg.TLS == "tls" {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if s.cfg.TLS == "starttls" {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, to := range msg.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("smtp RCPT TO failed: %w", err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// Bytes renders the message as an RFC 5322 document
+// Bodies are base64 encoded UTF-8; an HTML body produces multipart/alternative
+func (m *Message) Bytes(from string) ([]byte, error) {
+	if len(m.To) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+
+	header("From", from)
+	header("To", strings.Join(m.To, ", "))
+	header("Subject", mime.BEncoding.Encode("UTF-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@%s>", randomID(), messageDomain(from)))
+	header("MIME-Version", "1.0")
+
+	names := make([]string, 0, len(m.Headers))
+	for name := range m.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header(name, m.Headers[name])
+	}
+
+	if m.HTML == "" {
+		header("Content-Type", `text/plain; charset="UTF-8"`)
+		header("Content-Transfer-Encoding", "base64")
+		buf.WriteString("\r\n")
+		writeBase64(&buf, m.Text)
+		return buf.Bytes(), nil
+	}
+
+	boundary := "unifocus-" + randomID()
+	header("Content-Type", fmt.Sprintf(`multipart/alternative; boundary="%s"`, boundary))
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", m.Text},
+		{"text/html", m.HTML},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=\"UTF-8\"\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+		writeBase64(&buf, part.body)
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+// writeBase64 写出按76字符折行的base64正文
+func writeBase64(buf *bytes.Buffer, body string) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+}
+
+// randomID 生成用于Message-ID和分隔符的随机串
+func randomID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// messageDomain 取发件人地址的域名
+func messageDomain(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndex(addr.Address, "@"); i >= 0 {
+			return addr.Address[i+1:]
+		}
+	}
+	return "unifocus"
+}
+
+// FileSender writes messages as .eml files instead of sending them (dry-run mode)
+type FileSender struct {
+	dir  string
+	from string
+}
+
+// NewFileSender creates a sender that writes messages into dir
+func NewFileSender(from, dir string) *FileSender {
+	return &FileSender{dir: dir, from: from}
+}
+
+// Send writes the message to <dir>/<time>-<recipient>.eml
+func (s *FileSender) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes(s.from)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000"), fileSafe(msg.To[0]))
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// fileSafe 将收件人地址转换为可用作文件名的字符串
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '@', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// ConsoleSender writes messages to the log instead of sending them (local development)
+type ConsoleSender struct{}
+
+// NewConsoleSender creates a sender that logs messages
+func NewConsoleSender() *ConsoleSender {
+	return &ConsoleSender{}
+}
+
+// Send logs the recipients, subject and plain-text body
+func (s *ConsoleSender) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	logger.Infof("Mail to %s: %s\n%s", strings.Join(msg.To, ", "), msg.Subject, msg.Text)
+	return nil
+}
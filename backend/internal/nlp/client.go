@@ -0,0 +1,122 @@
+package nlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+)
+
+// maxResponseBytes NLP服务响应的大小上限
+const maxResponseBytes = 8 << 20
+
+// ErrNotSupported indicates the NLP service does not provide the requested capability
+var ErrNotSupported = errors.New("not supported by the nlp service")
+
+// Client calls the UniFocus NLP service over HTTP for text extraction and vectorization
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// New creates an NLP service client, or returns nil when no service URL is configured
+func New(cfg config.NLPServiceConfig) *Client {
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.URL), "/")
+	if baseURL == "" {
+		return nil
+	}
+	return &Client{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+// textResponse 文本提取接口的响应
+type textResponse struct {
+	Text string `json:"text"`
+}
+
+// vectorResponse 向量化接口的响应
+type vectorResponse struct {
+	Vector []float32 `json:"vector"`
+}
+
+// ExtractTextFromPDF extracts the plain text of a PDF document
+func (c *Client) ExtractTextFromPDF(ctx context.Context, fileData []byte) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "resume.pdf")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(fileData); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	var resp textResponse
+	if err := c.post(ctx, "/api/v1/extract/pdf", form.FormDataContentType(), &body, &resp); err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// VectorizeText returns the sentence embedding of text
+func (c *Client) VectorizeText(ctx context.Context, text string) ([]float32, error) {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp vectorResponse
+	if err := c.post(ctx, "/api/v1/vectorize/text", "application/json", bytes.NewReader(payload), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Vector) == 0 {
+		return nil, errors.New("nlp service returned an empty vector")
+	}
+	return resp.Vector, nil
+}
+
+// ExtractSkills is not offered by the NLP service yet; skills are entered by the user
+func (c *Client) ExtractSkills(ctx context.Context, text string) ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// post 发送请求并解析JSON响应
+func (c *Client) post(ctx context.Context, path, contentType string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("nlp service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read nlp service response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nlp service %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode nlp service response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// subscriberBuffer 每个实时连接缓冲的通知数，连接过慢时丢弃（客户端可通过列表接口补齐）
+const subscriberBuffer = 16
+
+// Broker fans out new notifications to live connections on every instance.
+// Notifications are published to a Redis channel; each instance subscribes to
+// it and forwards messages to its own connections of the addressed user.
+type Broker struct {
+	rdb     *redis.Client
+	channel string
+
+	mu          sync.RWMutex
+	subscribers map[int64]map[chan *domain.Notification]struct{}
+}
+
+// NewBroker creates a new notification broker
+func NewBroker(rdb *redis.Client) *Broker {
+	return &Broker{
+		rdb:         rdb,
+		channel:     rdb.Key("notifications"),
+		subscribers: make(map[int64]map[chan *domain.Notification]struct{}),
+	}
+}
+
+// Publish announces a stored notification to all instances
+func (b *Broker) Publish(ctx context.Context, n *domain.Notification) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	return b.rdb.Publish(ctx, b.channel, data).Err()
+}
+
+// Run relays published notifications to local subscribers until ctx is cancelled,
+// then closes all live connections so that server shutdown is not held up by them
+func (b *Broker) Run(ctx context.Context) {
+	pubsub := b.rdb.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+	defer b.closeAll()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			n := &domain.Notification{}
+			if err := json.Unmarshal([]byte(msg.Payload), n); err != nil {
+				logger.Warnf("failed to decode published notification: %v", err)
+				continue
+			}
+			b.dispatch(n)
+		}
+	}
+}
+
+// Subscribe registers a live connection of the user; the returned function unregisters it
+func (b *Broker) Subscribe(userID int64) (<-chan *domain.Notification, func()) {
+	ch := make(chan *domain.Notification, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan *domain.Notification]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[userID][ch]; !ok {
+			return // 已被closeAll关闭
+		}
+		delete(b.subscribers[userID], ch)
+		if len(b.subscribers[userID]) == 0 {
+			delete(b.subscribers, userID)
+		}
+		close(ch)
+	}
+}
+
+// closeAll 关闭所有连接的通道
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, channels := range b.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+	}
+	b.subscribers = make(map[int64]map[chan *domain.Notification]struct{})
+}
+
+// dispatch 将通知投递给该用户在本实例上的所有连接
+func (b *Broker) dispatch(n *domain.Notification) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[n.UserID] {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// flushLockKey 多实例部署时保证同一时刻只有一个实例在补发暂存的推送
+const flushLockKey = "notify:flush"
+
+// flushInterval 检查免打扰时段是否结束的间隔
+const flushInterval = time.Minute
+
+// silentNotifier 免打扰期间仍可送达但不打扰用户的渠道（如站内通知只写入通知中心，不做实时推送）
+type silentNotifier interface {
+	NotifySilently(ctx context.Context, user *domain.User, n *domain.Notification) error
+}
+
+// Dispatcher routes notifications to the notifiers allowed by each user's
+// preferences and holds pushes back during the user's quiet hours
+type Dispatcher struct {
+	notifiers      map[string]Notifier
+	channels       []string // 已启用的渠道，按配置顺序
+	defaultOffsets []int
+	loc            *time.Location
+	profileRepo    *postgres.ProfileRepository
+	heldRepo       *postgres.HeldNotificationRepository
+	userRepo       *postgres.UserRepository
+	locker         *redis.Client
+}
+
+// NewDispatcher creates a new dispatcher over the configured notifiers
+func NewDispatcher(
+	cfg *config.Config,
+	notifiers []Notifier,
+	profileRepo *postgres.ProfileRepository,
+	heldRepo *postgres.HeldNotificationRepository,
+	userRepo *postgres.UserRepository,
+	locker *redis.Client,
+) *Dispatcher {
+	d := &Dispatcher{
+		notifiers:      make(map[string]Notifier, len(notifiers)),
+		defaultOffsets: append([]int(nil), cfg.Notification.Reminder.OffsetDays...),
+		loc:            cfg.Calendar.GetLocation(),
+		profileRepo:    profileRepo,
+		heldRepo:       heldRepo,
+		userRepo:       userRepo,
+		locker:         locker,
+	}
+	for _, notifier := range notifiers {
+		d.notifiers[notifier.Channel()] = notifier
+		d.channels = append(d.channels, notifier.Channel())
+	}
+	sort.Ints(d.defaultOffsets)
+	return d
+}
+
+// Available reports whether the channel is enabled on this deployment
+func (d *Dispatcher) Available(channel string) bool {
+	_, ok := d.notifiers[channel]
+	return ok
+}
+
+// Preferences returns the user's effective preferences: stored settings with
+// system defaults filled in. A failed lookup falls back to the defaults.
+func (d *Dispatcher) Preferences(ctx context.Context, userID int64) *domain.NotificationPreferences {
+	stored, err := d.profileRepo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		logger.Warnf("failed to load notification preferences of user %d: %v", userID, err)
+		stored = &domain.NotificationPreferences{}
+	}
+	return d.Resolve(stored)
+}
+
+// Resolve fills in system defaults for the settings missing from stored
+func (d *Dispatcher) Resolve(stored *domain.NotificationPreferences) *domain.NotificationPreferences {
+	prefs := &domain.NotificationPreferences{
+		Types:              make(map[string]*domain.NotificationTypePreference, len(domain.NotificationTypes)),
+		ReminderOffsetDays: append([]int(nil), stored.ReminderOffsetDays...),
+		QuietHours:         stored.QuietHours,
+	}
+
+	for _, typ := range domain.NotificationTypes {
+		if pref, ok := stored.Types[typ]; ok && pref != nil {
+			prefs.Types[typ] = &domain.NotificationTypePreference{
+				Enabled:  pref.Enabled,
+				Channels: append([]string(nil), pref.Channels...),
+			}
+			continue
+		}
+		prefs.Types[typ] = &domain.NotificationTypePreference{Enabled: true, Channels: d.defaultChannels(typ)}
+	}
+	if len(prefs.ReminderOffsetDays) == 0 {
+		prefs.ReminderOffsetDays = append([]int(nil), d.defaultOffsets...)
+	}
+	sort.Ints(prefs.ReminderOffsetDays)
+	if prefs.QuietHours.Timezone == "" {
+		prefs.QuietHours.Timezone = d.loc.String()
+	}
+
+	return prefs
+}
+
+// defaultChannels 未设置时的推送渠道：截止提醒走全部已启用渠道，变更通知与新机会匹配仅站内
+func (d *Dispatcher) defaultChannels(typ string) []string {
+	if typ == domain.NotificationTypeStatusChange || typ == domain.NotificationTypeNewMatch {
+		if d.Available(domain.NotificationChannelInApp) {
+			return []string{domain.NotificationChannelInApp}
+		}
+		return []string{}
+	}
+	return append([]string{}, d.channels...)
+}
+
+// Channels returns the enabled channels the user wants notifications of the type on
+func (d *Dispatcher) Channels(prefs *domain.NotificationPreferences, typ string) []string {
+	pref, ok := prefs.Types[typ]
+	if !ok || !pref.Enabled {
+		return nil
+	}
+
+	var channels []string
+	for _, channel := range pref.Channels {
+		if d.Available(channel) {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// Deliver sends n to the user on one channel. During the user's quiet hours
+// in-app notifications are stored without the live push, and pushes on other
+// channels are held back until the quiet hours end. held reports whether it was
+// held rather than sent.
+func (d *Dispatcher) Deliver(ctx context.Context, user *domain.User, prefs *domain.NotificationPreferences, channel string, n *domain.Notification, now time.Time) (bool, error) {
+	notifier, ok := d.notifiers[channel]
+	if !ok {
+		return false, fmt.Errorf("notification channel %q is not enabled", channel)
+	}
+
+	// 站内通知会写入ID等字段，每个渠道使用独立副本
+	copied := *n
+
+	if InQuietHours(prefs.QuietHours, now, d.loc) {
+		if silent, ok := notifier.(silentNotifier); ok {
+			return false, silent.NotifySilently(ctx, user, &copied)
+		}
+		n.UserID = user.ID
+		if err := d.heldRepo.Hold(ctx, user.ID, channel, n); err != nil {
+			return false, fmt.Errorf("failed to hold notification: %w", err)
+		}
+		return true, nil
+	}
+	return false, notifier.Notify(ctx, user, &copied)
+}
+
+// Notify sends n to the user on every channel their preferences allow for its type
+// Failures are logged per channel; the first one is returned
+func (d *Dispatcher) Notify(ctx context.Context, user *domain.User, n *domain.Notification) error {
+	prefs := d.Preferences(ctx, user.ID)
+	now := time.Now()
+
+	var firstErr error
+	for _, channel := range d.Channels(prefs, n.Type) {
+		if _, err := d.Deliver(ctx, user, prefs, channel, n, now); err != nil {
+			logger.Warnf("failed to send %s notification to user %d: %v", channel, user.ID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// Start flushes held pushes every minute until ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.FlushHeld(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Errorf("Held notification flush failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FlushHeld delivers the pushes of users whose quiet hours are over at now.
+// In-app notifications are stored one by one; email and webhook pushes are
+// combined into a single message per channel. Failed pushes are held again.
+func (d *Dispatcher) FlushHeld(ctx context.Context, now time.Time) error {
+	locked, err := d.locker.Lock(ctx, flushLockKey, flushInterval)
+	if err != nil {
+		return fmt.Errorf("failed to acquire flush lock: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		if err := d.locker.Unlock(context.Background(), flushLockKey); err != nil {
+			logger.Warnf("failed to release flush lock: %v", err)
+		}
+	}()
+
+	userIDs, err := d.heldRepo.ListUserIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list held notifications: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		prefs := d.Preferences(ctx, userID)
+		if InQuietHours(prefs.QuietHours, now, d.loc) {
+			continue
+		}
+		if err := d.flushUser(ctx, userID); err != nil {
+			logger.Warnf("failed to flush held notifications of user %d: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// flushUser 补发单个用户暂存的推送
+func (d *Dispatcher) flushUser(ctx context.Context, userID int64) error {
+	user, err := d.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	held, err := d.heldRepo.Take(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	byChannel := make(map[string][]*domain.Notification)
+	for _, h := range held {
+		byChannel[h.Channel] = append(byChannel[h.Channel], h.Notification)
+	}
+
+	for channel, notifications := range byChannel {
+		notifier, ok := d.notifiers[channel]
+		if !ok {
+			// 渠道已被停用，丢弃暂存的推送
+			continue
+		}
+
+		var failed []*domain.Notification
+		if channel == domain.NotificationChannelInApp || len(notifications) == 1 {
+			for _, n := range notifications {
+				if err := notifier.Notify(ctx, user, n); err != nil {
+					logger.Warnf("failed to send held %s notification to user %d: %v", channel, userID, err)
+					failed = append(failed, n)
+				}
+			}
+		} else if err := notifier.Notify(ctx, user, batchNotification(notifications)); err != nil {
+			logger.Warnf("failed to send held %s notifications to user %d: %v", channel, userID, err)
+			failed = notifications
+		}
+
+		for _, n := range failed {
+			if err := d.heldRepo.Hold(context.Background(), userID, channel, n); err != nil {
+				logger.Errorf("failed to re-hold %s notification of user %d: %v", channel, userID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// batchNotification 将免打扰期间暂存的多条推送合并为一条
+func batchNotification(notifications []*domain.Notification) *domain.Notification {
+	lines := make([]string, 0, len(notifications)*3)
+	items := make([]map[string]interface{}, 0, len(notifications))
+	for _, n := range notifications {
+		lines = append(lines, "• "+n.Title, "  "+n.Body)
+		if n.URL != "" {
+			lines = append(lines, "  "+n.URL)
+		}
+		items = append(items, map[string]interface{}{
+			"type":           n.Type,
+			"title":          n.Title,
+			"body":           n.Body,
+			"url":            n.URL,
+			"opportunity_id": n.OpportunityID,
+			"data":           n.Data,
+		})
+	}
+
+	return &domain.Notification{
+		UserID: notifications[0].UserID,
+		Type:   domain.NotificationTypeBatch,
+		Title:  fmt.Sprintf("免打扰期间的%d条通知", len(notifications)),
+		Body:   strings.Join(lines, "\n"),
+		Data:   domain.JSONB{"items": items},
+	}
+}
+
+// InQuietHours reports whether now falls inside the quiet hours, evaluated in
+// their timezone (defaultLoc when unset). A range whose start is later than its
+// end spans midnight.
+func InQuietHours(q domain.QuietHours, now time.Time, defaultLoc *time.Location) bool {
+	if !q.Enabled {
+		return false
+	}
+	start, err := ParseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(q.End)
+	if err != nil {
+		return false
+	}
+
+	loc := defaultLoc
+	if q.Timezone != "" {
+		if l, err := time.LoadLocation(q.Timezone); err == nil {
+			loc = l
+		}
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// ParseClock parses "HH:MM" into minutes after midnight
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
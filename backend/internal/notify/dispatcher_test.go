@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+func TestInQuietHours(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 3, 10, hour, minute, 0, 0, shanghai)
+	}
+	quiet := func(start, end, timezone string) domain.QuietHours {
+		return domain.QuietHours{Enabled: true, Start: start, End: end, Timezone: timezone}
+	}
+
+	tests := []struct {
+		name string
+		q    domain.QuietHours
+		now  time.Time
+		want bool
+	}{
+		{"disabled", domain.QuietHours{Start: "00:00", End: "23:59"}, at(12, 0), false},
+		{"same day inside", quiet("12:00", "14:00", ""), at(13, 0), true},
+		{"same day at start", quiet("12:00", "14:00", ""), at(12, 0), true},
+		{"same day at end", quiet("12:00", "14:00", ""), at(14, 0), false},
+		{"same day before", quiet("12:00", "14:00", ""), at(11, 59), false},
+		{"across midnight evening", quiet("22:00", "07:00", ""), at(23, 30), true},
+		{"across midnight at midnight", quiet("22:00", "07:00", ""), at(0, 0), true},
+		{"across midnight morning", quiet("22:00", "07:00", ""), at(6, 59), true},
+		{"across midnight at end", quiet("22:00", "07:00", ""), at(7, 0), false},
+		{"across midnight daytime", quiet("22:00", "07:00", ""), at(12, 0), false},
+		{"start equals end", quiet("08:00", "08:00", ""), at(8, 0), false},
+		{"start equals end elsewhere", quiet("08:00", "08:00", ""), at(20, 0), false},
+		{"invalid start", quiet("25:00", "07:00", ""), at(23, 0), false},
+		{"invalid end", quiet("22:00", "7am", ""), at(23, 0), false},
+		// 13:00 Asia/Shanghai = 05:00 UTC
+		{"user timezone", quiet("04:00", "06:00", "UTC"), at(13, 0), true},
+		{"user timezone outside", quiet("12:00", "14:00", "UTC"), at(13, 0), false},
+		{"invalid timezone falls back", quiet("12:00", "14:00", "Mars/Olympus"), at(13, 0), true},
+		{"invalid timezone outside", quiet("04:00", "06:00", "Mars/Olympus"), at(13, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InQuietHours(tt.q, tt.now, shanghai); got != tt.want {
+				t.Errorf("InQuietHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"00:00", 0, false},
+		{"07:30", 450, false},
+		{"23:59", 1439, false},
+		{"24:00", 0, true},
+		{"7:30", 450, false},
+		{"7:3", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseClock(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseClock(%q) = (%d, %v), want (%d, error %v)", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// recordingNotifier 记录调用方式的测试渠道
+type recordingNotifier struct {
+	channel  string
+	notified int
+	silent   int
+}
+
+func (n *recordingNotifier) Channel() string { return n.channel }
+
+func (n *recordingNotifier) Notify(ctx context.Context, user *domain.User, notification *domain.Notification) error {
+	n.notified++
+	return nil
+}
+
+func (n *recordingNotifier) NotifySilently(ctx context.Context, user *domain.User, notification *domain.Notification) error {
+	n.silent++
+	return nil
+}
+
+func TestDeliverInAppDuringQuietHours(t *testing.T) {
+	user := &domain.User{ID: 1}
+	n := &domain.Notification{Type: domain.NotificationTypeStatusChange, Title: "t"}
+	now := time.Date(2025, 3, 10, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		quiet        domain.QuietHours
+		wantNotified int
+		wantSilent   int
+	}{
+		{"outside quiet hours", domain.QuietHours{}, 1, 0},
+		{"inside quiet hours", domain.QuietHours{Enabled: true, Start: "22:00", End: "07:00"}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inApp := &recordingNotifier{channel: domain.NotificationChannelInApp}
+			d := &Dispatcher{
+				notifiers: map[string]Notifier{domain.NotificationChannelInApp: inApp},
+				loc:       time.UTC,
+			}
+			prefs := &domain.NotificationPreferences{QuietHours: tt.quiet}
+			held, err := d.Deliver(context.Background(), user, prefs, domain.NotificationChannelInApp, n, now)
+			if err != nil {
+				t.Fatalf("Deliver() error = %v", err)
+			}
+			if held {
+				t.Errorf("Deliver() held = true, want in-app delivered immediately")
+			}
+			if inApp.notified != tt.wantNotified || inApp.silent != tt.wantSilent {
+				t.Errorf("Deliver() calls = (notify %d, silent %d), want (%d, %d)", inApp.notified, inApp.silent, tt.wantNotified, tt.wantSilent)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Package notify delivers user notifications over pluggable channels.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/mail"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of a webhook request body
+const SignatureHeader = "X-UniFocus-Signature"
+
+// Notifier delivers a notification to a user over one channel
+type Notifier interface {
+	Channel() string
+	Notify(ctx context.Context, user *domain.User, n *domain.Notification) error
+}
+
+// New creates the notifiers for the configured channels
+func New(cfg *config.Config, sender mail.Sender, inApp *InAppNotifier) []Notifier {
+	notifiers := make([]Notifier, 0, len(cfg.Notification.Channels))
+	for _, channel := range cfg.Notification.Channels {
+		switch channel {
+		case domain.NotificationChannelInApp:
+			notifiers = append(notifiers, inApp)
+		case domain.NotificationChannelEmail:
+			notifiers = append(notifiers, NewEmailNotifier(sender))
+		case domain.NotificationChannelWebhook:
+			notifiers = append(notifiers, NewWebhookNotifier(cfg.Notification.Webhook))
+		}
+	}
+	return notifiers
+}
+
+// InAppNotifier stores notifications for the in-app notification center and
+// pushes them to the user's live connections
+type InAppNotifier struct {
+	notificationRepo *postgres.NotificationRepository
+	broker           *Broker
+}
+
+// NewInAppNotifier creates a new in-app notifier
+func NewInAppNotifier(notificationRepo *postgres.NotificationRepository, broker *Broker) *InAppNotifier {
+	return &InAppNotifier{notificationRepo: notificationRepo, broker: broker}
+}
+
+// Channel returns the channel name
+func (n *InAppNotifier) Channel() string {
+	return domain.NotificationChannelInApp
+}
+
+// Notify stores the notification and publishes it for live delivery
+// A failed publish is only logged: the notification is already in the user's list
+func (n *InAppNotifier) Notify(ctx context.Context, user *domain.User, notification *domain.Notification) error {
+	if err := n.NotifySilently(ctx, user, notification); err != nil {
+		return err
+	}
+	if err := n.broker.Publish(ctx, notification); err != nil {
+		logger.Warnf("failed to publish notification %d: %v", notification.ID, err)
+	}
+	return nil
+}
+
+// NotifySilently stores the notification without pushing it to live connections;
+// the user sees it the next time the notification list is loaded
+func (n *InAppNotifier) NotifySilently(ctx context.Context, user *domain.User, notification *domain.Notification) error {
+	notification.UserID = user.ID
+	if err := n.notificationRepo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// EmailNotifier sends notifications by email
+type EmailNotifier struct {
+	sender mail.Sender
+}
+
+// NewEmailNotifier creates a new email notifier
+func NewEmailNotifier(sender mail.Sender) *EmailNotifier {
+	return &EmailNotifier{sender: sender}
+}
+
+// Channel returns the channel name
+func (n *EmailNotifier) Channel() string {
+	return domain.NotificationChannelEmail
+}
+
+// Notify emails the notification to the user's address
+func (n *EmailNotifier) Notify(ctx context.Context, user *domain.User, notification *domain.Notification) error {
+	if user.Email == "" {
+		return fmt.Errorf("user %d has no email address", user.ID)
+	}
+
+	lines := []string{notification.Body}
+	if notification.URL != "" {
+		lines = append(lines, "", notification.URL)
+	}
+
+	msg := &mail.Message{
+		To:      []string{user.Email},
+		Subject: notification.Title,
+		Text:    strings.Join(lines, "\n"),
+	}
+	if err := n.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to a configured URL
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(cfg config.WebhookConfig) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    cfg.URL,
+		secret: cfg.Secret,
+		client: &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+// Channel returns the channel name
+func (n *WebhookNotifier) Channel() string {
+	return domain.NotificationChannelWebhook
+}
+
+// webhookPayload 推送到Webhook的请求体
+type webhookPayload struct {
+	UserID        int64        `json:"user_id"`
+	Email         string       `json:"email"`
+	Type          string       `json:"type"`
+	Title         string       `json:"title"`
+	Body          string       `json:"body"`
+	URL           string       `json:"url,omitempty"`
+	OpportunityID *int64       `json:"opportunity_id,omitempty"`
+	Data          domain.JSONB `json:"data,omitempty"`
+	SentAt        time.Time    `json:"sent_at"`
+}
+
+// Notify posts the notification; any non-2xx response is an error
+func (n *WebhookNotifier) Notify(ctx context.Context, user *domain.User, notification *domain.Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		UserID:        user.ID,
+		Email:         user.Email,
+		Type:          notification.Type,
+		Title:         notification.Title,
+		Body:          notification.Body,
+		URL:           notification.URL,
+		OpportunityID: notification.OpportunityID,
+		Data:          notification.Data,
+		SentAt:        time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns "sha256=" followed by the hex HMAC-SHA256 of body, as sent in SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,148 @@
+// Package ratelimit 基于Redis有序集合的滑动窗口限流，以及登录防暴力破解
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/unifocus/backend/internal/repository/redis"
+)
+
+// allowScript 清理窗口外的记录后计数，未超限时记入本次请求；
+// 超限时返回最早一条记录离开窗口还需的毫秒数
+var allowScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, count + 1, 0}
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, count, tonumber(oldest[2]) + window - now}
+`)
+
+// recordScript 记入一次事件并返回窗口内的事件数
+var recordScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+redis.call('ZADD', KEYS[1], now, ARGV[3])
+redis.call('PEXPIRE', KEYS[1], window)
+return redis.call('ZCARD', KEYS[1])
+`)
+
+// peekScript 返回窗口内的事件数与最近一次事件的时间（毫秒），不记入新事件
+var peekScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count == 0 then
+	return {0, 0}
+end
+local latest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+return {count, tonumber(latest[2])}
+`)
+
+// LimitError 请求被限流，RetryAfter 之后可以重试
+type LimitError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return e.Reason
+}
+
+// RetryAfterSeconds 返回向上取整的等待秒数，用于 Retry-After 响应头
+func (e *LimitError) RetryAfterSeconds() int {
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// Result 一次限流检查的结果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // 仅在被拒绝时有值
+}
+
+// Limiter 滑动窗口限流器，每个键对应一个有序集合，成员为请求时间
+type Limiter struct {
+	rdb *redis.Client
+}
+
+// NewLimiter creates a new sliding window limiter
+func NewLimiter(rdb *redis.Client) *Limiter {
+	return &Limiter{rdb: rdb}
+}
+
+// Allow 检查 key 在 window 内的请求数是否少于 limit，允许时计入本次请求
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	now := time.Now()
+	values, err := allowScript.Run(ctx, l.rdb.Client, []string{l.rdb.Key(key)},
+		now.UnixMilli(), window.Milliseconds(), limit, member(now)).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	result := &Result{
+		Allowed: values[0] == 1,
+		Limit:   limit,
+	}
+	if result.Allowed {
+		result.Remaining = limit - int(values[1])
+	} else {
+		result.RetryAfter = time.Duration(values[2]) * time.Millisecond
+	}
+	return result, nil
+}
+
+// Record 记入一次事件（如登录失败），返回窗口内的事件数
+func (l *Limiter) Record(ctx context.Context, key string, window time.Duration) (int, error) {
+	now := time.Now()
+	count, err := recordScript.Run(ctx, l.rdb.Client, []string{l.rdb.Key(key)},
+		now.UnixMilli(), window.Milliseconds(), member(now)).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record event: %w", err)
+	}
+	return count, nil
+}
+
+// Peek 返回窗口内的事件数与最近一次事件的时间
+func (l *Limiter) Peek(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	values, err := peekScript.Run(ctx, l.rdb.Client, []string{l.rdb.Key(key)},
+		time.Now().UnixMilli(), window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to read events: %w", err)
+	}
+	if values[0] == 0 {
+		return 0, time.Time{}, nil
+	}
+	return int(values[0]), time.UnixMilli(values[1]), nil
+}
+
+// Reset 清空 key 的记录
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.rdb.Delete(ctx, key)
+}
+
+// member 生成有序集合成员，同一毫秒内的多个请求也不会互相覆盖
+func member(now time.Time) string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%d-%s", now.UnixNano(), hex.EncodeToString(b))
+}
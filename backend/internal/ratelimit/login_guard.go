@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// 登录失败记录与锁定标记的Redis键
+const (
+	accountFailuresKey = "ratelimit:login:fail:account:%s"
+	ipFailuresKey      = "ratelimit:login:fail:ip:%s"
+	accountLockKey     = "ratelimit:login:lock:account:%s"
+	ipLockKey          = "ratelimit:login:lock:ip:%s"
+)
+
+// baseDelay 超过免等待次数后第一次重试需等待的时间，之后逐次翻倍
+const baseDelay = time.Second
+
+// LoginGuard 登录防暴力破解：按账号与IP分别统计滑动窗口内的失败次数，
+// 超过各自的免等待次数后要求渐进等待，达到阈值后临时锁定。
+// Redis不可用时放行，只记录日志，避免限流组件故障导致无法登录
+type LoginGuard struct {
+	limiter *Limiter
+	rdb     *redis.Client
+	cfg     config.LoginProtectionConfig
+}
+
+// NewLoginGuard creates a new login guard
+func NewLoginGuard(cfg config.LoginProtectionConfig, rdb *redis.Client) *LoginGuard {
+	return &LoginGuard{
+		limiter: NewLimiter(rdb),
+		rdb:     rdb,
+		cfg:     cfg,
+	}
+}
+
+// Check 在校验密码之前调用；账号或IP被锁定、或尚未到达渐进等待时间时返回 *LimitError
+func (g *LoginGuard) Check(ctx context.Context, account, ip string) error {
+	if !g.enabled() {
+		return nil
+	}
+	account = normalizeAccount(account)
+
+	lockKeys := []string{fmt.Sprintf(accountLockKey, account)}
+	if ip != "" {
+		lockKeys = append(lockKeys, fmt.Sprintf(ipLockKey, ip))
+	}
+	for _, key := range lockKeys {
+		ttl, err := g.rdb.PTTL(ctx, g.rdb.Key(key)).Result()
+		if err != nil {
+			logger.Warnf("[SECURITY] failed to check login lockout %s: %v", key, err)
+			return nil
+		}
+		if ttl > 0 {
+			return &LimitError{Reason: "too many failed login attempts, try again later", RetryAfter: ttl}
+		}
+	}
+
+	if err := g.checkDelay(ctx, fmt.Sprintf(accountFailuresKey, account), g.cfg.FreeAttempts, account); err != nil {
+		return err
+	}
+	if ip != "" {
+		return g.checkDelay(ctx, fmt.Sprintf(ipFailuresKey, ip), g.cfg.IPFreeAttempts, "ip="+ip)
+	}
+	return nil
+}
+
+// checkDelay 距最近一次失败尚未到达渐进等待时间时返回 *LimitError
+func (g *LoginGuard) checkDelay(ctx context.Context, failuresKey string, free int, subject string) error {
+	failures, latest, err := g.limiter.Peek(ctx, failuresKey, g.cfg.GetWindow())
+	if err != nil {
+		logger.Warnf("[SECURITY] failed to check login failures for %s: %v", subject, err)
+		return nil
+	}
+	if wait := time.Until(latest.Add(g.delay(failures, free))); failures > 0 && wait > 0 {
+		return &LimitError{Reason: "too many failed login attempts, slow down", RetryAfter: wait}
+	}
+	return nil
+}
+
+// Fail 记录一次失败的登录；本次失败触发锁定时返回 *LimitError
+func (g *LoginGuard) Fail(ctx context.Context, account, ip string) error {
+	if !g.enabled() {
+		return nil
+	}
+	account = normalizeAccount(account)
+
+	if ip != "" {
+		if err := g.recordFailure(ctx, fmt.Sprintf(ipFailuresKey, ip), fmt.Sprintf(ipLockKey, ip), g.cfg.IPMaxFailures, "ip="+ip+" last_account="+account); err != nil {
+			return err
+		}
+	}
+	return g.recordFailure(ctx, fmt.Sprintf(accountFailuresKey, account), fmt.Sprintf(accountLockKey, account), g.cfg.MaxFailures, "account="+account+" ip="+ip)
+}
+
+// recordFailure 记入一次失败，达到 threshold 时设置锁定标记并记录安全事件
+func (g *LoginGuard) recordFailure(ctx context.Context, failuresKey, lockKey string, threshold int, subject string) error {
+	window := g.cfg.GetWindow()
+	failures, err := g.limiter.Record(ctx, failuresKey, window)
+	if err != nil {
+		logger.Warnf("[SECURITY] failed to record login failure (%s): %v", subject, err)
+		return nil
+	}
+	if failures < threshold {
+		return nil
+	}
+
+	lockout := g.cfg.GetLockout()
+	if err := g.rdb.Set(ctx, lockKey, time.Now().UTC().Format(time.RFC3339), lockout); err != nil {
+		logger.Warnf("[SECURITY] failed to set login lockout (%s): %v", subject, err)
+		return nil
+	}
+	// 锁定期间不再累计，解锁后重新计数
+	_ = g.limiter.Reset(ctx, failuresKey)
+	logger.Warnf("[SECURITY] login locked out: %s failures=%d window=%s lockout=%s", subject, failures, window, lockout)
+	return &LimitError{Reason: "too many failed login attempts, try again later", RetryAfter: lockout}
+}
+
+// Succeed 登录成功后清空账号的失败记录；IP的记录保留，防止用自己的账号重置计数
+func (g *LoginGuard) Succeed(ctx context.Context, account string) {
+	if !g.enabled() {
+		return
+	}
+	account = normalizeAccount(account)
+	if err := g.limiter.Reset(ctx, fmt.Sprintf(accountFailuresKey, account)); err != nil {
+		logger.Warnf("[SECURITY] failed to reset login failures for %s: %v", account, err)
+	}
+}
+
+// delay 返回失败 failures 次后距最近一次失败需等待的时间：
+// 前 free 次不等待，之后从 baseDelay 起逐次翻倍，不超过 MaxDelaySeconds
+func (g *LoginGuard) delay(failures, free int) time.Duration {
+	return ProgressiveDelay(failures, free, baseDelay, time.Duration(g.cfg.MaxDelaySeconds)*time.Second)
+}
+
+func (g *LoginGuard) enabled() bool {
+	return g != nil && g.cfg.Enabled && g.rdb != nil
+}
+
+// ProgressiveDelay 计算渐进等待时间：失败次数不超过 free 时为0，
+// 之后为 base * 2^(failures-free-1)，不超过 max
+func ProgressiveDelay(failures, free int, base, max time.Duration) time.Duration {
+	extra := failures - free
+	if extra <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < extra; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+func normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
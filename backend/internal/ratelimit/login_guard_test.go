@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgressiveDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		free     int
+		base     time.Duration
+		max      time.Duration
+		want     time.Duration
+	}{
+		{name: "no failures", failures: 0, free: 3, base: time.Second, max: time.Minute, want: 0},
+		{name: "within free attempts", failures: 3, free: 3, base: time.Second, max: time.Minute, want: 0},
+		{name: "first delayed attempt", failures: 4, free: 3, base: time.Second, max: time.Minute, want: time.Second},
+		{name: "doubles", failures: 5, free: 3, base: time.Second, max: time.Minute, want: 2 * time.Second},
+		{name: "doubles again", failures: 7, free: 3, base: time.Second, max: time.Minute, want: 8 * time.Second},
+		{name: "capped", failures: 10, free: 3, base: time.Second, max: time.Minute, want: time.Minute},
+		{name: "cap reached exactly", failures: 5, free: 0, base: time.Second, max: 16 * time.Second, want: 16 * time.Second},
+		{name: "no free attempts", failures: 1, free: 0, base: time.Second, max: time.Minute, want: time.Second},
+		{name: "base above cap", failures: 4, free: 3, base: 2 * time.Minute, max: time.Minute, want: time.Minute},
+		{name: "many failures do not overflow", failures: 1000, free: 3, base: time.Second, max: time.Minute, want: time.Minute},
+		{name: "ip threshold", failures: 16, free: 15, base: time.Second, max: time.Minute, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProgressiveDelay(tt.failures, tt.free, tt.base, tt.max); got != tt.want {
+				t.Errorf("ProgressiveDelay(%d, %d, %v, %v) = %v, want %v", tt.failures, tt.free, tt.base, tt.max, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+// Package recurrence parses schedule recurrence rules and expands them into
+// concrete occurrences.
+//
+// Two formats are accepted:
+//   - the compact format stored by earlier clients, e.g. "WEEKLY_MON_WED",
+//     "BIWEEKLY_TUE", "DAILY" or "MONTHLY";
+//   - RFC 5545 RRULE values, with or without the "RRULE:" prefix, e.g.
+//     "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE;UNTIL=20250630T000000Z".
+//
+// Only the subset of RRULE used by timetables is supported: FREQ
+// (DAILY/WEEKLY/MONTHLY), INTERVAL, COUNT, UNTIL, BYDAY (without ordinal
+// prefixes) and BYMONTHDAY. WKST is accepted but weeks always start on Monday.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 重复频率
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// maxSpanDays 单次展开的最大天数，防止异常规则导致无限迭代
+const maxSpanDays = 3660
+
+// ErrInvalidRule indicates a recurrence rule cannot be parsed
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// weekdayCodes RRULE星期代码
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// weekdayNames 紧凑格式星期名称
+var weekdayNames = map[string]time.Weekday{
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+	"SUN": time.Sunday,
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int        // 0表示不限次数
+	Until      *time.Time // 含当天
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// Bounds limits an expansion, e.g. to a semester, and removes excluded days
+type Bounds struct {
+	Start    *time.Time           // 首个允许的日期（含）
+	End      *time.Time           // 最后允许的日期（含）
+	Excluded func(time.Time) bool // 返回true的日期被跳过（如节假日、调课）
+}
+
+// Parse parses a rule in the compact or RFC 5545 format
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	upper := strings.ToUpper(value)
+	if strings.HasPrefix(upper, "RRULE:") || strings.Contains(upper, "FREQ=") {
+		return parseRRule(strings.TrimPrefix(upper, "RRULE:"))
+	}
+	return parseCompact(upper)
+}
+
+// parseCompact parses rules like WEEKLY_MON_WED or BIWEEKLY_FRI
+func parseCompact(value string) (*Rule, error) {
+	parts := strings.Split(value, "_")
+	rule := &Rule{Interval: 1}
+
+	switch parts[0] {
+	case "DAILY":
+		rule.Freq = FreqDaily
+	case "WEEKLY":
+		rule.Freq = FreqWeekly
+	case "BIWEEKLY":
+		rule.Freq = FreqWeekly
+		rule.Interval = 2
+	case "MONTHLY":
+		rule.Freq = FreqMonthly
+	default:
+		return nil, fmt.Errorf("%w: unknown frequency %q", ErrInvalidRule, parts[0])
+	}
+
+	for _, name := range parts[1:] {
+		day, ok := weekdayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown weekday %q", ErrInvalidRule, name)
+		}
+		rule.ByDay = append(rule.ByDay, day)
+	}
+
+	if len(rule.ByDay) > 0 && rule.Freq != FreqWeekly {
+		return nil, fmt.Errorf("%w: weekdays are only allowed for weekly rules", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// parseRRule parses the value of an RFC 5545 RRULE property
+func parseRRule(value string) (*Rule, error) {
+	rule := &Rule{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		key, val := kv[0], kv[1]
+
+		switch key {
+		case "FREQ":
+			if val != FreqDaily && val != FreqWeekly && val != FreqMonthly {
+				return nil, fmt.Errorf("%w: unsupported frequency %q", ErrInvalidRule, val)
+			}
+			rule.Freq = val
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: invalid interval %q", ErrInvalidRule, val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: invalid count %q", ErrInvalidRule, val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day, ok := weekdayCodes[code]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY %q", ErrInvalidRule, code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, s := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(s)
+				if err != nil || n < 1 || n > 31 {
+					return nil, fmt.Errorf("%w: unsupported BYMONTHDAY %q", ErrInvalidRule, s)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			// 周起始日固定为周一
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// parseUntil accepts DATE and DATE-TIME (UTC or floating) values
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid UNTIL %q", ErrInvalidRule, value)
+}
+
+// String formats the rule as an RFC 5545 RRULE value (without the "RRULE:" prefix)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			codes[i] = strings.ToUpper(day.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the start times of the occurrences that begin in [from, to)
+// The first occurrence is anchored at dtstart; every occurrence keeps its clock time.
+// COUNT is applied before bounds and exclusions, as EXDATE is in RFC 5545.
+func (r *Rule) Between(dtstart, from, to time.Time, bounds Bounds) []time.Time {
+	if !to.After(from) {
+		return nil
+	}
+
+	loc := dtstart.Location()
+	first := dateOf(dtstart)
+
+	last := dateOf(to.In(loc))
+	if r.Until != nil {
+		if until := dateOf(r.Until.In(loc)); until.Before(last) {
+			last = until
+		}
+	}
+	if bounds.End != nil {
+		if end := dateOf(bounds.End.In(loc)); end.Before(last) {
+			last = end
+		}
+	}
+	if limit := first.AddDate(0, 0, maxSpanDays); limit.Before(last) {
+		last = limit
+	}
+
+	var occurrences []time.Time
+	matched := 0
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !r.matches(first, day) {
+			continue
+		}
+
+		matched++
+		if r.Count > 0 && matched > r.Count {
+			break
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day(),
+			dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, loc)
+		if start.Before(from) || !start.Before(to) {
+			continue
+		}
+		if r.Until != nil && start.After(*r.Until) && !isDateOnly(*r.Until) {
+			continue
+		}
+		if bounds.Start != nil && day.Before(dateOf(bounds.Start.In(loc))) {
+			continue
+		}
+		if bounds.Excluded != nil && bounds.Excluded(day) {
+			continue
+		}
+
+		occurrences = append(occurrences, start)
+	}
+
+	return occurrences
+}
+
+// matches reports whether day is an occurrence day of a rule anchored at first
+func (r *Rule) matches(first, day time.Time) bool {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		days := int(day.Sub(first).Hours()/24 + 0.5)
+		return days%interval == 0 && r.matchesWeekday(day, true)
+	case FreqWeekly:
+		weeks := int(weekStart(day).Sub(weekStart(first)).Hours()/(24*7) + 0.5)
+		if weeks%interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == first.Weekday()
+		}
+		return r.matchesWeekday(day, false)
+	case FreqMonthly:
+		months := (day.Year()-first.Year())*12 + int(day.Month()) - int(first.Month())
+		if months%interval != 0 {
+			return false
+		}
+		if len(r.ByMonthDay) == 0 {
+			return day.Day() == first.Day()
+		}
+		for _, d := range r.ByMonthDay {
+			if day.Day() == d {
+				return r.matchesWeekday(day, true)
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+// matchesWeekday checks BYDAY; an empty list matches when allowEmpty is set
+func (r *Rule) matchesWeekday(day time.Time, allowEmpty bool) bool {
+	if len(r.ByDay) == 0 {
+		return allowEmpty
+	}
+	for _, wd := range r.ByDay {
+		if day.Weekday() == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// dateOf truncates t to midnight in its own location
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekStart returns the Monday of the week containing t
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return dateOf(t).AddDate(0, 0, -offset)
+}
+
+// isDateOnly reports whether an UNTIL value carried no time of day
+func isDateOnly(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0
+}
@@ -0,0 +1,161 @@
+package recurrence
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var cst = time.FixedZone("CST", 8*3600)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, cst)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func dates(times []time.Time) []string {
+	result := make([]string, len(times))
+	for i, t := range times {
+		result[i] = t.Format("2006-01-02")
+	}
+	return result
+}
+
+func TestBetween(t *testing.T) {
+	// 2025-03-03 是周一，学期为 03-03 至 03-30 共四周
+	dtstart := time.Date(2025, 3, 3, 8, 0, 0, 0, cst)
+	semesterStart, semesterEnd := date("2025-03-03"), date("2025-03-30")
+	semester := Bounds{Start: &semesterStart, End: &semesterEnd}
+	lateStart := date("2025-03-10")
+	excluded := func(days ...string) func(time.Time) bool {
+		return func(day time.Time) bool {
+			for _, d := range days {
+				if day.Format("2006-01-02") == d {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		from, to string
+		bounds   Bounds
+		want     []string
+	}{
+		{
+			name: "weekly within semester", rule: "WEEKLY_MON_WED",
+			from: "2025-03-01", to: "2025-05-01", bounds: semester,
+			want: []string{"2025-03-03", "2025-03-05", "2025-03-10", "2025-03-12", "2025-03-17", "2025-03-19", "2025-03-24", "2025-03-26"},
+		},
+		{
+			name: "biweekly", rule: "BIWEEKLY_MON",
+			from: "2025-03-01", to: "2025-05-01", bounds: semester,
+			want: []string{"2025-03-03", "2025-03-17"},
+		},
+		{
+			name: "semester starts after dtstart", rule: "FREQ=WEEKLY;BYDAY=MO",
+			from: "2025-03-01", to: "2025-05-01", bounds: Bounds{Start: &lateStart, End: &semesterEnd},
+			want: []string{"2025-03-10", "2025-03-17", "2025-03-24"},
+		},
+		{
+			name: "excluded holiday", rule: "RRULE:FREQ=WEEKLY;BYDAY=MO",
+			from: "2025-03-01", to: "2025-05-01",
+			bounds: Bounds{Start: &semesterStart, End: &semesterEnd, Excluded: excluded("2025-03-10")},
+			want:   []string{"2025-03-03", "2025-03-17", "2025-03-24"},
+		},
+		{
+			name: "count applies before exclusions", rule: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3",
+			from: "2025-03-01", to: "2025-05-01",
+			bounds: Bounds{Excluded: excluded("2025-03-05")},
+			want:   []string{"2025-03-03", "2025-03-10"},
+		},
+		{
+			name: "until date is inclusive", rule: "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20250312",
+			from: "2025-03-01", to: "2025-05-01", bounds: semester,
+			want: []string{"2025-03-03", "2025-03-05", "2025-03-10", "2025-03-12"},
+		},
+		{
+			name: "window is half open", rule: "WEEKLY_MON_WED",
+			from: "2025-03-10", to: "2025-03-17", bounds: semester,
+			want: []string{"2025-03-10", "2025-03-12"},
+		},
+		{
+			name: "daily on weekends", rule: "FREQ=DAILY;BYDAY=SA,SU",
+			from: "2025-03-01", to: "2025-05-01", bounds: semester,
+			want: []string{"2025-03-08", "2025-03-09", "2025-03-15", "2025-03-16", "2025-03-22", "2025-03-23", "2025-03-29", "2025-03-30"},
+		},
+		{
+			name: "monthly by month day", rule: "FREQ=MONTHLY;BYMONTHDAY=15",
+			from: "2025-03-01", to: "2025-06-01",
+			want: []string{"2025-03-15", "2025-04-15", "2025-05-15"},
+		},
+		{
+			name: "empty window", rule: "DAILY",
+			from: "2025-03-10", to: "2025-03-10", bounds: semester,
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+
+			got := rule.Between(dtstart, date(tt.from), date(tt.to), tt.bounds)
+			if !reflect.DeepEqual(dates(got), tt.want) {
+				t.Errorf("Between() = %v, want %v", dates(got), tt.want)
+			}
+			for _, occurrence := range got {
+				if occurrence.Hour() != 8 || occurrence.Location() != cst {
+					t.Errorf("occurrence %v does not keep the clock time of dtstart", occurrence)
+				}
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "WEEKLY_MON_WED", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{value: "biweekly_fri", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR"},
+		{value: "RRULE:FREQ=DAILY;COUNT=5", want: "FREQ=DAILY;COUNT=5"},
+		{value: "FREQ=WEEKLY;WKST=SU;BYDAY=TU;UNTIL=20250630T000000Z", want: "FREQ=WEEKLY;UNTIL=20250630T000000Z;BYDAY=TU"},
+		{value: "", wantErr: true},
+		{value: "YEARLY", wantErr: true},
+		{value: "DAILY_MON", wantErr: true},
+		{value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{value: "FREQ=WEEKLY;COUNT=2;UNTIL=20250630", wantErr: true},
+		{value: "INTERVAL=2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRule) {
+					t.Errorf("Parse(%q) error = %v, want ErrInvalidRule", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.value, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
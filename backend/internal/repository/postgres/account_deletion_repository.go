@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// AccountDeletionRepository handles pending account deletion requests
+type AccountDeletionRepository struct {
+	db *DB
+}
+
+// NewAccountDeletionRepository creates a new account deletion repository
+func NewAccountDeletionRepository(db *DB) *AccountDeletionRepository {
+	return &AccountDeletionRepository{db: db}
+}
+
+// Schedule records a deletion request; an existing request keeps its original schedule
+func (r *AccountDeletionRepository) Schedule(ctx context.Context, deletion *domain.AccountDeletion, ip string) error {
+	query := `
+		INSERT INTO account_deletions (user_id, requested_at, scheduled_for, request_ip)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING requested_at, scheduled_for
+	`
+
+	return r.db.conn(ctx).QueryRowContext(ctx, query,
+		deletion.UserID,
+		deletion.RequestedAt.UTC(),
+		deletion.ScheduledFor.UTC(),
+		ip,
+	).Scan(&deletion.RequestedAt, &deletion.ScheduledFor)
+}
+
+// Get retrieves the user's pending deletion request
+func (r *AccountDeletionRepository) Get(ctx context.Context, userID int64) (*domain.AccountDeletion, error) {
+	query := `SELECT user_id, requested_at, scheduled_for FROM account_deletions WHERE user_id = $1`
+
+	deletion := &domain.AccountDeletion{}
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, userID).Scan(
+		&deletion.UserID,
+		&deletion.RequestedAt,
+		&deletion.ScheduledFor,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("deletion request not found")
+		}
+		return nil, err
+	}
+
+	return deletion, nil
+}
+
+// Cancel withdraws the user's pending deletion request
+func (r *AccountDeletionRepository) Cancel(ctx context.Context, userID int64) error {
+	result, err := r.db.conn(ctx).ExecContext(ctx, `DELETE FROM account_deletions WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("deletion request not found")
+	}
+
+	return nil
+}
+
+// ListDue lists the users whose grace period ended before now, oldest first
+func (r *AccountDeletionRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	query := `
+		SELECT user_id
+		FROM account_deletions
+		WHERE scheduled_for <= $1
+		ORDER BY scheduled_for
+		LIMIT $2
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	return userIDs, rows.Err()
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// AccountTokenRepository handles single-use email verification and password reset tokens
+type AccountTokenRepository struct {
+	db *DB
+}
+
+// NewAccountTokenRepository creates a new account token repository
+func NewAccountTokenRepository(db *DB) *AccountTokenRepository {
+	return &AccountTokenRepository{db: db}
+}
+
+// Create stores a token hash for the purpose, invalidating the user's earlier unused tokens of that purpose
+func (r *AccountTokenRepository) Create(ctx context.Context, userID int64, purpose, tokenHash string, expiresAt time.Time) error {
+	return r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM account_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`,
+			userID, purpose,
+		); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO account_tokens (user_id, purpose, token_hash, expires_at) VALUES ($1, $2, $3, $4)`,
+			userID, purpose, tokenHash, expiresAt.UTC(),
+		)
+		return err
+	})
+}
+
+// Consume marks an unused, unexpired token as used and returns its user
+func (r *AccountTokenRepository) Consume(ctx context.Context, purpose, tokenHash string) (int64, error) {
+	query := `
+		UPDATE account_tokens
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > $3
+		RETURNING user_id
+	`
+
+	var userID int64
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, tokenHash, purpose, time.Now().UTC()).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("token not found")
+		}
+		return 0, err
+	}
+
+	return userID, nil
+}
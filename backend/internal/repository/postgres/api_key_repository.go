@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// APIKeyRepository handles personal API keys
+type APIKeyRepository struct {
+	db *DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// apiKeyColumns API密钥查询列
+const apiKeyColumns = `id, user_id, name, prefix, scopes, expires_at, last_used_at, last_used_ip, created_at, revoked_at`
+
+// scanAPIKey 扫描一行API密钥记录
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	key := &domain.APIKey{}
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		pq.Array(&key.Scopes),
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.LastUsedIP,
+		&key.CreatedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Create stores a new API key by the hash of its token
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey, tokenHash string) error {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	return r.db.conn(ctx).QueryRowContext(ctx, query,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		tokenHash,
+		pq.Array(key.Scopes),
+		key.ExpiresAt.UTC(),
+	).Scan(&key.ID, &key.CreatedAt)
+}
+
+// GetByHash retrieves an unrevoked API key by the hash of its token
+func (r *APIKeyRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE token_hash = $1 AND revoked_at IS NULL`
+
+	key, err := scanAPIKey(r.db.conn(ctx).QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("api key not found")
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ListByUser lists a user's unrevoked API keys, newest first
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID int64) ([]*domain.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// CountActive counts a user's unrevoked, unexpired API keys
+func (r *APIKeyRepository) CountActive(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.conn(ctx).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM api_keys WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2`,
+		userID, time.Now().UTC(),
+	).Scan(&count)
+	return count, err
+}
+
+// Touch records a use of the API key; writes are skipped when it was used within the last minute
+func (r *APIKeyRepository) Touch(ctx context.Context, id int64, ip string) error {
+	now := time.Now().UTC()
+	_, err := r.db.conn(ctx).ExecContext(ctx, `
+		UPDATE api_keys
+		SET last_used_at = $2, last_used_ip = $3
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $4)
+	`, id, now, ip, now.Add(-time.Minute))
+	return err
+}
+
+// Revoke revokes one of the user's API keys
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID, id int64) error {
+	result, err := r.db.conn(ctx).ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		id, userID, time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("api key not found")
+	}
+
+	return nil
+}
+
+// RevokeAll revokes every API key of the user
+func (r *APIKeyRepository) RevokeAll(ctx context.Context, userID int64) error {
+	_, err := r.db.conn(ctx).ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID, time.Now().UTC(),
+	)
+	return err
+}
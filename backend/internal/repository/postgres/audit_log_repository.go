@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// AuditLogRepository handles the audit trail of account actions
+type AuditLogRepository struct {
+	db *DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// auditLogColumns 审计日志查询列
+const auditLogColumns = `id, user_id, actor_id, action, detail, ip, created_at`
+
+// scanAuditLog 扫描一行审计日志
+func scanAuditLog(row rowScanner) (*domain.AuditLog, error) {
+	entry := &domain.AuditLog{}
+	err := row.Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.ActorID,
+		&entry.Action,
+		&entry.Detail,
+		&entry.IP,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Create appends an entry to the audit trail
+func (r *AuditLogRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (user_id, actor_id, action, detail, ip)
+		VALUES ($1, $2, $3, COALESCE($4, '{}'::jsonb), $5)
+		RETURNING id, created_at
+	`
+
+	return r.db.conn(ctx).QueryRowContext(ctx, query,
+		entry.UserID,
+		entry.ActorID,
+		entry.Action,
+		entry.Detail,
+		entry.IP,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// ListByUser lists the audit entries about a user, newest first
+func (r *AuditLogRepository) ListByUser(ctx context.Context, userID int64, limit int) ([]*domain.AuditLog, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.AuditLog, 0)
+	for rows.Next() {
+		entry, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// CalendarFeedRepository handles calendar feed token data access operations
+type CalendarFeedRepository struct {
+	db *DB
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository(db *DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// Upsert stores a new token hash for the user, replacing (and thereby revoking) any previous token
+func (r *CalendarFeedRepository) Upsert(ctx context.Context, userID int64, tokenHash string) (*domain.CalendarFeed, error) {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash, last_accessed_at = NULL, created_at = CURRENT_TIMESTAMP
+		RETURNING id, user_id, token_hash, last_accessed_at, created_at
+	`
+
+	feed := &domain.CalendarFeed{}
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, userID, tokenHash).Scan(
+		&feed.ID,
+		&feed.UserID,
+		&feed.TokenHash,
+		&feed.LastAccessedAt,
+		&feed.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// GetByUserID retrieves the user's calendar feed
+func (r *CalendarFeedRepository) GetByUserID(ctx context.Context, userID int64) (*domain.CalendarFeed, error) {
+	query := `
+		SELECT id, user_id, token_hash, last_accessed_at, created_at
+		FROM calendar_feeds
+		WHERE user_id = $1
+	`
+
+	feed := &domain.CalendarFeed{}
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, userID).Scan(
+		&feed.ID,
+		&feed.UserID,
+		&feed.TokenHash,
+		&feed.LastAccessedAt,
+		&feed.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("calendar feed not found")
+		}
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// Touch resolves a token hash to its user and records the access time
+func (r *CalendarFeedRepository) Touch(ctx context.Context, tokenHash string) (int64, error) {
+	query := `
+		UPDATE calendar_feeds
+		SET last_accessed_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1
+		RETURNING user_id
+	`
+
+	var userID int64
+	if err := r.db.conn(ctx).QueryRowContext(ctx, query, tokenHash).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("calendar feed not found")
+		}
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// Delete revokes the user's calendar feed
+func (r *CalendarFeedRepository) Delete(ctx context.Context, userID int64) error {
+	result, err := r.db.conn(ctx).ExecContext(ctx, `DELETE FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("calendar feed not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// CompetitionRuleRepository handles competition level rule data access operations
+type CompetitionRuleRepository struct {
+	db *DB
+}
+
+// NewCompetitionRuleRepository creates a new competition rule repository
+func NewCompetitionRuleRepository(db *DB) *CompetitionRuleRepository {
+	return &CompetitionRuleRepository{db: db}
+}
+
+// ListActive retrieves all active competition level rules
+func (r *CompetitionRuleRepository) ListActive(ctx context.Context) ([]*domain.CompetitionLevelRule, error) {
+	query := `
+		SELECT id, competition_name, COALESCE(short_name, ''), level,
+			COALESCE(certification_source, ''), COALESCE(certification_document, ''),
+			keywords, organizer_patterns, url_patterns,
+			COALESCE(points_value, 0), COALESCE(difficulty_level, 0), COALESCE(participation_count, 0),
+			target_majors, skill_requirements, is_active, created_at, updated_at
+		FROM competition_level_rules
+		WHERE is_active = true
+		ORDER BY id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.CompetitionLevelRule
+	for rows.Next() {
+		rule := &domain.CompetitionLevelRule{}
+		err := rows.Scan(
+			&rule.ID,
+			&rule.CompetitionName,
+			&rule.ShortName,
+			&rule.Level,
+			&rule.CertificationSource,
+			&rule.CertificationDocument,
+			pq.Array(&rule.Keywords),
+			pq.Array(&rule.OrganizerPatterns),
+			pq.Array(&rule.URLPatterns),
+			&rule.PointsValue,
+			&rule.DifficultyLevel,
+			&rule.ParticipationCount,
+			pq.Array(&rule.TargetMajors),
+			pq.Array(&rule.SkillRequirements),
+			&rule.IsActive,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
@@ -3,10 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 	"github.com/unifocus/backend/internal/config"
 	"github.com/unifocus/backend/pkg/logger"
 )
@@ -58,10 +59,64 @@ func (d *DB) HealthCheck(ctx context.Context) error {
 	return d.PingContext(ctx)
 }
 
+// ExtensionInstalled reports whether a PostgreSQL extension is installed in the current database
+func (d *DB) ExtensionInstalled(ctx context.Context, name string) (bool, error) {
+	var installed bool
+	err := d.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = $1)`, name).Scan(&installed)
+	return installed, err
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is a PostgreSQL foreign key violation
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// txKey 上下文中进行中的事务
+type txKey struct{}
+
+// querier *sql.DB 与 *sql.Tx 共有的查询方法
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn 返回 ctx 中进行中的事务，没有时返回连接池
+func (d *DB) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return d.DB
+}
+
+// InTx runs fn in a database transaction. Repository calls made with the context
+// passed to fn join the transaction, so writes across repositories commit or roll
+// back together. Called inside another InTx, fn joins the outer transaction.
+func (d *DB) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	return d.Transaction(ctx, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
 // Transaction executes a function within a database transaction
 // If the function returns an error, the transaction is rolled back
 // Otherwise, the transaction is committed
-func (d *DB) Transaction(ctx context.Context, fn func(*sql.Tx) error) error {
+// When ctx already carries a transaction (see InTx), fn runs in it
+func (d *DB) Transaction(ctx context.Context, fn func(*sql.Tx) error) (err error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
 	tx, err := d.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// DigestRepository handles digest email preference data access operations
+type DigestRepository struct {
+	db *DB
+}
+
+// NewDigestRepository creates a new digest repository
+func NewDigestRepository(db *DB) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+// GetByUserID retrieves the user's digest settings; Frequency is empty when the user never chose one
+func (r *DigestRepository) GetByUserID(ctx context.Context, userID int64) (*domain.DigestRecipient, error) {
+	query := `
+		SELECT u.id, u.username, u.email, COALESCE(d.frequency, ''), d.last_sent_at
+		FROM users u
+		LEFT JOIN digest_preferences d ON d.user_id = u.id
+		WHERE u.id = $1
+	`
+
+	recipient := &domain.DigestRecipient{User: &domain.User{}}
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, userID).Scan(
+		&recipient.User.ID,
+		&recipient.User.Username,
+		&recipient.User.Email,
+		&recipient.Frequency,
+		&recipient.LastSentAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	return recipient, nil
+}
+
+// SetFrequency stores the user's digest frequency
+func (r *DigestRepository) SetFrequency(ctx context.Context, userID int64, frequency string) error {
+	query := `
+		INSERT INTO digest_preferences (user_id, frequency)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET frequency = EXCLUDED.frequency
+	`
+
+	if _, err := r.db.conn(ctx).ExecContext(ctx, query, userID, frequency); err != nil {
+		if isForeignKeyViolation(err) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// ListDue retrieves the users whose digest is due: daily subscribers last sent
+// before dailyCutoff and weekly subscribers last sent before weeklyCutoff.
+// Users without a preference follow defaultFrequency; suspended users are skipped.
+func (r *DigestRepository) ListDue(ctx context.Context, defaultFrequency string, dailyCutoff, weeklyCutoff time.Time) ([]*domain.DigestRecipient, error) {
+	query := `
+		SELECT u.id, u.username, u.email, COALESCE(d.frequency, ''), d.last_sent_at
+		FROM users u
+		LEFT JOIN digest_preferences d ON d.user_id = u.id
+		WHERE u.suspended_at IS NULL
+			AND ((COALESCE(d.frequency, $1) = 'daily' AND (d.last_sent_at IS NULL OR d.last_sent_at < $2))
+				OR (COALESCE(d.frequency, $1) = 'weekly' AND (d.last_sent_at IS NULL OR d.last_sent_at < $3)))
+		ORDER BY u.id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, defaultFrequency, dailyCutoff, weeklyCutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []*domain.DigestRecipient
+	for rows.Next() {
+		recipient := &domain.DigestRecipient{User: &domain.User{}}
+		if err := rows.Scan(
+			&recipient.User.ID,
+			&recipient.User.Username,
+			&recipient.User.Email,
+			&recipient.Frequency,
+			&recipient.LastSentAt,
+		); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+// Claim moves last_sent_at from prev to sentAt. It returns false when another
+// scanner already moved it, so each digest is sent at most once.
+func (r *DigestRepository) Claim(ctx context.Context, userID int64, prev *time.Time, sentAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO digest_preferences (user_id, last_sent_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET last_sent_at = EXCLUDED.last_sent_at
+		WHERE digest_preferences.last_sent_at IS NOT DISTINCT FROM $3
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, userID, sentAt, prev)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// Restore resets last_sent_at after a failed send so the next scan retries
+func (r *DigestRepository) Restore(ctx context.Context, userID int64, prev *time.Time) error {
+	_, err := r.db.conn(ctx).ExecContext(ctx,
+		`UPDATE digest_preferences SET last_sent_at = $2 WHERE user_id = $1`,
+		userID, prev,
+	)
+	return err
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// HeldNotification is a push held back during the user's quiet hours
+type HeldNotification struct {
+	ID           int64
+	Channel      string
+	Notification *domain.Notification
+}
+
+// HeldNotificationRepository handles pushes held back during quiet hours
+type HeldNotificationRepository struct {
+	db *DB
+}
+
+// NewHeldNotificationRepository creates a new held notification repository
+func NewHeldNotificationRepository(db *DB) *HeldNotificationRepository {
+	return &HeldNotificationRepository{db: db}
+}
+
+// Hold stores a push for later delivery on the channel
+func (r *HeldNotificationRepository) Hold(ctx context.Context, userID int64, channel string, n *domain.Notification) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.conn(ctx).ExecContext(ctx,
+		`INSERT INTO held_notifications (user_id, channel, notification) VALUES ($1, $2, $3)`,
+		userID, channel, data,
+	)
+	return err
+}
+
+// ListUserIDs returns the users that have held pushes
+func (r *HeldNotificationRepository) ListUserIDs(ctx context.Context) ([]int64, error) {
+	rows, err := r.db.conn(ctx).QueryContext(ctx, `SELECT DISTINCT user_id FROM held_notifications ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// Take removes and returns all held pushes of the user, oldest first
+// Removing them in one statement keeps concurrent flushes from delivering twice
+func (r *HeldNotificationRepository) Take(ctx context.Context, userID int64) ([]*HeldNotification, error) {
+	rows, err := r.db.conn(ctx).QueryContext(ctx,
+		`DELETE FROM held_notifications WHERE user_id = $1 RETURNING id, channel, notification`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var held []*HeldNotification
+	for rows.Next() {
+		h := &HeldNotification{Notification: &domain.Notification{}}
+		var data []byte
+		if err := rows.Scan(&h.ID, &h.Channel, &data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, h.Notification); err != nil {
+			return nil, err
+		}
+		held = append(held, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(held, func(i, j int) bool { return held[i].ID < held[j].ID })
+	return held, nil
+}
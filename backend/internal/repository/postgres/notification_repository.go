@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// NotificationRepository handles in-app notification data access operations
+type NotificationRepository struct {
+	db *DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create stores a notification and fills in its ID and creation time
+func (r *NotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, type, title, body, url, opportunity_id, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	return r.db.conn(ctx).QueryRowContext(ctx, query,
+		n.UserID,
+		n.Type,
+		n.Title,
+		n.Body,
+		n.URL,
+		n.OpportunityID,
+		n.Data,
+	).Scan(&n.ID, &n.CreatedAt)
+}
+
+// notificationColumns is the column list shared by notification queries
+const notificationColumns = `
+	id, user_id, type, title, COALESCE(body, ''), COALESCE(url, ''), opportunity_id, data, read_at, created_at
+`
+
+// scanNotification scans a row selected with notificationColumns
+func scanNotification(row rowScanner) (*domain.Notification, error) {
+	n := &domain.Notification{}
+	err := row.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.Type,
+		&n.Title,
+		&n.Body,
+		&n.URL,
+		&n.OpportunityID,
+		&n.Data,
+		&n.ReadAt,
+		&n.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// List retrieves the user's notifications, newest first
+func (r *NotificationRepository) List(ctx context.Context, userID int64, filter *domain.NotificationFilter) ([]*domain.Notification, int64, error) {
+	where := `WHERE user_id = $1`
+	args := []interface{}{userID}
+	if filter.Unread {
+		where += ` AND read_at IS NULL`
+	}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		where += fmt.Sprintf(` AND type = $%d`, len(args))
+	}
+
+	var total int64
+	if err := r.db.conn(ctx).QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	query := fmt.Sprintf(`SELECT `+notificationColumns+`
+		FROM notifications
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	notifications := []*domain.Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, total, rows.Err()
+}
+
+// ListAfter retrieves the user's notifications created after the given ID, oldest first
+// It is used to replay what a reconnecting live connection missed
+func (r *NotificationRepository) ListAfter(ctx context.Context, userID, afterID int64, limit int) ([]*domain.Notification, error) {
+	query := `SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*domain.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// ListAllByUser retrieves every notification of the user, oldest first
+func (r *NotificationRepository) ListAllByUser(ctx context.Context, userID int64) ([]*domain.Notification, error) {
+	query := `SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]*domain.Notification, 0)
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// ListSince retrieves the user's notifications of one type created after since, oldest first
+func (r *NotificationRepository) ListSince(ctx context.Context, userID int64, notificationType string, since time.Time, limit int) ([]*domain.Notification, error) {
+	query := `SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = $1 AND type = $2 AND created_at > $3
+		ORDER BY created_at, id
+		LIMIT $4
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID, notificationType, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*domain.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// CountUnread returns the number of unread notifications of the user
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := r.db.conn(ctx).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`,
+		userID,
+	).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks one of the user's notifications as read; marking it again keeps the first read time
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id int64) (*domain.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + notificationColumns
+
+	n, err := scanNotification(r.db.conn(ctx).QueryRowContext(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("notification not found")
+		}
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// MarkAllRead marks all unread notifications of the user as read and returns how many changed
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	result, err := r.db.conn(ctx).ExecContext(ctx,
+		`UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL`,
+		userID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -29,13 +29,13 @@ func (r *OpportunityRepository) Create(ctx context.Context, opp *domain.Opportun
 			competition_level, certification_type, organizer, organizer_type, award_level, points_value, is_official,
 			start_date, deadline, event_date, location,
 			requirements, eligibility_rules, target_majors,
-			tags, attachments, description_vector, is_active
+			tags, attachments, description_vector, is_active, created_by
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
 		opp.Title,
 		opp.Type,
 		opp.Description,
@@ -59,6 +59,7 @@ func (r *OpportunityRepository) Create(ctx context.Context, opp *domain.Opportun
 		opp.Attachments,
 		pq.Array(opp.DescriptionVector),
 		opp.IsActive,
+		opp.CreatedBy,
 	).Scan(&opp.ID, &opp.CreatedAt, &opp.UpdatedAt)
 
 	if err != nil {
@@ -68,24 +69,25 @@ func (r *OpportunityRepository) Create(ctx context.Context, opp *domain.Opportun
 	return nil
 }
 
-// GetByID retrieves an opportunity by ID
-func (r *OpportunityRepository) GetByID(ctx context.Context, id int64) (*domain.Opportunity, error) {
-	query := `
-		SELECT id, title, type, description, source_url, source_type,
-			competition_level, certification_type, organizer, organizer_type, award_level, points_value, is_official,
-			start_date, deadline, event_date, location,
-			requirements, eligibility_rules, target_majors,
-			tags, attachments, description_vector, is_active, view_count, save_count,
-			created_at, updated_at
-		FROM opportunities
-		WHERE id = $1
-	`
-
+// opportunityColumns is the full opportunity column list for queries aliasing opportunities as "o"
+const opportunityColumns = `
+	o.id, o.title, o.type, o.description, o.source_url, o.source_type,
+	o.competition_level, o.certification_type, o.organizer, o.organizer_type, o.award_level, o.points_value, o.is_official,
+	o.start_date, o.deadline, o.event_date, o.location,
+	o.requirements, o.eligibility_rules, o.target_majors,
+	o.tags, o.attachments, o.description_vector, o.is_active, o.view_count, o.save_count,
+	o.created_at, o.updated_at, o.created_by
+`
+
+// scanOpportunity scans a full opportunity row
+// Columns must be selected in the same order as opportunityColumns; extra
+// destinations receive any columns selected after them
+func scanOpportunity(row rowScanner, extra ...interface{}) (*domain.Opportunity, error) {
 	opp := &domain.Opportunity{}
 	var targetMajors, tags []string
 	var descriptionVector []float32
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	dest := []interface{}{
 		&opp.ID,
 		&opp.Title,
 		&opp.Type,
@@ -114,12 +116,10 @@ func (r *OpportunityRepository) GetByID(ctx context.Context, id int64) (*domain.
 		&opp.SaveCount,
 		&opp.CreatedAt,
 		&opp.UpdatedAt,
-	)
+		&opp.CreatedBy,
+	}
 
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("opportunity not found")
-		}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
@@ -130,6 +130,30 @@ func (r *OpportunityRepository) GetByID(ctx context.Context, id int64) (*domain.
 	return opp, nil
 }
 
+// GetByID retrieves an opportunity by ID
+func (r *OpportunityRepository) GetByID(ctx context.Context, id int64) (*domain.Opportunity, error) {
+	query := `
+		SELECT id, title, type, description, source_url, source_type,
+			competition_level, certification_type, organizer, organizer_type, award_level, points_value, is_official,
+			start_date, deadline, event_date, location,
+			requirements, eligibility_rules, target_majors,
+			tags, attachments, description_vector, is_active, view_count, save_count,
+			created_at, updated_at, created_by
+		FROM opportunities
+		WHERE id = $1
+	`
+
+	opp, err := scanOpportunity(r.db.conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("opportunity not found")
+		}
+		return nil, err
+	}
+
+	return opp, nil
+}
+
 // List retrieves opportunities with filtering and pagination
 func (r *OpportunityRepository) List(ctx context.Context, filter *domain.OpportunityFilter) ([]*domain.Opportunity, int64, error) {
 	var conditions []string
@@ -187,7 +211,7 @@ func (r *OpportunityRepository) List(ctx context.Context, filter *domain.Opportu
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM opportunities %s", whereClause)
 	var total int64
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	err := r.db.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -207,7 +231,7 @@ func (r *OpportunityRepository) List(ctx context.Context, filter *domain.Opportu
 			start_date, deadline, event_date, location,
 			requirements, eligibility_rules, target_majors,
 			tags, attachments, description_vector, is_active, view_count, save_count,
-			created_at, updated_at
+			created_at, updated_at, created_by
 		FROM opportunities
 		%s
 		ORDER BY created_at DESC
@@ -216,7 +240,7 @@ func (r *OpportunityRepository) List(ctx context.Context, filter *domain.Opportu
 
 	args = append(args, filter.Limit, filter.Offset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -224,49 +248,11 @@ func (r *OpportunityRepository) List(ctx context.Context, filter *domain.Opportu
 
 	var opportunities []*domain.Opportunity
 	for rows.Next() {
-		opp := &domain.Opportunity{}
-		var targetMajors, tags []string
-		var descriptionVector []float32
-
-		err := rows.Scan(
-			&opp.ID,
-			&opp.Title,
-			&opp.Type,
-			&opp.Description,
-			&opp.SourceURL,
-			&opp.SourceType,
-			&opp.CompetitionLevel,
-			&opp.CertificationType,
-			&opp.Organizer,
-			&opp.OrganizerType,
-			&opp.AwardLevel,
-			&opp.PointsValue,
-			&opp.IsOfficial,
-			&opp.StartDate,
-			&opp.Deadline,
-			&opp.EventDate,
-			&opp.Location,
-			&opp.Requirements,
-			&opp.EligibilityRules,
-			pq.Array(&targetMajors),
-			pq.Array(&tags),
-			&opp.Attachments,
-			pq.Array(&descriptionVector),
-			&opp.IsActive,
-			&opp.ViewCount,
-			&opp.SaveCount,
-			&opp.CreatedAt,
-			&opp.UpdatedAt,
-		)
-
+		opp, err := scanOpportunity(rows)
 		if err != nil {
 			return nil, 0, err
 		}
 
-		opp.TargetMajors = targetMajors
-		opp.Tags = tags
-		opp.DescriptionVector = descriptionVector
-
 		opportunities = append(opportunities, opp)
 	}
 
@@ -288,7 +274,7 @@ func (r *OpportunityRepository) Update(ctx context.Context, opp *domain.Opportun
 		RETURNING updated_at
 	`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
 		opp.Title,
 		opp.Type,
 		opp.Description,
@@ -333,7 +319,7 @@ func (r *OpportunityRepository) Delete(ctx context.Context, id int64) error {
 		WHERE id = $1
 	`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -353,13 +339,134 @@ func (r *OpportunityRepository) Delete(ctx context.Context, id int64) error {
 // IncrementViewCount increments the view count for an opportunity
 func (r *OpportunityRepository) IncrementViewCount(ctx context.Context, id int64) error {
 	query := `UPDATE opportunities SET view_count = view_count + 1 WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, id)
 	return err
 }
 
 // IncrementSaveCount increments the save count for an opportunity
 func (r *OpportunityRepository) IncrementSaveCount(ctx context.Context, id int64) error {
 	query := `UPDATE opportunities SET save_count = save_count + 1 WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, id)
 	return err
 }
+
+// ListRecommendationCandidates retrieves one page of active, unexpired opportunities
+// open to the user's grade that the user has not marked as not interested or
+// irrelevant, in id order after afterID. Major requirements are checked when scoring.
+func (r *OpportunityRepository) ListRecommendationCandidates(ctx context.Context, userID int64, grade int, afterID int64, limit int) ([]*domain.Opportunity, error) {
+	query := `SELECT ` + opportunityColumns + `
+		FROM opportunities o
+		LEFT JOIN user_opportunities uo ON uo.opportunity_id = o.id AND uo.user_id = $1
+		WHERE o.is_active = true
+			AND o.id > $3
+			AND (o.deadline IS NULL OR o.deadline >= CURRENT_DATE)
+			AND (uo.user_feedback IS NULL OR uo.user_feedback NOT IN ('not_interested', 'irrelevant'))
+			AND (jsonb_typeof(o.requirements->'grade') IS DISTINCT FROM 'array'
+				OR jsonb_array_length(o.requirements->'grade') = 0
+				OR o.requirements->'grade' @> to_jsonb($2::int))
+		ORDER BY o.id
+		LIMIT $4
+	`
+
+	return r.queryOpportunities(ctx, query, userID, grade, afterID, limit)
+}
+
+// ListByFeedback retrieves the opportunities a user gave one of the given feedbacks
+func (r *OpportunityRepository) ListByFeedback(ctx context.Context, userID int64, feedbacks []string) ([]*domain.Opportunity, error) {
+	query := `SELECT ` + opportunityColumns + `
+		FROM opportunities o
+		JOIN user_opportunities uo ON uo.opportunity_id = o.id
+		WHERE uo.user_id = $1 AND uo.user_feedback = ANY($2)
+	`
+	return r.queryOpportunities(ctx, query, userID, pq.Array(feedbacks))
+}
+
+// UpdateDescriptionVector stores the description embedding of an opportunity
+func (r *OpportunityRepository) UpdateDescriptionVector(ctx context.Context, id int64, vector []float32) error {
+	query := `UPDATE opportunities SET description_vector = $1 WHERE id = $2`
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, pq.Array(vector), id)
+	return err
+}
+
+// ListMissingVectors retrieves active opportunities that have not been vectorized yet
+func (r *OpportunityRepository) ListMissingVectors(ctx context.Context, limit int) ([]*domain.Opportunity, error) {
+	query := `SELECT ` + opportunityColumns + `
+		FROM opportunities o
+		WHERE o.is_active = true AND o.description_vector IS NULL
+		ORDER BY o.id
+		LIMIT $1
+	`
+
+	return r.queryOpportunities(ctx, query, limit)
+}
+
+// ListVectorCandidates retrieves active, unexpired opportunities whose description
+// vector has the given dimension, newest first
+func (r *OpportunityRepository) ListVectorCandidates(ctx context.Context, dim int, excludeID int64, limit int) ([]*domain.Opportunity, error) {
+	query := `SELECT ` + opportunityColumns + `
+		FROM opportunities o
+		WHERE o.is_active = true AND o.id <> $1
+			AND (o.deadline IS NULL OR o.deadline >= CURRENT_DATE)
+			AND array_length(o.description_vector, 1) = $2
+		ORDER BY o.created_at DESC
+		LIMIT $3
+	`
+
+	return r.queryOpportunities(ctx, query, excludeID, dim, limit)
+}
+
+// NearestByVector retrieves the opportunities closest to the given vector by cosine
+// distance using the pgvector extension, together with their cosine similarity
+func (r *OpportunityRepository) NearestByVector(ctx context.Context, vector []float32, excludeID int64, limit int) ([]*domain.Opportunity, []float64, error) {
+	// 维度以字面量写入，使表达式与谓词和 019_vector_index 中的HNSW部分索引一致
+	dim := len(vector)
+	query := fmt.Sprintf(`SELECT `+opportunityColumns+`,
+			1 - (o.description_vector::vector(%[1]d) <=> $1::real[]::vector(%[1]d)) AS similarity
+		FROM opportunities o
+		WHERE array_length(o.description_vector, 1) = %[1]d
+			AND o.is_active = true AND o.id <> $2
+			AND (o.deadline IS NULL OR o.deadline >= CURRENT_DATE)
+		ORDER BY o.description_vector::vector(%[1]d) <=> $1::real[]::vector(%[1]d)
+		LIMIT $3
+	`, dim)
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, pq.Array(vector), excludeID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var opportunities []*domain.Opportunity
+	var similarities []float64
+	for rows.Next() {
+		var similarity float64
+		opp, err := scanOpportunity(rows, &similarity)
+		if err != nil {
+			return nil, nil, err
+		}
+		opportunities = append(opportunities, opp)
+		similarities = append(similarities, similarity)
+	}
+
+	return opportunities, similarities, rows.Err()
+}
+
+// queryOpportunities runs a query selecting opportunityColumns and scans all rows
+func (r *OpportunityRepository) queryOpportunities(ctx context.Context, query string, args ...interface{}) ([]*domain.Opportunity, error) {
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var opportunities []*domain.Opportunity
+	for rows.Next() {
+		opp, err := scanOpportunity(rows)
+		if err != nil {
+			return nil, err
+		}
+		opportunities = append(opportunities, opp)
+	}
+
+	return opportunities, rows.Err()
+}
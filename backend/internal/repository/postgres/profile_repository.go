@@ -35,7 +35,7 @@ func (r *ProfileRepository) CreateOrUpdate(ctx context.Context, profile *domain.
 		RETURNING id, updated_at
 	`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
 		profile.UserID,
 		profile.ResumeText,
 		pq.Array(profile.Skills),
@@ -64,7 +64,7 @@ func (r *ProfileRepository) GetByUserID(ctx context.Context, userID int64) (*dom
 	var certificates []domain.Cert
 	var resumeVector []float32
 
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, userID).Scan(
 		&profile.ID,
 		&profile.UserID,
 		&profile.ResumeText,
@@ -98,7 +98,7 @@ func (r *ProfileRepository) UpdateSkills(ctx context.Context, userID int64, skil
 		WHERE user_id = $2
 	`
 
-	result, err := r.db.ExecContext(ctx, query, pq.Array(skills), userID)
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, pq.Array(skills), userID)
 	if err != nil {
 		return err
 	}
@@ -114,3 +114,75 @@ func (r *ProfileRepository) UpdateSkills(ctx context.Context, userID int64, skil
 
 	return nil
 }
+
+// UpdateResumeVector stores the resume embedding of a user
+func (r *ProfileRepository) UpdateResumeVector(ctx context.Context, userID int64, vector []float32) error {
+	query := `UPDATE user_profiles SET resume_vector = $1 WHERE user_id = $2`
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, pq.Array(vector), userID)
+	return err
+}
+
+// ListMissingResumeVectors retrieves profiles that have resume text but no resume vector
+// Only user_id and resume_text are populated
+func (r *ProfileRepository) ListMissingResumeVectors(ctx context.Context, limit int) ([]*domain.UserProfile, error) {
+	query := `
+		SELECT user_id, resume_text
+		FROM user_profiles
+		WHERE resume_vector IS NULL AND COALESCE(resume_text, '') <> ''
+		ORDER BY user_id
+		LIMIT $1
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []*domain.UserProfile
+	for rows.Next() {
+		profile := &domain.UserProfile{}
+		if err := rows.Scan(&profile.UserID, &profile.ResumeText); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, rows.Err()
+}
+
+// GetNotificationPreferences retrieves the notification preferences stored with the user's profile
+// It returns empty preferences when the user has no profile or never set them
+func (r *ProfileRepository) GetNotificationPreferences(ctx context.Context, userID int64) (*domain.NotificationPreferences, error) {
+	prefs := &domain.NotificationPreferences{}
+	err := r.db.conn(ctx).QueryRowContext(ctx,
+		`SELECT notification_preferences FROM user_profiles WHERE user_id = $1`,
+		userID,
+	).Scan(prefs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// UpdateNotificationPreferences stores the user's notification preferences, creating the profile if needed
+func (r *ProfileRepository) UpdateNotificationPreferences(ctx context.Context, userID int64, prefs *domain.NotificationPreferences) error {
+	query := `
+		INSERT INTO user_profiles (user_id, notification_preferences)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			notification_preferences = EXCLUDED.notification_preferences,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.db.conn(ctx).ExecContext(ctx, query, userID, prefs); err != nil {
+		if isForeignKeyViolation(err) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// ReminderRepository handles deadline reminder data access operations
+type ReminderRepository struct {
+	db *DB
+}
+
+// NewReminderRepository creates a new reminder repository
+func NewReminderRepository(db *DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// ListDue retrieves saved and applied opportunities of active listings whose
+// deadline falls within [from, to], nearest deadline first; suspended users are skipped
+func (r *ReminderRepository) ListDue(ctx context.Context, from, to time.Time) ([]*domain.DueReminder, error) {
+	query := `SELECT ` + opportunityColumns + `, uo.id, uo.status, u.id, u.username, u.email
+		FROM user_opportunities uo
+		JOIN opportunities o ON o.id = uo.opportunity_id
+		JOIN users u ON u.id = uo.user_id
+		WHERE uo.status IN ('saved', 'applied')
+			AND o.is_active = true
+			AND u.suspended_at IS NULL
+			AND o.deadline BETWEEN $1 AND $2
+		ORDER BY o.deadline, uo.id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*domain.DueReminder
+	for rows.Next() {
+		reminder := &domain.DueReminder{User: &domain.User{}}
+		opp, err := scanOpportunity(rows,
+			&reminder.UserOpportunityID,
+			&reminder.Status,
+			&reminder.User.ID,
+			&reminder.User.Username,
+			&reminder.User.Email,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reminder.Opportunity = opp
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+// Claim records that a reminder is being delivered on a channel. It returns
+// false when the same reminder was already claimed, which keeps delivery
+// idempotent across restarts and concurrent scanners.
+func (r *ReminderRepository) Claim(ctx context.Context, userOpportunityID int64, deadline time.Time, offsetDays int, channel string) (bool, error) {
+	query := `
+		INSERT INTO reminder_deliveries (user_opportunity_id, deadline, offset_days, channel)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_opportunity_id, deadline, offset_days, channel) DO NOTHING
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, userOpportunityID, deadline, offsetDays, channel)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// Release removes a claim after a failed delivery so the next scan retries it
+func (r *ReminderRepository) Release(ctx context.Context, userOpportunityID int64, deadline time.Time, offsetDays int, channel string) error {
+	query := `
+		DELETE FROM reminder_deliveries
+		WHERE user_opportunity_id = $1 AND deadline = $2 AND offset_days = $3 AND channel = $4
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, userOpportunityID, deadline, offsetDays, channel)
+	return err
+}
@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// ScheduleRepository handles schedule data access operations
+type ScheduleRepository struct {
+	db *DB
+}
+
+// NewScheduleRepository creates a new schedule repository
+func NewScheduleRepository(db *DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// scheduleColumns is the column list shared by all schedule queries
+const scheduleColumns = `
+	id, user_id, title, COALESCE(type, ''), start_time, end_time,
+	COALESCE(location, ''), COALESCE(description, ''),
+	is_recurring, COALESCE(recurrence_rule, ''), semester_id, exception_dates,
+	COALESCE(import_key, ''), created_at, COALESCE(updated_at, created_at)
+`
+
+// dateLayout DATE[]列的文本格式
+const dateLayout = "2006-01-02"
+
+// scanSchedule scans a row selected with scheduleColumns
+func scanSchedule(row rowScanner) (*domain.Schedule, error) {
+	schedule := &domain.Schedule{}
+	var semesterID sql.NullInt64
+	var exceptionDates []string
+
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.UserID,
+		&schedule.Title,
+		&schedule.Type,
+		&schedule.StartTime,
+		&schedule.EndTime,
+		&schedule.Location,
+		&schedule.Description,
+		&schedule.IsRecurring,
+		&schedule.RecurrenceRule,
+		&semesterID,
+		pq.Array(&exceptionDates),
+		&schedule.ImportKey,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if semesterID.Valid {
+		schedule.SemesterID = &semesterID.Int64
+	}
+	for _, d := range exceptionDates {
+		if t, err := time.Parse(dateLayout, d); err == nil {
+			schedule.ExceptionDates = append(schedule.ExceptionDates, t)
+		}
+	}
+
+	return schedule, nil
+}
+
+// formatDates converts dates to the text form accepted by a DATE[] column
+func formatDates(dates []time.Time) []string {
+	result := make([]string, len(dates))
+	for i, d := range dates {
+		result[i] = d.Format(dateLayout)
+	}
+	return result
+}
+
+// Create creates a new schedule
+func (r *ScheduleRepository) Create(ctx context.Context, schedule *domain.Schedule) error {
+	query := `
+		INSERT INTO schedules (
+			user_id, title, type, start_time, end_time, location, description,
+			is_recurring, recurrence_rule, semester_id, exception_dates
+		)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11::date[])
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.conn(ctx).QueryRowContext(ctx, query,
+		schedule.UserID,
+		schedule.Title,
+		schedule.Type,
+		schedule.StartTime,
+		schedule.EndTime,
+		schedule.Location,
+		schedule.Description,
+		schedule.IsRecurring,
+		schedule.RecurrenceRule,
+		schedule.SemesterID,
+		pq.Array(formatDates(schedule.ExceptionDates)),
+	).Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt)
+}
+
+// Upsert creates an imported schedule, or updates the user's schedule with the same import key
+// The returned flag reports whether a new row was inserted
+func (r *ScheduleRepository) Upsert(ctx context.Context, schedule *domain.Schedule) (bool, error) {
+	query := `
+		INSERT INTO schedules (
+			user_id, title, type, start_time, end_time, location, description,
+			is_recurring, recurrence_rule, semester_id, exception_dates, import_key
+		)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11::date[], $12)
+		ON CONFLICT (user_id, import_key) WHERE import_key IS NOT NULL DO UPDATE
+		SET title = EXCLUDED.title, type = EXCLUDED.type,
+			start_time = EXCLUDED.start_time, end_time = EXCLUDED.end_time,
+			location = EXCLUDED.location, description = EXCLUDED.description,
+			is_recurring = EXCLUDED.is_recurring, recurrence_rule = EXCLUDED.recurrence_rule,
+			semester_id = EXCLUDED.semester_id, exception_dates = EXCLUDED.exception_dates,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, COALESCE(updated_at, created_at), (xmax = 0)
+	`
+
+	var inserted bool
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
+		schedule.UserID,
+		schedule.Title,
+		schedule.Type,
+		schedule.StartTime,
+		schedule.EndTime,
+		schedule.Location,
+		schedule.Description,
+		schedule.IsRecurring,
+		schedule.RecurrenceRule,
+		schedule.SemesterID,
+		pq.Array(formatDates(schedule.ExceptionDates)),
+		schedule.ImportKey,
+	).Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt, &inserted)
+	if err != nil {
+		return false, err
+	}
+
+	return inserted, nil
+}
+
+// GetByID retrieves a schedule owned by the user
+func (r *ScheduleRepository) GetByID(ctx context.Context, userID, id int64) (*domain.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE id = $1 AND user_id = $2`
+
+	schedule, err := scanSchedule(r.db.conn(ctx).QueryRowContext(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("schedule not found")
+		}
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// ListByUserID retrieves all schedules of a user ordered by start time
+func (r *ScheduleRepository) ListByUserID(ctx context.Context, userID int64) ([]*domain.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + `
+		FROM schedules
+		WHERE user_id = $1
+		ORDER BY start_time
+	`
+
+	return r.querySchedules(ctx, query, userID)
+}
+
+// ListInWindow retrieves the schedules that may occur in [from, to):
+// one-off entries overlapping the window and recurring entries starting before it ends
+func (r *ScheduleRepository) ListInWindow(ctx context.Context, userID int64, from, to time.Time) ([]*domain.Schedule, error) {
+	query := `SELECT ` + scheduleColumns + `
+		FROM schedules
+		WHERE user_id = $1
+			AND start_time < $3
+			AND (is_recurring = true OR end_time > $2)
+		ORDER BY start_time
+	`
+
+	return r.querySchedules(ctx, query, userID, from, to)
+}
+
+// Update updates a schedule owned by the user
+func (r *ScheduleRepository) Update(ctx context.Context, schedule *domain.Schedule) error {
+	query := `
+		UPDATE schedules
+		SET title = $1, type = NULLIF($2, ''), start_time = $3, end_time = $4,
+			location = $5, description = $6, is_recurring = $7, recurrence_rule = NULLIF($8, ''),
+			semester_id = $9, exception_dates = $10::date[], updated_at = CURRENT_TIMESTAMP
+		WHERE id = $11 AND user_id = $12
+		RETURNING updated_at
+	`
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
+		schedule.Title,
+		schedule.Type,
+		schedule.StartTime,
+		schedule.EndTime,
+		schedule.Location,
+		schedule.Description,
+		schedule.IsRecurring,
+		schedule.RecurrenceRule,
+		schedule.SemesterID,
+		pq.Array(formatDates(schedule.ExceptionDates)),
+		schedule.ID,
+		schedule.UserID,
+	).Scan(&schedule.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("schedule not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a schedule owned by the user
+func (r *ScheduleRepository) Delete(ctx context.Context, userID, id int64) error {
+	result, err := r.db.conn(ctx).ExecContext(ctx, `DELETE FROM schedules WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("schedule not found")
+	}
+
+	return nil
+}
+
+// querySchedules runs a query selecting scheduleColumns and scans all rows
+func (r *ScheduleRepository) querySchedules(ctx context.Context, query string, args ...interface{}) ([]*domain.Schedule, error) {
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*domain.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// SemesterRepository handles semester data access operations
+type SemesterRepository struct {
+	db *DB
+}
+
+// NewSemesterRepository creates a new semester repository
+func NewSemesterRepository(db *DB) *SemesterRepository {
+	return &SemesterRepository{db: db}
+}
+
+const semesterColumns = `id, user_id, name, start_date, end_date, holidays, exam_periods, created_at, updated_at`
+
+// scanSemester scans a row selected with semesterColumns
+func scanSemester(row rowScanner) (*domain.Semester, error) {
+	semester := &domain.Semester{}
+	err := row.Scan(
+		&semester.ID,
+		&semester.UserID,
+		&semester.Name,
+		&semester.StartDate,
+		&semester.EndDate,
+		&semester.Holidays,
+		&semester.ExamPeriods,
+		&semester.CreatedAt,
+		&semester.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return semester, nil
+}
+
+// Create creates a new semester
+func (r *SemesterRepository) Create(ctx context.Context, semester *domain.Semester) error {
+	query := `
+		INSERT INTO semesters (user_id, name, start_date, end_date, holidays, exam_periods)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.conn(ctx).QueryRowContext(ctx, query,
+		semester.UserID,
+		semester.Name,
+		semester.StartDate,
+		semester.EndDate,
+		semester.Holidays,
+		semester.ExamPeriods,
+	).Scan(&semester.ID, &semester.CreatedAt, &semester.UpdatedAt)
+}
+
+// GetByID retrieves a semester owned by the user
+func (r *SemesterRepository) GetByID(ctx context.Context, userID, id int64) (*domain.Semester, error) {
+	query := `SELECT ` + semesterColumns + ` FROM semesters WHERE id = $1 AND user_id = $2`
+
+	semester, err := scanSemester(r.db.conn(ctx).QueryRowContext(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("semester not found")
+		}
+		return nil, err
+	}
+
+	return semester, nil
+}
+
+// ListByUserID retrieves all semesters of a user, latest first
+func (r *SemesterRepository) ListByUserID(ctx context.Context, userID int64) ([]*domain.Semester, error) {
+	query := `SELECT ` + semesterColumns + ` FROM semesters WHERE user_id = $1 ORDER BY start_date DESC`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var semesters []*domain.Semester
+	for rows.Next() {
+		semester, err := scanSemester(rows)
+		if err != nil {
+			return nil, err
+		}
+		semesters = append(semesters, semester)
+	}
+
+	return semesters, rows.Err()
+}
+
+// Update updates a semester owned by the user
+func (r *SemesterRepository) Update(ctx context.Context, semester *domain.Semester) error {
+	query := `
+		UPDATE semesters
+		SET name = $1, start_date = $2, end_date = $3, holidays = $4, exam_periods = $5,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 AND user_id = $7
+		RETURNING updated_at
+	`
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
+		semester.Name,
+		semester.StartDate,
+		semester.EndDate,
+		semester.Holidays,
+		semester.ExamPeriods,
+		semester.ID,
+		semester.UserID,
+	).Scan(&semester.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("semester not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a semester owned by the user; its schedules become unbounded
+func (r *SemesterRepository) Delete(ctx context.Context, userID, id int64) error {
+	result, err := r.db.conn(ctx).ExecContext(ctx, `DELETE FROM semesters WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("semester not found")
+	}
+
+	return nil
+}
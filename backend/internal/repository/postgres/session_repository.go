@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// SessionRepository handles login sessions and their refresh tokens
+type SessionRepository struct {
+	db *DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// sessionColumns 会话查询列
+const sessionColumns = `id, user_id, user_agent, ip, created_at, last_used_at, expires_at, revoked_at`
+
+// scanSession 扫描一行会话记录
+func scanSession(row rowScanner) (*domain.Session, error) {
+	session := &domain.Session{}
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Create creates a session together with its first refresh token
+func (r *SessionRepository) Create(ctx context.Context, session *domain.Session, tokenHash string) error {
+	return r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO sessions (user_id, user_agent, ip, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at, last_used_at
+		`, session.UserID, session.UserAgent, session.IP, session.ExpiresAt.UTC(),
+		).Scan(&session.ID, &session.CreatedAt, &session.LastUsedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO refresh_tokens (session_id, token_hash) VALUES ($1, $2)`,
+			session.ID, tokenHash,
+		)
+		return err
+	})
+}
+
+// GetByID retrieves a session
+func (r *SessionRepository) GetByID(ctx context.Context, id int64) (*domain.Session, error) {
+	session, err := scanSession(r.db.conn(ctx).QueryRowContext(ctx,
+		`SELECT `+sessionColumns+` FROM sessions WHERE id = $1`, id,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetToken retrieves a refresh token by its hash
+func (r *SessionRepository) GetToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	token := &domain.RefreshToken{}
+	err := r.db.conn(ctx).QueryRowContext(ctx,
+		`SELECT id, session_id, token_hash, used_at FROM refresh_tokens WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&token.ID, &token.SessionID, &token.TokenHash, &token.UsedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// Rotate marks a refresh token as used and issues its successor in the same session,
+// extending the session to expiresAt. It returns false when the token had already
+// been used, e.g. by a concurrent refresh.
+func (r *SessionRepository) Rotate(ctx context.Context, token *domain.RefreshToken, newHash string, expiresAt time.Time) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL`,
+			token.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO refresh_tokens (session_id, token_hash) VALUES ($1, $2)`,
+			token.SessionID, newHash,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE sessions SET last_used_at = CURRENT_TIMESTAMP, expires_at = $2 WHERE id = $1`,
+			token.SessionID, expiresAt.UTC(),
+		); err != nil {
+			return err
+		}
+
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// ListActive lists the user's sessions that are neither revoked nor expired, most recently used first
+func (r *SessionRepository) ListActive(ctx context.Context, userID int64) ([]*domain.Session, error) {
+	rows, err := r.db.conn(ctx).QueryContext(ctx, `
+		SELECT `+sessionColumns+`
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_used_at DESC
+	`, userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*domain.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// Revoke ends one of the user's sessions
+func (r *SessionRepository) Revoke(ctx context.Context, userID, id int64) error {
+	result, err := r.db.conn(ctx).ExecContext(ctx,
+		`UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		id, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("session not found")
+	}
+
+	return nil
+}
+
+// RevokeAll ends all of the user's sessions except exceptID (0 ends all) and returns the IDs it ended
+func (r *SessionRepository) RevokeAll(ctx context.Context, userID, exceptID int64) ([]int64, error) {
+	rows, err := r.db.conn(ctx).QueryContext(ctx,
+		`UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL RETURNING id`,
+		userID, exceptID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
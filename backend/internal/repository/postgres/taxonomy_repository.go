@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// TaxonomyRepository handles taxonomy term data access operations
+type TaxonomyRepository struct {
+	db *DB
+}
+
+// NewTaxonomyRepository creates a new taxonomy repository
+func NewTaxonomyRepository(db *DB) *TaxonomyRepository {
+	return &TaxonomyRepository{db: db}
+}
+
+const taxonomyColumns = `
+	id, kind, code, name, COALESCE(level, ''), parent_id, synonyms, is_active, created_at, updated_at
+`
+
+// scanTaxonomyTerm scans a row selected with taxonomyColumns
+func scanTaxonomyTerm(row rowScanner) (*domain.TaxonomyTerm, error) {
+	term := &domain.TaxonomyTerm{}
+	var parentID sql.NullInt64
+
+	err := row.Scan(
+		&term.ID,
+		&term.Kind,
+		&term.Code,
+		&term.Name,
+		&term.Level,
+		&parentID,
+		pq.Array(&term.Synonyms),
+		&term.IsActive,
+		&term.CreatedAt,
+		&term.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		term.ParentID = &parentID.Int64
+	}
+	return term, nil
+}
+
+// Create creates a new taxonomy term
+func (r *TaxonomyRepository) Create(ctx context.Context, term *domain.TaxonomyTerm) error {
+	query := `
+		INSERT INTO taxonomy_terms (kind, code, name, level, parent_id, synonyms, is_active)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
+		term.Kind,
+		term.Code,
+		term.Name,
+		term.Level,
+		term.ParentID,
+		pq.Array(term.Synonyms),
+		term.IsActive,
+	).Scan(&term.ID, &term.CreatedAt, &term.UpdatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.New("taxonomy term already exists")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByID retrieves a taxonomy term by ID
+func (r *TaxonomyRepository) GetByID(ctx context.Context, id int64) (*domain.TaxonomyTerm, error) {
+	query := `SELECT ` + taxonomyColumns + ` FROM taxonomy_terms WHERE id = $1`
+
+	term, err := scanTaxonomyTerm(r.db.conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("taxonomy term not found")
+		}
+		return nil, err
+	}
+
+	return term, nil
+}
+
+// ListActive retrieves all active taxonomy terms
+func (r *TaxonomyRepository) ListActive(ctx context.Context) ([]*domain.TaxonomyTerm, error) {
+	query := `SELECT ` + taxonomyColumns + ` FROM taxonomy_terms WHERE is_active = true ORDER BY kind, code`
+	return r.queryTerms(ctx, query)
+}
+
+// List retrieves taxonomy terms with filtering and pagination
+func (r *TaxonomyRepository) List(ctx context.Context, filter *domain.TaxonomyFilter) ([]*domain.TaxonomyTerm, int64, error) {
+	var conditions []string
+	var args []interface{}
+	argPos := 1
+
+	if filter.Kind != "" {
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", argPos))
+		args = append(args, filter.Kind)
+		argPos++
+	}
+
+	if filter.Level != "" {
+		conditions = append(conditions, fmt.Sprintf("level = $%d", argPos))
+		args = append(args, filter.Level)
+		argPos++
+	}
+
+	if filter.ParentID != nil {
+		conditions = append(conditions, fmt.Sprintf("parent_id = $%d", argPos))
+		args = append(args, *filter.ParentID)
+		argPos++
+	}
+
+	if filter.Query != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"(name ILIKE $%d OR code ILIKE $%d OR EXISTS (SELECT 1 FROM unnest(synonyms) s WHERE s ILIKE $%d))",
+			argPos, argPos, argPos))
+		args = append(args, "%"+filter.Query+"%")
+		argPos++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM taxonomy_terms %s", whereClause)
+	if err := r.db.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	query := fmt.Sprintf(`SELECT `+taxonomyColumns+`
+		FROM taxonomy_terms
+		%s
+		ORDER BY kind, code
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argPos, argPos+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	terms, err := r.queryTerms(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return terms, total, nil
+}
+
+// Update updates a taxonomy term
+func (r *TaxonomyRepository) Update(ctx context.Context, term *domain.TaxonomyTerm) error {
+	query := `
+		UPDATE taxonomy_terms
+		SET kind = $1, code = $2, name = $3, level = NULLIF($4, ''), parent_id = $5,
+			synonyms = $6, is_active = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8
+		RETURNING updated_at
+	`
+
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
+		term.Kind,
+		term.Code,
+		term.Name,
+		term.Level,
+		term.ParentID,
+		pq.Array(term.Synonyms),
+		term.IsActive,
+		term.ID,
+	).Scan(&term.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("taxonomy term not found")
+		}
+		if isUniqueViolation(err) {
+			return errors.New("taxonomy term already exists")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete soft deletes a taxonomy term (sets is_active = false)
+func (r *TaxonomyRepository) Delete(ctx context.Context, id int64) error {
+	query := `
+		UPDATE taxonomy_terms
+		SET is_active = false, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("taxonomy term not found")
+	}
+
+	return nil
+}
+
+// queryTerms runs a query selecting taxonomyColumns and scans all rows
+func (r *TaxonomyRepository) queryTerms(ctx context.Context, query string, args ...interface{}) ([]*domain.TaxonomyTerm, error) {
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var terms []*domain.TaxonomyTerm
+	for rows.Next() {
+		term, err := scanTaxonomyTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	return terms, rows.Err()
+}
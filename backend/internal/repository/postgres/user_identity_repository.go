@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// UserIdentityRepository handles the links between users and campus SSO accounts
+type UserIdentityRepository struct {
+	db *DB
+}
+
+// NewUserIdentityRepository creates a new user identity repository
+func NewUserIdentityRepository(db *DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+// Get retrieves the identity of a provider account
+func (r *UserIdentityRepository) Get(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	identity := &domain.UserIdentity{}
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("identity not found")
+		}
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// Link binds a provider account to a user, or records another login of an existing link
+func (r *UserIdentityRepository) Link(ctx context.Context, identity *domain.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (provider, subject) DO UPDATE
+		SET email = EXCLUDED.email, last_login_at = EXCLUDED.last_login_at
+		RETURNING id, user_id, created_at, last_login_at
+	`
+
+	return r.db.conn(ctx).QueryRowContext(ctx, query,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		time.Now().UTC(),
+	).Scan(&identity.ID, &identity.UserID, &identity.CreatedAt, &identity.LastLoginAt)
+}
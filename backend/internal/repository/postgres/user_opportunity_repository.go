@@ -0,0 +1,568 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// UserOpportunityRepository handles user-opportunity relation data access operations
+type UserOpportunityRepository struct {
+	db *DB
+}
+
+// NewUserOpportunityRepository creates a new user-opportunity repository
+func NewUserOpportunityRepository(db *DB) *UserOpportunityRepository {
+	return &UserOpportunityRepository{db: db}
+}
+
+// userOpportunityColumns is the column list shared by all queries aliasing user_opportunities as "uo"
+const userOpportunityColumns = `
+	uo.id, uo.user_id, uo.opportunity_id, COALESCE(uo.status, ''),
+	COALESCE(uo.accessibility_score, 0), COALESCE(uo.relevance_score, 0), uo.score_details,
+	uo.pushed_at, COALESCE(uo.push_channel, ''),
+	uo.viewed_at, uo.saved_at, uo.applied_at, uo.completed_at,
+	COALESCE(uo.user_feedback, ''), COALESCE(uo.feedback_reason, '')
+`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanUserOpportunity scans a row selected with userOpportunityColumns
+func scanUserOpportunity(row rowScanner) (*domain.UserOpportunity, error) {
+	uo := &domain.UserOpportunity{}
+	if err := row.Scan(userOpportunityDest(uo)...); err != nil {
+		return nil, err
+	}
+	return uo, nil
+}
+
+// userOpportunityDest returns the scan destinations matching userOpportunityColumns
+func userOpportunityDest(uo *domain.UserOpportunity) []interface{} {
+	return []interface{}{
+		&uo.ID,
+		&uo.UserID,
+		&uo.OpportunityID,
+		&uo.Status,
+		&uo.AccessibilityScore,
+		&uo.RelevanceScore,
+		&uo.ScoreDetails,
+		&uo.PushedAt,
+		&uo.PushChannel,
+		&uo.ViewedAt,
+		&uo.SavedAt,
+		&uo.AppliedAt,
+		&uo.CompletedAt,
+		&uo.UserFeedback,
+		&uo.FeedbackReason,
+	}
+}
+
+// Get retrieves the relation between a user and an opportunity
+func (r *UserOpportunityRepository) Get(ctx context.Context, userID, opportunityID int64) (*domain.UserOpportunity, error) {
+	query := `SELECT ` + userOpportunityColumns + `
+		FROM user_opportunities uo
+		WHERE uo.user_id = $1 AND uo.opportunity_id = $2
+	`
+
+	uo, err := scanUserOpportunity(r.db.conn(ctx).QueryRowContext(ctx, query, userID, opportunityID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("user opportunity not found")
+		}
+		return nil, err
+	}
+
+	return uo, nil
+}
+
+// ListAllByUser retrieves every relation of the user, including opportunities only scored or pushed
+func (r *UserOpportunityRepository) ListAllByUser(ctx context.Context, userID int64) ([]*domain.UserOpportunity, error) {
+	query := `SELECT ` + userOpportunityColumns + `
+		FROM user_opportunities uo
+		WHERE uo.user_id = $1
+		ORDER BY uo.id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	relations := make([]*domain.UserOpportunity, 0)
+	for rows.Next() {
+		uo, err := scanUserOpportunity(rows)
+		if err != nil {
+			return nil, err
+		}
+		relations = append(relations, uo)
+	}
+
+	return relations, rows.Err()
+}
+
+// CountByStatus counts the user's saved opportunities per status
+func (r *UserOpportunityRepository) CountByStatus(ctx context.Context, userID int64) (map[string]int64, error) {
+	query := `
+		SELECT status, COUNT(*)
+		FROM user_opportunities
+		WHERE user_id = $1 AND status IS NOT NULL
+		GROUP BY status
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// UpsertScores stores the computed scores of a user-opportunity pair
+// A row created only for scoring has no status until the user saves the opportunity
+func (r *UserOpportunityRepository) UpsertScores(ctx context.Context, uo *domain.UserOpportunity) error {
+	query := `
+		INSERT INTO user_opportunities (user_id, opportunity_id, status, accessibility_score, relevance_score, score_details)
+		VALUES ($1, $2, NULL, $3, $4, $5)
+		ON CONFLICT (user_id, opportunity_id)
+		DO UPDATE SET
+			accessibility_score = EXCLUDED.accessibility_score,
+			relevance_score = EXCLUDED.relevance_score,
+			score_details = EXCLUDED.score_details,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, COALESCE(status, '')
+	`
+
+	return r.db.conn(ctx).QueryRowContext(ctx, query,
+		uo.UserID,
+		uo.OpportunityID,
+		uo.AccessibilityScore,
+		uo.RelevanceScore,
+		uo.ScoreDetails,
+	).Scan(&uo.ID, &uo.Status)
+}
+
+// Save marks an opportunity as saved by the user and increments its save_count
+// in the same transaction. Saving is idempotent: the counter only moves when the
+// opportunity was not saved yet (no status) or had been abandoned.
+// It returns the relation and whether it was newly saved.
+func (r *UserOpportunityRepository) Save(ctx context.Context, userID, opportunityID int64) (*domain.UserOpportunity, bool, error) {
+	var uo *domain.UserOpportunity
+	var created bool
+
+	err := r.db.Transaction(ctx, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM opportunities WHERE id = $1 AND is_active = true)`,
+			opportunityID,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("opportunity not found")
+		}
+
+		// 仅在尚未保存或已放弃时（重新）进入saved状态
+		var id int64
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO user_opportunities (user_id, opportunity_id, status, saved_at)
+			VALUES ($1, $2, 'saved', CURRENT_TIMESTAMP)
+			ON CONFLICT (user_id, opportunity_id)
+			DO UPDATE SET
+				status = 'saved',
+				saved_at = CURRENT_TIMESTAMP,
+				applied_at = NULL,
+				completed_at = NULL,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE user_opportunities.status IS NULL OR user_opportunities.status = 'abandoned'
+			RETURNING id
+		`, userID, opportunityID).Scan(&id)
+
+		switch {
+		case err == nil:
+			created = true
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE opportunities SET save_count = save_count + 1 WHERE id = $1`,
+				opportunityID,
+			); err != nil {
+				return err
+			}
+		case errors.Is(err, sql.ErrNoRows):
+			// 已处于saved/applied/completed，保持不变
+		default:
+			return err
+		}
+
+		query := `SELECT ` + userOpportunityColumns + `
+			FROM user_opportunities uo
+			WHERE uo.user_id = $1 AND uo.opportunity_id = $2
+		`
+		uo, err = scanUserOpportunity(tx.QueryRowContext(ctx, query, userID, opportunityID))
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return uo, created, nil
+}
+
+// UpdateStatus moves a relation from one status to another and stamps the
+// timestamp column matching the new status. The update only applies while the
+// relation is still in the expected status, so concurrent changes are not lost.
+func (r *UserOpportunityRepository) UpdateStatus(ctx context.Context, userID, opportunityID int64, from, to string) (*domain.UserOpportunity, error) {
+	var stamp string
+	switch to {
+	case domain.UserOpportunityStatusApplied:
+		stamp = ", applied_at = CURRENT_TIMESTAMP"
+	case domain.UserOpportunityStatusCompleted:
+		stamp = ", completed_at = CURRENT_TIMESTAMP"
+	}
+
+	query := `
+		WITH updated AS (
+			UPDATE user_opportunities
+			SET status = $3` + stamp + `, updated_at = CURRENT_TIMESTAMP
+			WHERE user_id = $1 AND opportunity_id = $2 AND status = $4
+			RETURNING *
+		)
+		SELECT ` + userOpportunityColumns + ` FROM updated uo
+	`
+
+	uo, err := scanUserOpportunity(r.db.conn(ctx).QueryRowContext(ctx, query, userID, opportunityID, to, from))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("user opportunity not found")
+		}
+		return nil, err
+	}
+
+	return uo, nil
+}
+
+// ListByUser retrieves the opportunities a user has saved, applied for,
+// completed or abandoned, most recently updated first
+func (r *UserOpportunityRepository) ListByUser(ctx context.Context, userID int64, filter *domain.UserOpportunityFilter) ([]*domain.UserOpportunityDetail, int64, error) {
+	where := `WHERE uo.user_id = $1 AND uo.status IS NOT NULL`
+	args := []interface{}{userID}
+	if filter.Status != "" {
+		where += ` AND uo.status = $2`
+		args = append(args, filter.Status)
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM user_opportunities uo ` + where
+	if err := r.db.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	query := fmt.Sprintf(`SELECT `+opportunityColumns+`, `+userOpportunityColumns+`
+		FROM user_opportunities uo
+		JOIN opportunities o ON o.id = uo.opportunity_id
+		%s
+		ORDER BY uo.updated_at DESC, uo.id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []*domain.UserOpportunityDetail
+	for rows.Next() {
+		item := &domain.UserOpportunityDetail{}
+		opp, err := scanOpportunity(rows, userOpportunityDest(&item.UserOpportunity)...)
+		if err != nil {
+			return nil, 0, err
+		}
+		item.Opportunity = opp
+		items = append(items, item)
+	}
+
+	return items, total, rows.Err()
+}
+
+// ListByStatuses retrieves all of a user's opportunities in any of the given statuses,
+// ordered by deadline (undated last)
+func (r *UserOpportunityRepository) ListByStatuses(ctx context.Context, userID int64, statuses []string) ([]*domain.UserOpportunityDetail, error) {
+	query := `SELECT ` + opportunityColumns + `, ` + userOpportunityColumns + `
+		FROM user_opportunities uo
+		JOIN opportunities o ON o.id = uo.opportunity_id
+		WHERE uo.user_id = $1 AND uo.status = ANY($2)
+		ORDER BY o.deadline ASC NULLS LAST, uo.id ASC
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, userID, pq.Array(statuses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*domain.UserOpportunityDetail
+	for rows.Next() {
+		item := &domain.UserOpportunityDetail{}
+		opp, err := scanOpportunity(rows, userOpportunityDest(&item.UserOpportunity)...)
+		if err != nil {
+			return nil, err
+		}
+		item.Opportunity = opp
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// SetFeedback records the user's feedback on an opportunity without changing its status
+func (r *UserOpportunityRepository) SetFeedback(ctx context.Context, userID, opportunityID int64, feedback, reason string) (*domain.UserOpportunity, error) {
+	query := `
+		INSERT INTO user_opportunities AS uo (user_id, opportunity_id, status, user_feedback, feedback_reason)
+		VALUES ($1, $2, NULL, $3, NULLIF($4, ''))
+		ON CONFLICT (user_id, opportunity_id)
+		DO UPDATE SET
+			user_feedback = EXCLUDED.user_feedback,
+			feedback_reason = EXCLUDED.feedback_reason,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + userOpportunityColumns
+
+	uo, err := scanUserOpportunity(r.db.conn(ctx).QueryRowContext(ctx, query, userID, opportunityID, feedback, reason))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, errors.New("opportunity not found")
+		}
+		return nil, err
+	}
+
+	return uo, nil
+}
+
+// MarkPushed records a delivered push: pushed_at is stamped and push_channel
+// holds the comma-separated channels of the latest push
+func (r *UserOpportunityRepository) MarkPushed(ctx context.Context, id int64, channels []string) error {
+	query := `
+		UPDATE user_opportunities
+		SET pushed_at = CURRENT_TIMESTAMP, push_channel = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	_, err := r.db.conn(ctx).ExecContext(ctx, query, id, strings.Join(channels, ","))
+	return err
+}
+
+// FollowerIDs returns the users who have saved or applied for an opportunity
+func (r *UserOpportunityRepository) FollowerIDs(ctx context.Context, opportunityID int64) ([]int64, error) {
+	query := `
+		SELECT user_id
+		FROM user_opportunities
+		WHERE opportunity_id = $1 AND status IN ('saved', 'applied')
+		ORDER BY user_id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// TopIrrelevantOpportunities returns the opportunities most often marked irrelevant
+func (r *UserOpportunityRepository) TopIrrelevantOpportunities(ctx context.Context, limit int) ([]*domain.IrrelevantOpportunityStat, error) {
+	query := `
+		SELECT o.id, o.title, o.source_url, COALESCE(o.source_type, ''),
+			COUNT(*) FILTER (WHERE uo.user_feedback = 'irrelevant') AS irrelevant,
+			COUNT(*) AS total
+		FROM user_opportunities uo
+		JOIN opportunities o ON o.id = uo.opportunity_id
+		WHERE uo.user_feedback IS NOT NULL
+		GROUP BY o.id
+		HAVING COUNT(*) FILTER (WHERE uo.user_feedback = 'irrelevant') > 0
+		ORDER BY irrelevant DESC, o.id
+		LIMIT $1
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*domain.IrrelevantOpportunityStat
+	for rows.Next() {
+		stat := &domain.IrrelevantOpportunityStat{}
+		if err := rows.Scan(
+			&stat.OpportunityID,
+			&stat.Title,
+			&stat.SourceURL,
+			&stat.SourceType,
+			&stat.IrrelevantCount,
+			&stat.FeedbackCount,
+		); err != nil {
+			return nil, err
+		}
+		stat.IrrelevantRate = float64(stat.IrrelevantCount) / float64(stat.FeedbackCount)
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// TopIrrelevantSources returns the source sites whose opportunities are most often
+// marked irrelevant, matched to crawl tasks by host name
+func (r *UserOpportunityRepository) TopIrrelevantSources(ctx context.Context, limit int) ([]*domain.IrrelevantSourceStat, error) {
+	query := `
+		WITH marked AS (
+			SELECT o.id, COALESCE(o.source_type, '') AS source_type,
+				LOWER(substring(o.source_url from '^[a-zA-Z]+://([^/:?#]+)')) AS host
+			FROM user_opportunities uo
+			JOIN opportunities o ON o.id = uo.opportunity_id
+			WHERE uo.user_feedback = 'irrelevant'
+		)
+		SELECT COALESCE(m.host, ''),
+			COALESCE((
+				SELECT ct.site_name FROM crawl_tasks ct
+				WHERE LOWER(substring(ct.target_url from '^[a-zA-Z]+://([^/:?#]+)')) = m.host
+				ORDER BY ct.id
+				LIMIT 1
+			), ''),
+			m.source_type,
+			COUNT(*) AS irrelevant,
+			COUNT(DISTINCT m.id)
+		FROM marked m
+		GROUP BY m.host, m.source_type
+		ORDER BY irrelevant DESC, 1
+		LIMIT $1
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*domain.IrrelevantSourceStat
+	for rows.Next() {
+		stat := &domain.IrrelevantSourceStat{}
+		if err := rows.Scan(
+			&stat.Host,
+			&stat.SiteName,
+			&stat.SourceType,
+			&stat.IrrelevantCount,
+			&stat.OpportunityCount,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// PeerParticipationRate returns the share of users in the given major who
+// saved, applied for or completed the opportunity
+func (r *UserOpportunityRepository) PeerParticipationRate(ctx context.Context, opportunityID int64, major string) (float64, error) {
+	if major == "" {
+		return 0, nil
+	}
+
+	query := `
+		SELECT
+			(SELECT COUNT(DISTINCT uo.user_id)
+				FROM user_opportunities uo
+				JOIN users u ON u.id = uo.user_id
+				WHERE uo.opportunity_id = $1 AND u.major = $2
+					AND uo.status IN ('saved', 'applied', 'completed')),
+			(SELECT COUNT(*) FROM users WHERE major = $2)
+	`
+
+	var participants, peers int64
+	if err := r.db.conn(ctx).QueryRowContext(ctx, query, opportunityID, major).Scan(&participants, &peers); err != nil {
+		return 0, err
+	}
+
+	if peers == 0 {
+		return 0, nil
+	}
+
+	return float64(participants) / float64(peers), nil
+}
+
+// PeerParticipationRates returns PeerParticipationRate for several opportunities at once
+// Opportunities without any peer participation are absent from the result
+func (r *UserOpportunityRepository) PeerParticipationRates(ctx context.Context, opportunityIDs []int64, major string) (map[int64]float64, error) {
+	rates := make(map[int64]float64)
+	if major == "" || len(opportunityIDs) == 0 {
+		return rates, nil
+	}
+
+	var peers int64
+	if err := r.db.conn(ctx).QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE major = $1`, major).Scan(&peers); err != nil {
+		return nil, err
+	}
+	if peers == 0 {
+		return rates, nil
+	}
+
+	query := `
+		SELECT uo.opportunity_id, COUNT(DISTINCT uo.user_id)
+		FROM user_opportunities uo
+		JOIN users u ON u.id = uo.user_id
+		WHERE uo.opportunity_id = ANY($1) AND u.major = $2
+			AND uo.status IN ('saved', 'applied', 'completed')
+		GROUP BY uo.opportunity_id
+	`
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, pq.Array(opportunityIDs), major)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, participants int64
+		if err := rows.Scan(&id, &participants); err != nil {
+			return nil, err
+		}
+		rates[id] = float64(participants) / float64(peers)
+	}
+
+	return rates, rows.Err()
+}
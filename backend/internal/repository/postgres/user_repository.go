@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/unifocus/backend/internal/domain"
 )
@@ -18,15 +21,45 @@ func NewUserRepository(db *DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// userColumns is the column list shared by user queries
+const userColumns = `
+	id, username, email, password_hash, school, major, grade, avatar_url, role,
+	email_verified_at, suspended_at, created_at, updated_at
+`
+
+// scanUser scans a row selected with userColumns
+func scanUser(row rowScanner) (*domain.User, error) {
+	user := &domain.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		&user.School,
+		&user.Major,
+		&user.Grade,
+		&user.AvatarURL,
+		&user.Role,
+		&user.EmailVerifiedAt,
+		&user.SuspendedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 // Create creates a new user
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
 		INSERT INTO users (username, email, password_hash, school, major, grade, avatar_url)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at, updated_at
+		RETURNING id, role, created_at, updated_at
 	`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
 		user.Username,
 		user.Email,
 		user.Password,
@@ -34,7 +67,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		user.Major,
 		user.Grade,
 		user.AvatarURL,
-	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	).Scan(&user.ID, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return err
@@ -45,26 +78,9 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
-	query := `
-		SELECT id, username, email, password_hash, school, major, grade, avatar_url, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
-
-	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Password,
-		&user.School,
-		&user.Major,
-		&user.Grade,
-		&user.AvatarURL,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
+	user, err := scanUser(r.db.conn(ctx).QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("user not found")
@@ -77,26 +93,9 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, e
 
 // GetByEmail retrieves a user by email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `
-		SELECT id, username, email, password_hash, school, major, grade, avatar_url, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-
-	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Password,
-		&user.School,
-		&user.Major,
-		&user.Grade,
-		&user.AvatarURL,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 
+	user, err := scanUser(r.db.conn(ctx).QueryRowContext(ctx, query, email))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("user not found")
@@ -109,26 +108,9 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 
 // GetByUsername retrieves a user by username
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
-	query := `
-		SELECT id, username, email, password_hash, school, major, grade, avatar_url, created_at, updated_at
-		FROM users
-		WHERE username = $1
-	`
-
-	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Password,
-		&user.School,
-		&user.Major,
-		&user.Grade,
-		&user.AvatarURL,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = $1`
 
+	user, err := scanUser(r.db.conn(ctx).QueryRowContext(ctx, query, username))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("user not found")
@@ -139,16 +121,17 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 	return user, nil
 }
 
-// Update updates user information
+// Update updates user information; changing the grade restarts its rollover clock
 func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
-		SET username = $1, email = $2, school = $3, major = $4, grade = $5, avatar_url = $6, updated_at = CURRENT_TIMESTAMP
+		SET username = $1, email = $2, school = $3, major = $4, grade = $5, avatar_url = $6, updated_at = CURRENT_TIMESTAMP,
+			grade_updated_at = CASE WHEN grade IS DISTINCT FROM $5 THEN $8 ELSE grade_updated_at END
 		WHERE id = $7
 		RETURNING updated_at
 	`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := r.db.conn(ctx).QueryRowContext(ctx, query,
 		user.Username,
 		user.Email,
 		user.School,
@@ -156,12 +139,16 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 		user.Grade,
 		user.AvatarURL,
 		user.ID,
+		time.Now().UTC(),
 	).Scan(&user.UpdatedAt)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return errors.New("user not found")
 		}
+		if isUniqueViolation(err) {
+			return errors.New("username already exists")
+		}
 		return err
 	}
 
@@ -176,7 +163,57 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID int64, passw
 		WHERE id = $2
 	`
 
-	result, err := r.db.ExecContext(ctx, query, passwordHash, userID)
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, passwordHash, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// UpdateRole changes a user's role
+func (r *UserRepository) UpdateRole(ctx context.Context, userID int64, role string) error {
+	query := `
+		UPDATE users
+		SET role = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, role, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// MarkEmailVerified records that the user confirmed their email address
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID int64) error {
+	query := `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, userID)
 	if err != nil {
 		return err
 	}
@@ -198,7 +235,7 @@ func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&exists)
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, email).Scan(&exists)
 	return exists, err
 }
 
@@ -207,6 +244,185 @@ func (r *UserRepository) ExistsByUsername(ctx context.Context, username string)
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, username).Scan(&exists)
+	err := r.db.conn(ctx).QueryRowContext(ctx, query, username).Scan(&exists)
 	return exists, err
 }
+
+// Delete removes a user; every table owned by the user is cleared by ON DELETE CASCADE
+func (r *UserRepository) Delete(ctx context.Context, userID int64) error {
+	result, err := r.db.conn(ctx).ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// UpdateAvatar sets a user's avatar URL
+func (r *UserRepository) UpdateAvatar(ctx context.Context, userID int64, avatarURL string) error {
+	query := `
+		UPDATE users
+		SET avatar_url = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, avatarURL, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// RolloverGrades moves every user whose grade was set before the start of the
+// academic year up one grade (fourth-year students stay in grade 4), and marks
+// their grade as set at that start so a later run does not move them again
+func (r *UserRepository) RolloverGrades(ctx context.Context, yearStart time.Time) (int64, error) {
+	query := `
+		UPDATE users
+		SET grade = LEAST(grade + 1, 4), grade_updated_at = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE grade_updated_at < $1
+	`
+
+	result, err := r.db.conn(ctx).ExecContext(ctx, query, yearStart.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Search retrieves users for administrators with filtering and pagination, newest first.
+// Email matches any part of the address; school and major must match exactly.
+func (r *UserRepository) Search(ctx context.Context, filter *domain.AdminUserFilter) ([]*domain.User, int64, error) {
+	var conditions []string
+	var args []interface{}
+	argPos := 1
+
+	if filter.Email != "" {
+		conditions = append(conditions, fmt.Sprintf(`email ILIKE $%d ESCAPE '\'`, argPos))
+		args = append(args, "%"+escapeLike(filter.Email)+"%")
+		argPos++
+	}
+
+	if filter.School != "" {
+		conditions = append(conditions, fmt.Sprintf("school = $%d", argPos))
+		args = append(args, filter.School)
+		argPos++
+	}
+
+	if filter.Major != "" {
+		conditions = append(conditions, fmt.Sprintf("major = $%d", argPos))
+		args = append(args, filter.Major)
+		argPos++
+	}
+
+	if filter.Role != "" {
+		conditions = append(conditions, fmt.Sprintf("role = $%d", argPos))
+		args = append(args, filter.Role)
+		argPos++
+	}
+
+	switch filter.Status {
+	case domain.UserStatusActive:
+		conditions = append(conditions, "suspended_at IS NULL")
+	case domain.UserStatusSuspended:
+		conditions = append(conditions, "suspended_at IS NOT NULL")
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM users %s", whereClause)
+	if err := r.db.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	query := fmt.Sprintf(`SELECT `+userColumns+`
+		FROM users
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argPos, argPos+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := make([]*domain.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+// SetSuspended suspends a user at the given time, or reactivates them when at is nil
+func (r *UserRepository) SetSuspended(ctx context.Context, userID int64, at *time.Time) error {
+	var suspendedAt interface{}
+	if at != nil {
+		suspendedAt = at.UTC()
+	}
+
+	result, err := r.db.conn(ctx).ExecContext(ctx,
+		`UPDATE users SET suspended_at = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		suspendedAt, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// escapeLike 转义 LIKE 模式中的通配符，使搜索词按字面匹配
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/unifocus/backend/pkg/logger"
 )
 
+// Nil is returned when a key does not exist
+var Nil = redis.Nil
+
 // Client wraps redis.Client with namespace management and helper methods
 type Client struct {
 	*redis.Client
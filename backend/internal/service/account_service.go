@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/mail"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalidAccountToken indicates a verification or reset link is unknown, used or expired
+	ErrInvalidAccountToken = errors.New("invalid or expired token")
+	// ErrIncorrectPassword indicates the current password given for a password change is wrong
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+)
+
+// AccountService handles email verification and password recovery
+type AccountService struct {
+	cfg       config.AuthConfig
+	userRepo  *postgres.UserRepository
+	tokenRepo *postgres.AccountTokenRepository
+	auth      *AuthService
+	sender    mail.Sender
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(
+	cfg config.AuthConfig,
+	userRepo *postgres.UserRepository,
+	tokenRepo *postgres.AccountTokenRepository,
+	auth *AuthService,
+	sender mail.Sender,
+) *AccountService {
+	return &AccountService{
+		cfg:       cfg,
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		auth:      auth,
+		sender:    sender,
+	}
+}
+
+// SendVerification emails the user a link to confirm their address
+// Nothing is sent when the address is already verified
+func (s *AccountService) SendVerification(ctx context.Context, user *domain.User) error {
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	token, err := s.issue(ctx, user.ID, domain.AccountTokenVerifyEmail, s.cfg.GetVerifyDuration())
+	if err != nil {
+		return err
+	}
+
+	body := []string{
+		fmt.Sprintf("%s，你好：", user.Username),
+		"",
+		"请点击下面的链接验证你的 UniFocus 邮箱：",
+		s.link("/verify-email", token),
+		"",
+		fmt.Sprintf("链接将在%d小时后失效。如果这不是你本人的操作，请忽略本邮件。", s.cfg.VerifyExpireHours),
+	}
+	return s.send(ctx, user.Email, "验证你的 UniFocus 邮箱", body)
+}
+
+// ResendVerification sends a new verification link to a signed-in user, invalidating earlier links
+func (s *AccountService) ResendVerification(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.SendVerification(ctx, user)
+}
+
+// VerifyEmail confirms the address a verification link was sent to
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	userID, err := s.consume(ctx, domain.AccountTokenVerifyEmail, token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, userID); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+	return nil
+}
+
+// ForgotPassword emails a password reset link if the address belongs to a user
+// Unknown addresses are ignored silently so the endpoint cannot reveal who has an account
+func (s *AccountService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	token, err := s.issue(ctx, user.ID, domain.AccountTokenResetPassword, s.cfg.GetResetDuration())
+	if err != nil {
+		return err
+	}
+
+	body := []string{
+		fmt.Sprintf("%s，你好：", user.Username),
+		"",
+		"我们收到了重置你 UniFocus 密码的请求，请点击下面的链接设置新密码：",
+		s.link("/reset-password", token),
+		"",
+		fmt.Sprintf("链接将在%d分钟后失效，且只能使用一次。如果这不是你本人的操作，请忽略本邮件，你的密码不会改变。", s.cfg.ResetExpireMinutes),
+	}
+	return s.send(ctx, user.Email, "重置你的 UniFocus 密码", body)
+}
+
+// ResetPassword sets a new password using a reset link and signs out all sessions
+// Receiving the link also proves ownership of the address, so it is marked verified
+func (s *AccountService) ResetPassword(ctx context.Context, token, password string) error {
+	userID, err := s.consume(ctx, domain.AccountTokenResetPassword, token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.auth.SetPassword(ctx, userID, password); err != nil {
+		return err
+	}
+	if err := s.userRepo.MarkEmailVerified(ctx, userID); err != nil {
+		logger.Warnf("failed to mark email of user %d verified: %v", userID, err)
+	}
+	return nil
+}
+
+// ChangePassword changes a signed-in user's password. All existing sessions,
+// including the current one, are signed out; a new session is returned.
+func (s *AccountService) ChangePassword(ctx context.Context, userID int64, req *domain.ChangePasswordRequest, client *domain.ClientInfo) (*domain.TokenPair, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	if err := s.auth.SetPassword(ctx, userID, req.NewPassword); err != nil {
+		return nil, err
+	}
+	return s.auth.startSession(ctx, user, client)
+}
+
+// ForcePasswordReset replaces the user's password with a random one, which signs
+// out every session, token and API key, and emails them a link to choose a new one
+func (s *AccountService) ForcePasswordReset(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// 随机密码不告知任何人，用户只能通过邮件中的链接设置新密码
+	password, err := newSecureToken()
+	if err != nil {
+		return err
+	}
+	if err := s.auth.SetPassword(ctx, userID, password); err != nil {
+		return err
+	}
+
+	token, err := s.issue(ctx, userID, domain.AccountTokenResetPassword, s.cfg.GetResetDuration())
+	if err != nil {
+		return err
+	}
+
+	body := []string{
+		fmt.Sprintf("%s，你好：", user.Username),
+		"",
+		"出于安全原因，管理员已重置你的 UniFocus 密码，所有设备均已退出登录。请点击下面的链接设置新密码：",
+		s.link("/reset-password", token),
+		"",
+		fmt.Sprintf("链接将在%d分钟后失效，且只能使用一次。链接失效后可在登录页通过“忘记密码”重新获取。", s.cfg.ResetExpireMinutes),
+	}
+	return s.send(ctx, user.Email, "你的 UniFocus 密码已被重置", body)
+}
+
+// issue 生成一次性令牌并保存摘要，同用途的旧令牌随之失效
+func (s *AccountService) issue(ctx context.Context, userID int64, purpose string, ttl time.Duration) (string, error) {
+	token, err := newSecureToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.tokenRepo.Create(ctx, userID, purpose, hashToken(token), time.Now().Add(ttl)); err != nil {
+		return "", fmt.Errorf("failed to save %s token: %w", purpose, err)
+	}
+	return token, nil
+}
+
+// consume 使用一次性令牌，返回其所属用户
+func (s *AccountService) consume(ctx context.Context, purpose, token string) (int64, error) {
+	userID, err := s.tokenRepo.Consume(ctx, purpose, hashToken(token))
+	if err != nil {
+		if err.Error() == "token not found" {
+			return 0, ErrInvalidAccountToken
+		}
+		return 0, fmt.Errorf("failed to use %s token: %w", purpose, err)
+	}
+	return userID, nil
+}
+
+// link 构造邮件中指向前端页面的链接
+func (s *AccountService) link(path, token string) string {
+	return s.cfg.BaseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// send 发送纯文本邮件
+func (s *AccountService) send(ctx context.Context, to, subject string, lines []string) error {
+	msg := &mail.Message{
+		To:      []string{to},
+		Subject: subject,
+		Text:    strings.Join(lines, "\n"),
+	}
+	if err := s.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+)
+
+// recentAuditLimit 用户详情中附带的审计日志条数
+const recentAuditLimit = 20
+
+var (
+	// ErrCannotModifySelf indicates an administrator tried to suspend themselves or change their own role
+	ErrCannotModifySelf = errors.New("administrators cannot suspend themselves or change their own role")
+	// ErrUserAlreadySuspended indicates the account to suspend is already suspended
+	ErrUserAlreadySuspended = errors.New("user is already suspended")
+	// ErrUserNotSuspended indicates the account to reactivate is not suspended
+	ErrUserNotSuspended = errors.New("user is not suspended")
+)
+
+// AdminService lets administrators find and inspect users, suspend and reactivate
+// accounts, force password resets and change roles. Every action on a user is
+// written to the audit log with the administrator as actor, in the same
+// transaction as the change itself.
+type AdminService struct {
+	db           *postgres.DB
+	userRepo     *postgres.UserRepository
+	profileRepo  *postgres.ProfileRepository
+	uoRepo       *postgres.UserOpportunityRepository
+	scheduleRepo *postgres.ScheduleRepository
+	sessionRepo  *postgres.SessionRepository
+	apiKeyRepo   *postgres.APIKeyRepository
+	deletionRepo *postgres.AccountDeletionRepository
+	auditRepo    *postgres.AuditLogRepository
+	auth         *AuthService
+	accounts     *AccountService
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(
+	db *postgres.DB,
+	userRepo *postgres.UserRepository,
+	profileRepo *postgres.ProfileRepository,
+	uoRepo *postgres.UserOpportunityRepository,
+	scheduleRepo *postgres.ScheduleRepository,
+	sessionRepo *postgres.SessionRepository,
+	apiKeyRepo *postgres.APIKeyRepository,
+	deletionRepo *postgres.AccountDeletionRepository,
+	auditRepo *postgres.AuditLogRepository,
+	auth *AuthService,
+	accounts *AccountService,
+) *AdminService {
+	return &AdminService{
+		db:           db,
+		userRepo:     userRepo,
+		profileRepo:  profileRepo,
+		uoRepo:       uoRepo,
+		scheduleRepo: scheduleRepo,
+		sessionRepo:  sessionRepo,
+		apiKeyRepo:   apiKeyRepo,
+		deletionRepo: deletionRepo,
+		auditRepo:    auditRepo,
+		auth:         auth,
+		accounts:     accounts,
+	}
+}
+
+// SearchUsers finds users by email, school, major, role and status
+func (s *AdminService) SearchUsers(ctx context.Context, filter *domain.AdminUserFilter) ([]*domain.User, int64, error) {
+	users, total, err := s.userRepo.Search(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+	return users, total, nil
+}
+
+// GetUser returns a user's account, profile, activity summary, pending deletion
+// and most recent audit entries. Viewing is itself recorded in the audit log.
+func (s *AdminService) GetUser(ctx context.Context, adminID, userID int64, client *domain.ClientInfo) (*domain.AdminUserDetail, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	detail := &domain.AdminUserDetail{User: user}
+
+	detail.Profile, err = s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil && err.Error() != "profile not found" {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	if detail.Activity, err = s.activity(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	detail.PendingDeletion, err = s.deletionRepo.Get(ctx, userID)
+	if err != nil && err.Error() != "deletion request not found" {
+		return nil, fmt.Errorf("failed to get deletion request: %w", err)
+	}
+
+	if err := s.audit(ctx, adminID, userID, domain.AuditAdminUserViewed, nil, client); err != nil {
+		return nil, err
+	}
+
+	detail.RecentAudit, err = s.auditRepo.ListByUser(ctx, userID, recentAuditLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	return detail, nil
+}
+
+// ListAuditLogs returns the most recent audit entries about a user
+func (s *AdminService) ListAuditLogs(ctx context.Context, userID int64, limit int) ([]*domain.AuditLog, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	logs, err := s.auditRepo.ListByUser(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	return logs, nil
+}
+
+// Suspend blocks the user from signing in and from using existing tokens and API
+// keys until the account is reactivated. Digests and reminders are not sent while suspended.
+func (s *AdminService) Suspend(ctx context.Context, adminID, userID int64, reason string, client *domain.ClientInfo) (*domain.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.SuspendedAt != nil {
+		return nil, ErrUserAlreadySuspended
+	}
+
+	now := time.Now()
+	err = s.db.InTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.SetSuspended(ctx, userID, &now); err != nil {
+			if err.Error() == "user not found" {
+				return err
+			}
+			return fmt.Errorf("failed to suspend user: %w", err)
+		}
+		return s.audit(ctx, adminID, userID, domain.AuditAdminUserSuspended, domain.JSONB{"reason": reason}, client)
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.SuspendedAt = &now
+	return user, nil
+}
+
+// Reactivate lifts a suspension; sessions and API keys that had not expired work again
+func (s *AdminService) Reactivate(ctx context.Context, adminID, userID int64, client *domain.ClientInfo) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.SuspendedAt == nil {
+		return nil, ErrUserNotSuspended
+	}
+
+	detail := domain.JSONB{"suspended_at": user.SuspendedAt.UTC().Format(time.RFC3339)}
+	err = s.db.InTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.SetSuspended(ctx, userID, nil); err != nil {
+			if err.Error() == "user not found" {
+				return err
+			}
+			return fmt.Errorf("failed to reactivate user: %w", err)
+		}
+		return s.audit(ctx, adminID, userID, domain.AuditAdminUserReactivated, detail, client)
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.SuspendedAt = nil
+	return user, nil
+}
+
+// ForcePasswordReset invalidates the user's password and every session, token and
+// API key, and emails them a reset link
+func (s *AdminService) ForcePasswordReset(ctx context.Context, adminID, userID int64, client *domain.ClientInfo) error {
+	return s.db.InTx(ctx, func(ctx context.Context) error {
+		// 先写审计日志，发送重置邮件是事务中的最后一步
+		if err := s.audit(ctx, adminID, userID, domain.AuditAdminPasswordResetForced, nil, client); err != nil {
+			return err
+		}
+		return s.accounts.ForcePasswordReset(ctx, userID)
+	})
+}
+
+// ChangeRole changes the user's role. Administrators cannot change their own role,
+// so the last administrator cannot lock everyone out by accident.
+func (s *AdminService) ChangeRole(ctx context.Context, adminID, userID int64, role string, client *domain.ClientInfo) (*domain.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+	current, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+	detail := domain.JSONB{"from": current.Role, "to": role}
+	err = s.db.InTx(ctx, func(ctx context.Context) error {
+		if user, err = s.auth.GrantRole(ctx, userID, role); err != nil {
+			return err
+		}
+		return s.audit(ctx, adminID, userID, domain.AuditAdminRoleChanged, detail, client)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// RevokeTokens signs the user out everywhere and revokes their API keys
+// The access token epoch lives in Redis, so it stays bumped even if the transaction
+// rolls back; the user then only has to sign in again.
+func (s *AdminService) RevokeTokens(ctx context.Context, adminID, userID int64, client *domain.ClientInfo) error {
+	return s.db.InTx(ctx, func(ctx context.Context) error {
+		if err := s.auth.RevokeAllTokens(ctx, userID); err != nil {
+			return err
+		}
+		return s.audit(ctx, adminID, userID, domain.AuditAdminTokensRevoked, nil, client)
+	})
+}
+
+// activity 汇总用户的收藏、日程、会话与API密钥；最近活跃时间取自仍有效的会话
+func (s *AdminService) activity(ctx context.Context, userID int64) (*domain.UserActivity, error) {
+	opportunities, err := s.uoRepo.CountByStatus(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count user opportunities: %w", err)
+	}
+	schedules, err := s.scheduleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	sessions, err := s.sessionRepo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	apiKeys, err := s.apiKeyRepo.CountActive(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count api keys: %w", err)
+	}
+
+	activity := &domain.UserActivity{
+		Opportunities:  opportunities,
+		Schedules:      len(schedules),
+		ActiveSessions: len(sessions),
+		APIKeys:        apiKeys,
+	}
+	if len(sessions) > 0 {
+		// 会话按最近使用时间倒序
+		activity.LastActiveAt = &sessions[0].LastUsedAt
+	}
+	return activity, nil
+}
+
+// audit 记录管理员对用户的操作
+func (s *AdminService) audit(ctx context.Context, adminID, userID int64, action string, detail domain.JSONB, client *domain.ClientInfo) error {
+	entry := &domain.AuditLog{
+		UserID:  userID,
+		ActorID: &adminID,
+		Action:  action,
+		Detail:  detail,
+		IP:      clientIP(client),
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to write audit log for %s: %w", action, err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+const (
+	// maxAPIKeysPerUser 每个用户同时有效的个人访问令牌上限
+	maxAPIKeysPerUser = 20
+	// defaultAPIKeyExpireDays 未指定有效期时的默认天数
+	defaultAPIKeyExpireDays = 90
+	// apiKeyDisplayPrefix 保存并展示的令牌开头长度（含 ufk_ 前缀）
+	apiKeyDisplayPrefix = 12
+)
+
+var (
+	// ErrInvalidAPIKey indicates the API key is unknown, revoked or expired
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrInsufficientScope indicates the API key lacks the scope the endpoint requires
+	ErrInsufficientScope = errors.New("api key lacks the required scope")
+	// ErrTooManyAPIKeys indicates the user already has the maximum number of active API keys
+	ErrTooManyAPIKeys = errors.New("too many api keys")
+)
+
+// IsAPIKey reports whether a bearer token is a personal API key rather than a JWT
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, domain.APIKeyPrefix)
+}
+
+// CreateAPIKey creates a personal API key. The token is returned only here;
+// only its hash is stored.
+func (s *AuthService) CreateAPIKey(ctx context.Context, userID int64, req *domain.CreateAPIKeyRequest) (*domain.CreateAPIKeyResponse, error) {
+	count, err := s.apiKeyRepo.CountActive(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count api keys: %w", err)
+	}
+	if count >= maxAPIKeysPerUser {
+		return nil, ErrTooManyAPIKeys
+	}
+
+	secret, err := newSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	token := domain.APIKeyPrefix + secret
+
+	days := req.ExpiresInDays
+	if days <= 0 {
+		days = defaultAPIKeyExpireDays
+	}
+
+	// 去重并按固定顺序保存权限范围
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range domain.APIKeyScopes {
+		if containsString(req.Scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	key := &domain.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    token[:apiKeyDisplayPrefix],
+		Scopes:    scopes,
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	}
+	if err := s.apiKeyRepo.Create(ctx, key, hashToken(token)); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &domain.CreateAPIKeyResponse{APIKey: key, Token: token}, nil
+}
+
+// ListAPIKeys lists the user's API keys that have not been revoked, including expired ones
+func (s *AuthService) ListAPIKeys(ctx context.Context, userID int64) ([]*domain.APIKey, error) {
+	keys, err := s.apiKeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes one of the user's API keys; it stops working immediately
+func (s *AuthService) RevokeAPIKey(ctx context.Context, userID, keyID int64) error {
+	if err := s.apiKeyRepo.Revoke(ctx, userID, keyID); err != nil {
+		if err.Error() == "api key not found" {
+			return err
+		}
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// ValidateAPIKey authenticates a request made with a personal API key. The key
+// must carry every scope in scopes. Its last use is recorded.
+func (s *AuthService) ValidateAPIKey(ctx context.Context, token, ip string, scopes ...string) (*domain.User, *domain.APIKey, error) {
+	key, err := s.apiKeyRepo.GetByHash(ctx, hashToken(token))
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if !key.ExpiresAt.After(time.Now()) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	for _, scope := range scopes {
+		if !key.HasScope(scope) {
+			return nil, nil, ErrInsufficientScope
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return nil, nil, errors.New("user not found")
+	}
+	if user.SuspendedAt != nil {
+		return nil, nil, ErrAccountSuspended
+	}
+
+	if err := s.apiKeyRepo.Touch(ctx, key.ID, truncateRunes(ip, 64)); err != nil {
+		logger.Warnf("failed to record use of api key %d: %v", key.ID, err)
+	}
+
+	return user, key, nil
+}
@@ -2,53 +2,132 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/unifocus/backend/internal/config"
 	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/ratelimit"
 	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
 	"github.com/unifocus/backend/pkg/jwt"
+	"github.com/unifocus/backend/pkg/logger"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// secureTokenBytes 刷新令牌与邮件链接令牌的随机字节数
+const secureTokenBytes = 32
+
+// 访问令牌吊销记录的Redis键，记录保留到被吊销的令牌自然过期为止
+const (
+	revokedTokenKey   = "auth:revoked:token:%s"   // 已登出的单个令牌（jti）
+	revokedSessionKey = "auth:revoked:session:%d" // 已结束会话签发的全部令牌
+	tokenEpochKey     = "auth:epoch:%d"           // 早于该时间（Unix毫秒）签发的令牌全部失效
+)
+
+// streamTokenKey 通知流令牌（SHA-256摘要）到签发它的用户与会话，连接时取出并删除
+const streamTokenKey = "auth:stream:%s"
+
+// StreamTokenTTL 通知流令牌签发后须在此时间内用于建立连接
+const StreamTokenTTL = time.Minute
+
+var (
+	// ErrInvalidRefreshToken indicates the refresh token is unknown, expired or its session has ended
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused indicates an already rotated refresh token was presented again;
+	// its whole session is revoked because the token has likely been stolen
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// ErrTokenRevoked indicates the access token was revoked before it expired
+	ErrTokenRevoked = errors.New("token revoked")
+	// ErrEmailNotVerified indicates the user must verify their email before signing in
+	ErrEmailNotVerified = errors.New("email not verified")
+	// ErrAccountSuspended indicates an administrator has suspended the account
+	ErrAccountSuspended = errors.New("account suspended")
+	// ErrInvalidStreamToken indicates the stream token is unknown, expired or already used
+	ErrInvalidStreamToken = errors.New("invalid stream token")
+)
+
+// userStore 认证用到的用户存取，由 *postgres.UserRepository 实现，测试中替换为内存实现
+type userStore interface {
+	Create(ctx context.Context, user *domain.User) error
+	GetByID(ctx context.Context, id int64) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	UpdatePassword(ctx context.Context, userID int64, passwordHash string) error
+	UpdateRole(ctx context.Context, userID int64, role string) error
+}
+
+// sessionStore 登录会话与刷新令牌的存取，由 *postgres.SessionRepository 实现，测试中替换为内存实现
+type sessionStore interface {
+	Create(ctx context.Context, session *domain.Session, tokenHash string) error
+	GetByID(ctx context.Context, id int64) (*domain.Session, error)
+	GetToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	Rotate(ctx context.Context, token *domain.RefreshToken, newHash string, expiresAt time.Time) (bool, error)
+	ListActive(ctx context.Context, userID int64) ([]*domain.Session, error)
+	Revoke(ctx context.Context, userID, id int64) error
+	RevokeAll(ctx context.Context, userID, exceptID int64) ([]int64, error)
+}
+
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo *postgres.UserRepository
-	jwtMgr   *jwt.Manager
+	userRepo        userStore
+	sessionRepo     sessionStore
+	apiKeyRepo      *postgres.APIKeyRepository
+	jwtMgr          *jwt.Manager
+	rdb             *redis.Client // 访问令牌吊销列表
+	loginGuard      *ratelimit.LoginGuard
+	refreshTTL      time.Duration
+	requireVerified bool             // 未验证邮箱的用户不签发令牌
+	taxonomy        *TaxonomyService // 专业名称规范化，可为nil
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(userRepo *postgres.UserRepository, jwtMgr *jwt.Manager) *AuthService {
+func NewAuthService(cfg config.JWTConfig, authCfg config.AuthConfig, userRepo *postgres.UserRepository, sessionRepo *postgres.SessionRepository, apiKeyRepo *postgres.APIKeyRepository, jwtMgr *jwt.Manager, rdb *redis.Client, taxonomy *TaxonomyService) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		jwtMgr:   jwtMgr,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		apiKeyRepo:      apiKeyRepo,
+		jwtMgr:          jwtMgr,
+		rdb:             rdb,
+		loginGuard:      ratelimit.NewLoginGuard(authCfg.Login, rdb),
+		refreshTTL:      cfg.GetRefreshDuration(),
+		requireVerified: authCfg.RequireEmailVerification,
+		taxonomy:        taxonomy,
 	}
 }
 
-// Register registers a new user
-func (s *AuthService) Register(ctx context.Context, req *domain.CreateUserRequest) (*domain.User, string, error) {
+// Register registers a new user and starts a session for them. When email
+// verification is required no session is started and the tokens are nil.
+func (s *AuthService) Register(ctx context.Context, req *domain.CreateUserRequest, client *domain.ClientInfo) (*domain.User, *domain.TokenPair, error) {
 	// Check if email already exists
 	exists, err := s.userRepo.ExistsByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to check email existence: %w", err)
+		return nil, nil, fmt.Errorf("failed to check email existence: %w", err)
 	}
 	if exists {
-		return nil, "", errors.New("email already exists")
+		return nil, nil, errors.New("email already exists")
 	}
 
 	// Check if username already exists
 	exists, err = s.userRepo.ExistsByUsername(ctx, req.Username)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to check username existence: %w", err)
+		return nil, nil, fmt.Errorf("failed to check username existence: %w", err)
 	}
 	if exists {
-		return nil, "", errors.New("username already exists")
+		return nil, nil, errors.New("username already exists")
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to hash password: %w", err)
+		return nil, nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	// Create user
@@ -57,75 +136,410 @@ func (s *AuthService) Register(ctx context.Context, req *domain.CreateUserReques
 		Email:     req.Email,
 		Password:  string(hashedPassword),
 		School:    req.School,
-		Major:     req.Major,
+		Major:     s.taxonomy.NormalizeMajor(req.Major),
 		Grade:     req.Grade,
 		AvatarURL: "",
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, "", fmt.Errorf("failed to create user: %w", err)
+		return nil, nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	if s.requireVerified {
+		return user, nil, nil
 	}
 
-	// Generate JWT token
-	token, err := s.jwtMgr.GenerateToken(user.ID, user.Username, user.Email)
+	tokens, err := s.startSession(ctx, user, client)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, nil, err
 	}
 
-	return user, token, nil
+	return user, tokens, nil
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *AuthService) Login(ctx context.Context, req *domain.LoginRequest) (*domain.User, string, error) {
+// Login authenticates a user and starts a new session
+// Repeated failures for the same account or from the same IP are throttled and
+// eventually locked out; such rejections are returned as *ratelimit.LimitError.
+func (s *AuthService) Login(ctx context.Context, req *domain.LoginRequest, client *domain.ClientInfo) (*domain.User, *domain.TokenPair, error) {
+	var ip string
+	if client != nil {
+		ip = client.IP
+	}
+	if err := s.loginGuard.Check(ctx, req.Email, ip); err != nil {
+		return nil, nil, err
+	}
+
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, "", errors.New("invalid email or password")
+		return nil, nil, s.loginFailed(ctx, req.Email, ip)
 	}
 
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return nil, "", errors.New("invalid email or password")
+		return nil, nil, s.loginFailed(ctx, req.Email, ip)
+	}
+	s.loginGuard.Succeed(ctx, req.Email)
+	if s.requireVerified && user.EmailVerifiedAt == nil {
+		return nil, nil, ErrEmailNotVerified
 	}
 
-	// Generate JWT token
-	token, err := s.jwtMgr.GenerateToken(user.ID, user.Username, user.Email)
+	tokens, err := s.startSession(ctx, user, client)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+		return nil, nil, err
 	}
 
-	return user, token, nil
+	return user, tokens, nil
+}
+
+// loginFailed records a failed login attempt and returns the error to report
+func (s *AuthService) loginFailed(ctx context.Context, email, ip string) error {
+	if err := s.loginGuard.Fail(ctx, email, ip); err != nil {
+		return err
+	}
+	return errors.New("invalid email or password")
 }
 
-// ValidateToken validates a JWT token and returns the user
-func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*domain.User, error) {
+// ValidateToken validates an access token, rejects revoked ones and those of
+// suspended users, and returns the user and its claims
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*domain.User, *jwt.Claims, error) {
 	claims, err := s.jwtMgr.ValidateToken(tokenString)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := s.checkRevoked(ctx, claims); err != nil {
+		return nil, nil, err
 	}
 
 	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, nil, errors.New("user not found")
+	}
+	if user.SuspendedAt != nil {
+		return nil, nil, ErrAccountSuspended
+	}
+
+	return user, claims, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. The presented token is
+// rotated: it stops working and a successor is issued in the same session.
+// Presenting a rotated token again revokes the whole session.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	token, err := s.sessionRepo.GetToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, token.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.RevokedAt != nil || !session.ExpiresAt.After(time.Now().UTC()) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if token.UsedAt != nil {
+		return nil, s.revokeReused(ctx, session)
+	}
+
+	next, err := newSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.sessionRepo.Rotate(ctx, token, hashToken(next), time.Now().Add(s.refreshTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !rotated {
+		return nil, s.revokeReused(ctx, session)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
+	if user.SuspendedAt != nil {
+		return nil, ErrAccountSuspended
+	}
+	return s.tokenPair(user, session.ID, next)
+}
+
+// IssueStreamToken issues a single-use token that opens the notification stream
+// in place of the access token, for clients such as the browser EventSource that
+// cannot set the Authorization header. It belongs to the session of claims.
+func (s *AuthService) IssueStreamToken(ctx context.Context, claims *jwt.Claims) (*domain.StreamToken, error) {
+	token, err := newSecureToken()
+	if err != nil {
+		return nil, err
+	}
+
+	value := fmt.Sprintf("%d:%d", claims.UserID, claims.SessionID)
+	if err := s.rdb.Set(ctx, fmt.Sprintf(streamTokenKey, hashToken(token)), value, StreamTokenTTL); err != nil {
+		return nil, fmt.Errorf("failed to store stream token: %w", err)
+	}
+	return &domain.StreamToken{Token: token, ExpiresIn: int64(StreamTokenTTL / time.Second)}, nil
+}
+
+// ValidateStreamToken consumes a stream token and returns its user and session.
+// Tokens of sessions that have since ended and of suspended users are rejected.
+func (s *AuthService) ValidateStreamToken(ctx context.Context, token string) (*domain.User, int64, error) {
+	if token == "" {
+		return nil, 0, ErrInvalidStreamToken
+	}
+
+	value, err := s.rdb.GetDel(ctx, s.rdb.Key(fmt.Sprintf(streamTokenKey, hashToken(token)))).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, 0, ErrInvalidStreamToken
+		}
+		return nil, 0, fmt.Errorf("failed to load stream token: %w", err)
+	}
+
+	var userID, sessionID int64
+	if _, err := fmt.Sscanf(value, "%d:%d", &userID, &sessionID); err != nil {
+		return nil, 0, ErrInvalidStreamToken
+	}
+	if sessionID != 0 {
+		revoked, err := s.rdb.Exists(ctx, fmt.Sprintf(revokedSessionKey, sessionID))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, 0, ErrTokenRevoked
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, 0, errors.New("user not found")
+	}
+	if user.SuspendedAt != nil {
+		return nil, 0, ErrAccountSuspended
+	}
+	return user, sessionID, nil
+}
+
+// ListSessions lists the user's active sessions, flagging the one with ID currentID
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentID int64) ([]*domain.Session, error) {
+	sessions, err := s.sessionRepo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentID
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's sessions out: its refresh token and
+// the access tokens it issued stop working immediately
+func (s *AuthService) RevokeSession(ctx context.Context, userID, id int64) error {
+	if err := s.sessionRepo.Revoke(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.denySessions(ctx, id)
+}
+
+// RevokeOtherSessions signs out every session of the user except currentID
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID, currentID int64) (int64, error) {
+	ids, err := s.sessionRepo.RevokeAll(ctx, userID, currentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if err := s.denySessions(ctx, ids...); err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// Logout revokes the presented access token and ends the session it belongs to
+func (s *AuthService) Logout(ctx context.Context, claims *jwt.Claims) error {
+	if claims.ExpiresAt != nil {
+		if ttl := time.Until(claims.ExpiresAt.Time); ttl > 0 {
+			if err := s.rdb.Set(ctx, fmt.Sprintf(revokedTokenKey, claims.ID), 1, ttl); err != nil {
+				return fmt.Errorf("failed to revoke token: %w", err)
+			}
+		}
+	}
+
+	if claims.SessionID == 0 {
+		return nil
+	}
+	if err := s.RevokeSession(ctx, claims.UserID, claims.SessionID); err != nil && err.Error() != "session not found" {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllTokens invalidates every access token issued to the user so far, ends
+// all of their sessions and revokes their API keys, e.g. after a password change
+// or on an admin's request
+func (s *AuthService) RevokeAllTokens(ctx context.Context, userID int64) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
 
+	// 纪元精确到毫秒，吊销前同一秒内签发的令牌也会失效，吊销后立即重新登录签发的令牌不受影响
+	epoch := time.Now().UnixMilli()
+	if err := s.rdb.Set(ctx, fmt.Sprintf(tokenEpochKey, userID), epoch, s.jwtMgr.Expiration()); err != nil {
+		return fmt.Errorf("failed to bump token epoch: %w", err)
+	}
+	if _, err := s.sessionRepo.RevokeAll(ctx, userID, 0); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if err := s.apiKeyRepo.RevokeAll(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke api keys: %w", err)
+	}
+	return nil
+}
+
+// GrantRole changes a user's role. The new role is checked on the user's next
+// request and appears in the claims of the next access token issued to them.
+func (s *AuthService) GrantRole(ctx context.Context, userID int64, role string) (*domain.User, error) {
+	if err := s.userRepo.UpdateRole(ctx, userID, role); err != nil {
+		if err.Error() == "user not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
 	return user, nil
 }
 
-// RefreshToken generates a new token from an existing valid token
-func (s *AuthService) RefreshToken(ctx context.Context, tokenString string) (string, error) {
-	claims, err := s.jwtMgr.ValidateToken(tokenString)
+// SetPassword replaces the user's password and signs out every existing token and session
+func (s *AuthService) SetPassword(ctx context.Context, userID int64, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		if err.Error() == "user not found" {
+			return err
+		}
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return s.RevokeAllTokens(ctx, userID)
+}
+
+// checkRevoked 检查令牌是否已被登出、所属会话是否已结束、是否早于用户的令牌纪元
+// Redis不可用时拒绝令牌，避免已吊销的令牌被放行
+func (s *AuthService) checkRevoked(ctx context.Context, claims *jwt.Claims) error {
+	values, err := s.rdb.MGet(ctx,
+		s.rdb.Key(fmt.Sprintf(revokedTokenKey, claims.ID)),
+		s.rdb.Key(fmt.Sprintf(revokedSessionKey, claims.SessionID)),
+		s.rdb.Key(fmt.Sprintf(tokenEpochKey, claims.UserID)),
+	).Result()
 	if err != nil {
-		return "", err
+		return fmt.Errorf("failed to check token revocation: %w", err)
 	}
 
-	// Verify user still exists
-	_, err = s.userRepo.GetByID(ctx, claims.UserID)
+	epoch, _ := values[2].(string)
+	if tokenRevoked(claims, values[0] != nil, values[1] != nil, epoch) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// tokenRevoked 按吊销记录判断令牌是否失效：令牌已登出、所属会话已结束，或签发早于令牌纪元（Unix毫秒，空串表示未设置）
+// 缺少毫秒签发时间的令牌按所在秒的起点比较，同一秒内的一并视为已吊销
+func tokenRevoked(claims *jwt.Claims, tokenDenied, sessionDenied bool, epoch string) bool {
+	if tokenDenied || (claims.SessionID != 0 && sessionDenied) {
+		return true
+	}
+	if epoch == "" || claims.IssuedAt == nil {
+		return false
+	}
+
+	e, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return false
+	}
+	issuedAt := claims.IssuedAt.UnixMilli()
+	if claims.IssuedAtMs != 0 {
+		issuedAt = claims.IssuedAtMs
+	}
+	return issuedAt < e
+}
+
+// denySessions 吊销会话已签发的访问令牌，记录保留一个访问令牌有效期
+func (s *AuthService) denySessions(ctx context.Context, ids ...int64) error {
+	for _, id := range ids {
+		if err := s.rdb.Set(ctx, fmt.Sprintf(revokedSessionKey, id), 1, s.jwtMgr.Expiration()); err != nil {
+			return fmt.Errorf("failed to revoke session tokens: %w", err)
+		}
+	}
+	return nil
+}
+
+// startSession 创建登录会话并签发令牌；已停用的账号不能登录，client 为nil时不记录设备信息
+func (s *AuthService) startSession(ctx context.Context, user *domain.User, client *domain.ClientInfo) (*domain.TokenPair, error) {
+	if user.SuspendedAt != nil {
+		return nil, ErrAccountSuspended
+	}
+
+	refreshToken, err := newSecureToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+	if client != nil {
+		session.UserAgent = truncateRunes(client.UserAgent, 500)
+		session.IP = truncateRunes(client.IP, 64)
+	}
+	if err := s.sessionRepo.Create(ctx, session, hashToken(refreshToken)); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.tokenPair(user, session.ID, refreshToken)
+}
+
+// tokenPair 为会话签发访问令牌，与刷新令牌一起返回
+func (s *AuthService) tokenPair(user *domain.User, sessionID int64, refreshToken string) (*domain.TokenPair, error) {
+	token, err := s.jwtMgr.GenerateToken(user.ID, user.Username, user.Email, user.Role, sessionID)
 	if err != nil {
-		return "", errors.New("user not found")
+		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	// Generate new token
-	return s.jwtMgr.RefreshToken(tokenString)
+	return &domain.TokenPair{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtMgr.Expiration() / time.Second),
+	}, nil
+}
+
+// revokeReused 已轮换的刷新令牌被再次使用，撤销整个会话
+func (s *AuthService) revokeReused(ctx context.Context, session *domain.Session) error {
+	logger.Warnf("Refresh token reuse detected for user %d, revoking session %d", session.UserID, session.ID)
+	if err := s.RevokeSession(ctx, session.UserID, session.ID); err != nil && err.Error() != "session not found" {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// newSecureToken 生成不透明的随机令牌（刷新令牌、邮件链接令牌）
+func newSecureToken() (string, error) {
+	buf := make([]byte, secureTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken 返回令牌的SHA-256十六进制摘要
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
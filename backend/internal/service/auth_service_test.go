@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/jwt"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+func TestTokenRevoked(t *testing.T) {
+	// 令牌纪元落在某一秒的第500毫秒
+	const second = int64(1_700_000_000)
+	epoch := "1700000000500"
+
+	claims := func(sessionID, issuedAtMs int64) *jwt.Claims {
+		c := &jwt.Claims{SessionID: sessionID, IssuedAtMs: issuedAtMs}
+		if issuedAtMs != 0 {
+			c.IssuedAt = jwtv5.NewNumericDate(time.UnixMilli(issuedAtMs))
+		}
+		return c
+	}
+	// 旧令牌只有精确到秒的 iat
+	legacy := func(unix int64) *jwt.Claims {
+		return &jwt.Claims{SessionID: 1, RegisteredClaims: jwtv5.RegisteredClaims{IssuedAt: jwtv5.NewNumericDate(time.Unix(unix, 0))}}
+	}
+
+	tests := []struct {
+		name          string
+		claims        *jwt.Claims
+		tokenDenied   bool
+		sessionDenied bool
+		epoch         string
+		want          bool
+	}{
+		{"no revocation records", claims(1, second*1000+100), false, false, "", false},
+		{"revoked jti", claims(1, second*1000+100), true, false, "", true},
+		{"revoked session", claims(1, second*1000+100), false, true, "", true},
+		{"token without session ignores session record", claims(0, second*1000+100), false, true, "", false},
+		{"issued earlier second", claims(1, (second-1)*1000+900), false, false, epoch, true},
+		{"same second before epoch", claims(1, second*1000+499), false, false, epoch, true},
+		{"same millisecond as epoch", claims(1, second*1000+500), false, false, epoch, false},
+		{"same second after epoch", claims(1, second*1000+501), false, false, epoch, false},
+		{"issued later second", claims(1, (second+1)*1000), false, false, epoch, false},
+		{"missing iat_ms same second", legacy(second), false, false, epoch, true},
+		{"missing iat_ms later second", legacy(second + 1), false, false, epoch, false},
+		{"missing iat_ms epoch at second start", legacy(second), false, false, "1700000000000", false},
+		{"missing iat", &jwt.Claims{SessionID: 1}, false, false, epoch, false},
+		{"malformed epoch", claims(1, second*1000+100), false, false, "soon", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenRevoked(tt.claims, tt.tokenDenied, tt.sessionDenied, tt.epoch); got != tt.want {
+				t.Errorf("tokenRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// memoryRedis 以命令钩子在内存中执行认证服务用到的Redis命令，不建立网络连接
+type memoryRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newMemoryRedis() (*redis.Client, *memoryRedis) {
+	mem := &memoryRedis{values: make(map[string]string)}
+	client := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:0"})
+	client.AddHook(mem)
+	return &redis.Client{Client: client}, mem
+}
+
+func (m *memoryRedis) DialHook(next goredis.DialHook) goredis.DialHook { return next }
+
+func (m *memoryRedis) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return next
+}
+
+func (m *memoryRedis) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		args := make([]string, len(cmd.Args()))
+		for i, arg := range cmd.Args() {
+			args[i] = fmt.Sprint(arg)
+		}
+
+		switch c := cmd.(type) {
+		case *goredis.StatusCmd: // SET
+			m.values[args[1]] = args[2]
+			c.SetVal("OK")
+		case *goredis.StringCmd: // GET, GETDEL
+			value, ok := m.values[args[1]]
+			if !ok {
+				return goredis.Nil
+			}
+			if cmd.Name() == "getdel" {
+				delete(m.values, args[1])
+			}
+			c.SetVal(value)
+		case *goredis.SliceCmd: // MGET
+			values := make([]interface{}, len(args)-1)
+			for i, key := range args[1:] {
+				if value, ok := m.values[key]; ok {
+					values[i] = value
+				}
+			}
+			c.SetVal(values)
+		case *goredis.IntCmd: // EXISTS, DEL
+			var n int64
+			for _, key := range args[1:] {
+				if _, ok := m.values[key]; ok {
+					n++
+					if cmd.Name() == "del" {
+						delete(m.values, key)
+					}
+				}
+			}
+			c.SetVal(n)
+		default:
+			return fmt.Errorf("memoryRedis: unsupported command %q", cmd.Name())
+		}
+		return nil
+	}
+}
+
+func (m *memoryRedis) has(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.values[key]
+	return ok
+}
+
+// memoryUsers 只实现 GetByID 的用户存储
+type memoryUsers struct {
+	userStore
+	users map[int64]*domain.User
+}
+
+func (m *memoryUsers) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// memorySessions 内存中的会话与刷新令牌，beforeRotate 可模拟并发刷新抢先轮换
+type memorySessions struct {
+	sessionStore
+	sessions     map[int64]*domain.Session
+	tokens       map[string]*domain.RefreshToken
+	nextID       int64
+	beforeRotate func(token *domain.RefreshToken)
+}
+
+func newMemorySessions() *memorySessions {
+	return &memorySessions{
+		sessions: make(map[int64]*domain.Session),
+		tokens:   make(map[string]*domain.RefreshToken),
+	}
+}
+
+func (m *memorySessions) Create(ctx context.Context, session *domain.Session, tokenHash string) error {
+	m.nextID++
+	session.ID = m.nextID
+	session.CreatedAt = time.Now()
+	session.LastUsedAt = session.CreatedAt
+	stored := *session
+	m.sessions[session.ID] = &stored
+	m.addToken(session.ID, tokenHash)
+	return nil
+}
+
+func (m *memorySessions) addToken(sessionID int64, tokenHash string) {
+	m.nextID++
+	m.tokens[tokenHash] = &domain.RefreshToken{ID: m.nextID, SessionID: sessionID, TokenHash: tokenHash}
+}
+
+func (m *memorySessions) GetByID(ctx context.Context, id int64) (*domain.Session, error) {
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (m *memorySessions) GetToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	token, ok := m.tokens[tokenHash]
+	if !ok {
+		return nil, errors.New("refresh token not found")
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (m *memorySessions) Rotate(ctx context.Context, token *domain.RefreshToken, newHash string, expiresAt time.Time) (bool, error) {
+	if m.beforeRotate != nil {
+		m.beforeRotate(token)
+	}
+
+	stored := m.tokens[token.TokenHash]
+	if stored.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	stored.UsedAt = &now
+	m.addToken(token.SessionID, newHash)
+	m.sessions[token.SessionID].ExpiresAt = expiresAt
+	return true, nil
+}
+
+func (m *memorySessions) Revoke(ctx context.Context, userID, id int64) error {
+	session, ok := m.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return errors.New("session not found")
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	return nil
+}
+
+func newTestAuthService(users ...*domain.User) (*AuthService, *memorySessions, *memoryRedis) {
+	_ = logger.Init(&config.LogConfig{Level: "error"})
+	rdb, mem := newMemoryRedis()
+	sessions := newMemorySessions()
+	store := &memoryUsers{users: make(map[int64]*domain.User)}
+	for _, user := range users {
+		store.users[user.ID] = user
+	}
+
+	return &AuthService{
+		userRepo:    store,
+		sessionRepo: sessions,
+		jwtMgr:      jwt.NewManager(&config.JWTConfig{Secret: "test-secret", AccessExpireMinutes: 15}),
+		rdb:         rdb,
+		refreshTTL:  24 * time.Hour,
+	}, sessions, mem
+}
+
+func TestStartSession(t *testing.T) {
+	suspended := time.Now()
+
+	tests := []struct {
+		name          string
+		user          *domain.User
+		client        *domain.ClientInfo
+		wantErr       error
+		wantUserAgent string
+		wantIP        string
+	}{
+		{"nil client", &domain.User{ID: 1, Role: domain.RoleStudent}, nil, nil, "", ""},
+		{"client info recorded", &domain.User{ID: 1, Role: domain.RoleStudent}, &domain.ClientInfo{UserAgent: "Firefox", IP: "10.0.0.1"}, nil, "Firefox", "10.0.0.1"},
+		{"suspended user", &domain.User{ID: 1, SuspendedAt: &suspended}, nil, ErrAccountSuspended, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, sessions, _ := newTestAuthService(tt.user)
+
+			pair, err := s.startSession(context.Background(), tt.user, tt.client)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("startSession() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(sessions.sessions) != 0 {
+					t.Errorf("startSession() created %d sessions, want 0", len(sessions.sessions))
+				}
+				return
+			}
+
+			claims, err := s.jwtMgr.ValidateToken(pair.Token)
+			if err != nil {
+				t.Fatalf("ValidateToken() error = %v", err)
+			}
+			session, err := sessions.GetByID(context.Background(), claims.SessionID)
+			if err != nil {
+				t.Fatalf("session of the access token: %v", err)
+			}
+			if session.UserID != tt.user.ID || session.UserAgent != tt.wantUserAgent || session.IP != tt.wantIP {
+				t.Errorf("session = (user %d, %q, %q), want (user %d, %q, %q)",
+					session.UserID, session.UserAgent, session.IP, tt.user.ID, tt.wantUserAgent, tt.wantIP)
+			}
+			if _, err := sessions.GetToken(context.Background(), hashToken(pair.RefreshToken)); err != nil {
+				t.Errorf("refresh token not stored: %v", err)
+			}
+		})
+	}
+}
+
+func TestRefreshRotation(t *testing.T) {
+	user := &domain.User{ID: 1, Role: domain.RoleStudent}
+	s, sessions, _ := newTestAuthService(user)
+	ctx := context.Background()
+
+	first, err := s.startSession(ctx, user, nil)
+	if err != nil {
+		t.Fatalf("startSession() error = %v", err)
+	}
+	second, err := s.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatalf("Refresh() returned the presented refresh token, want a successor")
+	}
+
+	claims, err := s.jwtMgr.ValidateToken(second.Token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	firstClaims, _ := s.jwtMgr.ValidateToken(first.Token)
+	if claims.SessionID != firstClaims.SessionID {
+		t.Errorf("rotated access token session = %d, want %d", claims.SessionID, firstClaims.SessionID)
+	}
+	if token, _ := sessions.GetToken(ctx, hashToken(first.RefreshToken)); token.UsedAt == nil {
+		t.Errorf("presented refresh token was not marked used")
+	}
+
+	// 后继令牌可以继续轮换
+	if _, err := s.Refresh(ctx, second.RefreshToken); err != nil {
+		t.Errorf("Refresh() with the successor error = %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	user := &domain.User{ID: 1, Role: domain.RoleStudent}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// reuse 轮换首个刷新令牌后再次提交它（应返回 ErrRefreshTokenReused），返回轮换出的后继令牌
+		reuse func(t *testing.T, s *AuthService, sessions *memorySessions, pair *domain.TokenPair) string
+	}{
+		{
+			name: "replay of a rotated token",
+			reuse: func(t *testing.T, s *AuthService, sessions *memorySessions, pair *domain.TokenPair) string {
+				next, err := s.Refresh(ctx, pair.RefreshToken)
+				if err != nil {
+					t.Fatalf("Refresh() error = %v", err)
+				}
+				if _, err := s.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+					t.Fatalf("Refresh() replay error = %v, want %v", err, ErrRefreshTokenReused)
+				}
+				return next.RefreshToken
+			},
+		},
+		{
+			name: "concurrent refresh wins the rotation",
+			reuse: func(t *testing.T, s *AuthService, sessions *memorySessions, pair *domain.TokenPair) string {
+				var successor string
+				sessions.beforeRotate = func(token *domain.RefreshToken) {
+					sessions.beforeRotate = nil
+					next, err := s.Refresh(ctx, pair.RefreshToken)
+					if err != nil {
+						t.Fatalf("concurrent Refresh() error = %v", err)
+					}
+					successor = next.RefreshToken
+				}
+				// 提交时并发的刷新在 Rotate 前抢先完成轮换
+				if _, err := s.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+					t.Fatalf("Refresh() losing the race error = %v, want %v", err, ErrRefreshTokenReused)
+				}
+				return successor
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, sessions, mem := newTestAuthService(user)
+			pair, err := s.startSession(ctx, user, nil)
+			if err != nil {
+				t.Fatalf("startSession() error = %v", err)
+			}
+			claims, _ := s.jwtMgr.ValidateToken(pair.Token)
+
+			successor := tt.reuse(t, s, sessions, pair)
+
+			if session, _ := sessions.GetByID(ctx, claims.SessionID); session.RevokedAt == nil {
+				t.Errorf("session was not revoked")
+			}
+			if !mem.has(fmt.Sprintf(revokedSessionKey, claims.SessionID)) {
+				t.Errorf("access tokens of the session were not revoked")
+			}
+			if err := s.checkRevoked(ctx, claims); !errors.Is(err, ErrTokenRevoked) {
+				t.Errorf("checkRevoked() = %v, want %v", err, ErrTokenRevoked)
+			}
+			// 会话已撤销，尚未使用的后继令牌也不能再刷新
+			if _, err := s.Refresh(ctx, successor); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("Refresh() with the successor error = %v, want %v", err, ErrInvalidRefreshToken)
+			}
+		})
+	}
+}
+
+func TestRefreshRejected(t *testing.T) {
+	suspended := time.Now()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		prepare func(s *AuthService, sessions *memorySessions, user *domain.User, sessionID int64)
+		token   func(refreshToken string) string
+		wantErr error
+	}{
+		{
+			name:    "unknown token",
+			token:   func(string) string { return "unknown" },
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "expired session",
+			prepare: func(s *AuthService, sessions *memorySessions, user *domain.User, sessionID int64) {
+				sessions.sessions[sessionID].ExpiresAt = time.Now().Add(-time.Minute)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "signed out session",
+			prepare: func(s *AuthService, sessions *memorySessions, user *domain.User, sessionID int64) {
+				_ = sessions.Revoke(ctx, user.ID, sessionID)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "suspended user",
+			prepare: func(s *AuthService, sessions *memorySessions, user *domain.User, sessionID int64) {
+				user.SuspendedAt = &suspended
+			},
+			wantErr: ErrAccountSuspended,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{ID: 1, Role: domain.RoleStudent}
+			s, sessions, _ := newTestAuthService(user)
+			pair, err := s.startSession(ctx, user, nil)
+			if err != nil {
+				t.Fatalf("startSession() error = %v", err)
+			}
+			claims, _ := s.jwtMgr.ValidateToken(pair.Token)
+
+			if tt.prepare != nil {
+				tt.prepare(s, sessions, user, claims.SessionID)
+			}
+			token := pair.RefreshToken
+			if tt.token != nil {
+				token = tt.token(token)
+			}
+			if _, err := s.Refresh(ctx, token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Refresh() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
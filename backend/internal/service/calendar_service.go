@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/ical"
+	"github.com/unifocus/backend/internal/recurrence"
+	"github.com/unifocus/backend/internal/repository/postgres"
+)
+
+const (
+	// feedTokenBytes 订阅令牌的随机字节数
+	feedTokenBytes = 32
+	// openEndedHorizon 无结束日期的重复日程计算排除日期的范围
+	openEndedHorizon = 365 * 24 * time.Hour
+	// calendarUIDDomain 事件UID的域名后缀
+	calendarUIDDomain = "unifocus"
+)
+
+// ErrInvalidFeedToken indicates a calendar feed token is unknown or revoked
+var ErrInvalidFeedToken = errors.New("invalid calendar feed token")
+
+// calendarStatuses 导出到日历的机会状态
+var calendarStatuses = []string{domain.UserOpportunityStatusSaved, domain.UserOpportunityStatusApplied}
+
+// CalendarService builds iCalendar exports and manages subscription feed tokens
+type CalendarService struct {
+	cfg          config.CalendarConfig
+	loc          *time.Location
+	baseURL      string // 站点地址，用于构造订阅链接
+	userRepo     *postgres.UserRepository
+	feedRepo     *postgres.CalendarFeedRepository
+	oppRepo      *postgres.OpportunityRepository
+	uoRepo       *postgres.UserOpportunityRepository
+	scheduleRepo *postgres.ScheduleRepository
+	semesterRepo *postgres.SemesterRepository
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(
+	cfg config.CalendarConfig,
+	baseURL string,
+	userRepo *postgres.UserRepository,
+	feedRepo *postgres.CalendarFeedRepository,
+	oppRepo *postgres.OpportunityRepository,
+	uoRepo *postgres.UserOpportunityRepository,
+	scheduleRepo *postgres.ScheduleRepository,
+	semesterRepo *postgres.SemesterRepository,
+) *CalendarService {
+	return &CalendarService{
+		cfg:          cfg,
+		loc:          cfg.GetLocation(),
+		baseURL:      baseURL,
+		userRepo:     userRepo,
+		feedRepo:     feedRepo,
+		oppRepo:      oppRepo,
+		uoRepo:       uoRepo,
+		scheduleRepo: scheduleRepo,
+		semesterRepo: semesterRepo,
+	}
+}
+
+// CreateFeedToken generates a new feed token for the user, revoking the previous one
+// The plain token is only returned here; only its hash is stored
+func (s *CalendarService) CreateFeedToken(ctx context.Context, userID int64) (string, *domain.CalendarFeed, error) {
+	buf := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	feed, err := s.feedRepo.Upsert(ctx, userID, hashFeedToken(token))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to save feed token: %w", err)
+	}
+
+	return token, feed, nil
+}
+
+// FeedURL returns the subscription URL of a feed token under the configured site address
+func (s *CalendarService) FeedURL(token string) string {
+	return s.baseURL + "/api/v1/users/me/calendar.ics?token=" + url.QueryEscape(token)
+}
+
+// GetFeed retrieves the metadata of the user's feed token
+func (s *CalendarService) GetFeed(ctx context.Context, userID int64) (*domain.CalendarFeed, error) {
+	return s.feedRepo.GetByUserID(ctx, userID)
+}
+
+// RevokeFeedToken revokes the user's feed token
+func (s *CalendarService) RevokeFeedToken(ctx context.Context, userID int64) error {
+	return s.feedRepo.Delete(ctx, userID)
+}
+
+// FeedUserID resolves a feed token to its user; feeds of suspended accounts are refused
+func (s *CalendarService) FeedUserID(ctx context.Context, token string) (int64, error) {
+	if token == "" {
+		return 0, ErrInvalidFeedToken
+	}
+
+	userID, err := s.feedRepo.Touch(ctx, hashFeedToken(token))
+	if err != nil {
+		if err.Error() == "calendar feed not found" {
+			return 0, ErrInvalidFeedToken
+		}
+		return 0, fmt.Errorf("failed to resolve feed token: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return 0, ErrInvalidFeedToken
+		}
+		return 0, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.SuspendedAt != nil {
+		return 0, ErrAccountSuspended
+	}
+
+	return userID, nil
+}
+
+// UserCalendar builds the calendar of a user's saved and applied opportunities and schedules
+func (s *CalendarService) UserCalendar(ctx context.Context, userID int64) (*ical.Calendar, error) {
+	items, err := s.uoRepo.ListByStatuses(ctx, userID, calendarStatuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user opportunities: %w", err)
+	}
+
+	schedules, err := s.scheduleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	semesters, err := s.semesterRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list semesters: %w", err)
+	}
+
+	cal := s.newCalendar("UniFocus")
+	for _, item := range items {
+		cal.Events = append(cal.Events, opportunityEvents(item.Opportunity, s.cfg)...)
+	}
+
+	bySemester := semestersByID(semesters)
+	now := time.Now()
+	for _, sch := range schedules {
+		cal.Events = append(cal.Events, scheduleEvent(sch, bySemester[derefID(sch.SemesterID)], s.cfg, s.loc, now))
+	}
+
+	return cal, nil
+}
+
+// OpportunityCalendar builds a calendar holding the deadline and event date of one opportunity
+func (s *CalendarService) OpportunityCalendar(ctx context.Context, opportunityID int64) (*ical.Calendar, error) {
+	opp, err := s.oppRepo.GetByID(ctx, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := s.newCalendar(opp.Title)
+	cal.Refresh = 0
+	cal.Events = opportunityEvents(opp, s.cfg)
+
+	return cal, nil
+}
+
+func (s *CalendarService) newCalendar(name string) *ical.Calendar {
+	return &ical.Calendar{
+		Name:     name,
+		Location: s.loc,
+		Refresh:  time.Duration(s.cfg.RefreshMinutes) * time.Minute,
+	}
+}
+
+// hashFeedToken 返回令牌的SHA-256十六进制摘要
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// opportunityEvents 生成机会截止日期与活动日期的全天事件
+func opportunityEvents(opp *domain.Opportunity, cfg config.CalendarConfig) []*ical.Event {
+	var lines []string
+	if opp.Organizer != "" {
+		lines = append(lines, "主办方："+opp.Organizer)
+	}
+	if opp.Description != "" {
+		lines = append(lines, opp.Description)
+	}
+	if opp.SourceURL != "" {
+		lines = append(lines, opp.SourceURL)
+	}
+	description := strings.Join(lines, "\n")
+
+	var categories []string
+	if opp.Type != "" {
+		categories = []string{opp.Type}
+	}
+
+	var events []*ical.Event
+	if opp.Deadline != nil {
+		events = append(events, &ical.Event{
+			UID:         fmt.Sprintf("opportunity-%d-deadline@%s", opp.ID, calendarUIDDomain),
+			Summary:     "截止：" + opp.Title,
+			Description: description,
+			URL:         opp.SourceURL,
+			Categories:  categories,
+			Start:       *opp.Deadline,
+			AllDay:      true,
+			Stamp:       opp.UpdatedAt,
+			Alarms:      dayAlarms(cfg.DeadlineAlarmDays, opp.Title+" 即将截止"),
+		})
+	}
+	if opp.EventDate != nil {
+		events = append(events, &ical.Event{
+			UID:         fmt.Sprintf("opportunity-%d-event@%s", opp.ID, calendarUIDDomain),
+			Summary:     opp.Title,
+			Description: description,
+			Location:    opp.Location,
+			URL:         opp.SourceURL,
+			Categories:  categories,
+			Start:       *opp.EventDate,
+			AllDay:      true,
+			Stamp:       opp.UpdatedAt,
+			Alarms:      dayAlarms(cfg.EventAlarmDays, opp.Title),
+		})
+	}
+
+	return events
+}
+
+// dayAlarms 将提前天数转换为提醒（全天事件以当天零点为基准）
+func dayAlarms(days []int, description string) []ical.Alarm {
+	alarms := make([]ical.Alarm, 0, len(days))
+	for _, d := range days {
+		if d < 0 {
+			continue
+		}
+		alarms = append(alarms, ical.Alarm{Before: time.Duration(d) * 24 * time.Hour, Description: description})
+	}
+	return alarms
+}
+
+// scheduleEvent 生成日程事件；重复日程输出RRULE，
+// 学期范围外、节假日与单独取消的日期输出为EXDATE
+func scheduleEvent(sch *domain.Schedule, semester *domain.Semester, cfg config.CalendarConfig, loc *time.Location, now time.Time) *ical.Event {
+	event := &ical.Event{
+		UID:         fmt.Sprintf("schedule-%d@%s", sch.ID, calendarUIDDomain),
+		Summary:     sch.Title,
+		Description: sch.Description,
+		Location:    sch.Location,
+		Start:       sch.StartTime,
+		End:         sch.EndTime,
+		Stamp:       sch.UpdatedAt,
+	}
+	if sch.Type != "" {
+		event.Categories = []string{sch.Type}
+	}
+	if cfg.ScheduleAlarmMinutes > 0 {
+		event.Alarms = []ical.Alarm{{Before: time.Duration(cfg.ScheduleAlarmMinutes) * time.Minute}}
+	}
+
+	if !sch.IsRecurring {
+		return event
+	}
+
+	value := sch.RecurrenceRule
+	if value == "" {
+		value = recurrence.FreqWeekly
+	}
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		// 无效规则与展开时一致：只保留首次发生
+		return event
+	}
+
+	// 排除日期：不受限展开与按学期/节假日/例外日期展开的差集
+	horizon := now.Add(openEndedHorizon)
+	if rule.Until != nil {
+		horizon = rule.Until.AddDate(0, 0, 1)
+	}
+	if semester != nil && semester.EndDate.AddDate(0, 0, 1).Before(horizon) {
+		horizon = semester.EndDate.AddDate(0, 0, 1)
+	}
+	kept := make(map[time.Time]bool)
+	for _, start := range rule.Between(sch.StartTime, sch.StartTime, horizon, scheduleBounds(sch, semester)) {
+		kept[start] = true
+	}
+	for _, start := range rule.Between(sch.StartTime, sch.StartTime, horizon, recurrence.Bounds{}) {
+		if !kept[start] {
+			event.ExDates = append(event.ExDates, start)
+		}
+	}
+
+	// 学期结束日期作为UNTIL；仅含日期的UNTIL需转换为UTC时刻，按日历时区取当天最后一秒
+	if rule.Count == 0 {
+		if semester != nil && (rule.Until == nil || semester.EndDate.Before(*rule.Until)) {
+			rule.Until = &semester.EndDate
+		}
+		if until := rule.Until; until != nil && until.Hour() == 0 && until.Minute() == 0 && until.Second() == 0 {
+			end := time.Date(until.Year(), until.Month(), until.Day(), 23, 59, 59, 0, loc).UTC()
+			rule.Until = &end
+		}
+	}
+	event.RRule = rule.String()
+
+	return event
+}
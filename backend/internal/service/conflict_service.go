@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+)
+
+const (
+	// deadlinePrepDays 截止日期窗口覆盖的天数（含截止当天）
+	deadlinePrepDays = 3
+	// prepHorizonDays 建议空闲时间段的搜索范围（目标日期之前的天数）
+	prepHorizonDays = 7
+	// freeDayStartHour/freeDayEndHour 建议空闲时间段的每日可用时段
+	freeDayStartHour = 8
+	freeDayEndHour   = 22
+	// minFreeSlot 建议空闲时间段的最短时长
+	minFreeSlot = time.Hour
+	// maxFreeSlots 返回的建议空闲时间段数量
+	maxFreeSlots = 5
+	// examPeriodType 考试周冲突的类型名称
+	examPeriodType = "考试周"
+)
+
+// 时间窗口权重：活动当天影响最大，截止准备期次之，开始日期最小
+const (
+	eventWindowWeight    = 1.0
+	deadlineWindowWeight = 0.5
+	startWindowWeight    = 0.3
+)
+
+// ConflictService detects time conflicts between opportunities and the user's schedule
+type ConflictService struct {
+	oppRepo      *postgres.OpportunityRepository
+	scheduleRepo *postgres.ScheduleRepository
+	semesterRepo *postgres.SemesterRepository
+}
+
+// NewConflictService creates a new time conflict service
+func NewConflictService(
+	oppRepo *postgres.OpportunityRepository,
+	scheduleRepo *postgres.ScheduleRepository,
+	semesterRepo *postgres.SemesterRepository,
+) *ConflictService {
+	return &ConflictService{
+		oppRepo:      oppRepo,
+		scheduleRepo: scheduleRepo,
+		semesterRepo: semesterRepo,
+	}
+}
+
+// Analyze loads an opportunity and analyzes it against the user's schedule
+func (s *ConflictService) Analyze(ctx context.Context, userID, opportunityID int64) (*domain.TimeConflictReport, error) {
+	opp, err := s.oppRepo.GetByID(ctx, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.AnalyzeOpportunity(ctx, userID, opp)
+}
+
+// AnalyzeOpportunity analyzes an already loaded opportunity against the user's schedule
+func (s *ConflictService) AnalyzeOpportunity(ctx context.Context, userID int64, opp *domain.Opportunity) (*domain.TimeConflictReport, error) {
+	schedules, err := s.scheduleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	semesters, err := s.semesterRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list semesters: %w", err)
+	}
+
+	return analyzeConflicts(opp, schedules, semesters, time.Now()), nil
+}
+
+// analyzeConflicts 计算时间成本、冲突条目与建议空闲时间段
+func analyzeConflicts(opp *domain.Opportunity, schedules []*domain.Schedule, semesters []*domain.Semester, now time.Time) *domain.TimeConflictReport {
+	bySemester := semestersByID(semesters)
+	cost, windows, conflicts := timeConflicts(opp, schedules, bySemester, semesters)
+
+	report := &domain.TimeConflictReport{
+		OpportunityID: opp.ID,
+		TimeCost:      round2(cost),
+		Windows:       windows,
+		Conflicts:     conflicts,
+		FreeSlots:     freeSlots(opp, schedules, bySemester, semesters, now),
+	}
+	if report.Windows == nil {
+		report.Windows = []domain.ConflictWindow{}
+	}
+	if report.Conflicts == nil {
+		report.Conflicts = []domain.ScheduleConflict{}
+	}
+	if report.FreeSlots == nil {
+		report.FreeSlots = []domain.FreeSlot{}
+	}
+
+	return report
+}
+
+// conflictWindows 返回机会的时间窗口（活动当天、开始当天、截止前准备期）
+func conflictWindows(opp *domain.Opportunity) []domain.ConflictWindow {
+	var windows []domain.ConflictWindow
+	if opp.EventDate != nil {
+		day := dayStart(*opp.EventDate)
+		windows = append(windows, domain.ConflictWindow{
+			Kind: domain.ConflictWindowEvent, StartTime: day, EndTime: day.AddDate(0, 0, 1), Weight: eventWindowWeight,
+		})
+	}
+	if opp.Deadline != nil {
+		end := dayStart(*opp.Deadline).AddDate(0, 0, 1)
+		windows = append(windows, domain.ConflictWindow{
+			Kind: domain.ConflictWindowDeadline, StartTime: end.AddDate(0, 0, -deadlinePrepDays), EndTime: end, Weight: deadlineWindowWeight,
+		})
+	}
+	if opp.StartDate != nil {
+		day := dayStart(*opp.StartDate)
+		windows = append(windows, domain.ConflictWindow{
+			Kind: domain.ConflictWindowStart, StartTime: day, EndTime: day.AddDate(0, 0, 1), Weight: startWindowWeight,
+		})
+	}
+	return windows
+}
+
+// timeConflicts 计算归一化时间成本（0-1）及冲突条目
+// 每个窗口的成本为窗口内各天占用比例的平均值：有考试或处于考试周的日子视为占满，
+// 其余按日程小时数 / busyHoursPerDay 计算；总成本为各窗口的加权平均
+func timeConflicts(opp *domain.Opportunity, schedules []*domain.Schedule, bySemester map[int64]*domain.Semester, semesters []*domain.Semester) (float64, []domain.ConflictWindow, []domain.ScheduleConflict) {
+	windows := conflictWindows(opp)
+	if len(windows) == 0 || (len(schedules) == 0 && !hasExamPeriods(semesters)) {
+		return 0, windows, nil
+	}
+
+	var conflicts []domain.ScheduleConflict
+	var cost, weights float64
+	for i := range windows {
+		w := &windows[i]
+		occurrences := expandSchedules(schedules, bySemester, w.StartTime, w.EndTime)
+
+		for _, occ := range occurrences {
+			conflicts = append(conflicts, domain.ScheduleConflict{
+				Window:     w.Kind,
+				ScheduleID: occ.ScheduleID,
+				Title:      occ.Title,
+				Type:       occ.Type,
+				StartTime:  occ.StartTime,
+				EndTime:    occ.EndTime,
+			})
+		}
+		conflicts = append(conflicts, examPeriodConflicts(w, semesters)...)
+
+		var total float64
+		days := 0
+		for day := w.StartTime; day.Before(w.EndTime); day = day.AddDate(0, 0, 1) {
+			total += dayCost(day, occurrences, semesters)
+			days++
+		}
+		if days > 0 {
+			w.Cost = round2(total / float64(days))
+		}
+
+		cost += w.Weight * w.Cost
+		weights += w.Weight
+	}
+
+	return cost / weights, windows, conflicts
+}
+
+// dayCost 计算某天的占用比例（0-1）
+func dayCost(day time.Time, occurrences []*domain.ScheduleOccurrence, semesters []*domain.Semester) float64 {
+	if inExamPeriod(day, semesters) {
+		return 1
+	}
+
+	next := day.AddDate(0, 0, 1)
+	var hours float64
+	for _, occ := range occurrences {
+		start, end := occ.StartTime, occ.EndTime
+		if start.Before(day) {
+			start = day
+		}
+		if end.After(next) {
+			end = next
+		}
+		if !end.After(start) {
+			continue
+		}
+		if occ.Type == domain.ScheduleTypeExam {
+			return 1
+		}
+		hours += end.Sub(start).Hours()
+	}
+
+	return math.Min(1, hours/busyHoursPerDay)
+}
+
+// examPeriodConflicts 返回与窗口重叠的考试周（截取到窗口范围内）
+func examPeriodConflicts(w *domain.ConflictWindow, semesters []*domain.Semester) []domain.ScheduleConflict {
+	var conflicts []domain.ScheduleConflict
+	for _, semester := range semesters {
+		for _, period := range semester.ExamPeriods {
+			start := dayStart(period.StartDate)
+			end := dayStart(period.EndDate).AddDate(0, 0, 1)
+			if !start.Before(w.EndTime) || !end.After(w.StartTime) {
+				continue
+			}
+			if start.Before(w.StartTime) {
+				start = w.StartTime
+			}
+			if end.After(w.EndTime) {
+				end = w.EndTime
+			}
+			conflicts = append(conflicts, domain.ScheduleConflict{
+				Window:    w.Kind,
+				Title:     period.Name,
+				Type:      examPeriodType,
+				StartTime: start,
+				EndTime:   end,
+			})
+		}
+	}
+	return conflicts
+}
+
+// freeSlots 在目标日期（活动日或截止日中较早的未来日期）之前的若干天内，
+// 找出每日可用时段中未被日程占用、且不在考试周内的空闲时间段
+func freeSlots(opp *domain.Opportunity, schedules []*domain.Schedule, bySemester map[int64]*domain.Semester, semesters []*domain.Semester, now time.Time) []domain.FreeSlot {
+	today := dayStart(now)
+
+	var target *time.Time
+	for _, d := range []*time.Time{opp.Deadline, opp.EventDate} {
+		if d == nil || dayStart(*d).Before(today) {
+			continue
+		}
+		if target == nil || d.Before(*target) {
+			target = d
+		}
+	}
+	if target == nil {
+		return nil
+	}
+
+	// 目标日期当天也可用于准备
+	end := dayStart(*target).AddDate(0, 0, 1)
+	start := end.AddDate(0, 0, -prepHorizonDays)
+	if start.Before(today) {
+		start = today
+	}
+
+	occurrences := expandSchedules(schedules, bySemester, start, end)
+
+	var slots []domain.FreeSlot
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if inExamPeriod(day, semesters) {
+			continue
+		}
+
+		cursor := day.Add(freeDayStartHour * time.Hour)
+		dayEnd := day.Add(freeDayEndHour * time.Hour)
+		if cursor.Before(now) {
+			cursor = now
+		}
+
+		for _, occ := range occurrences {
+			if !occ.EndTime.After(cursor) || !occ.StartTime.Before(dayEnd) {
+				continue
+			}
+			if occ.StartTime.Sub(cursor) >= minFreeSlot {
+				slots = append(slots, newFreeSlot(cursor, occ.StartTime))
+			}
+			if occ.EndTime.After(cursor) {
+				cursor = occ.EndTime
+			}
+		}
+		if dayEnd.Sub(cursor) >= minFreeSlot {
+			slots = append(slots, newFreeSlot(cursor, dayEnd))
+		}
+	}
+
+	// 优先较长的时间段，再按时间顺序返回
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Hours > slots[j].Hours
+	})
+	if len(slots) > maxFreeSlots {
+		slots = slots[:maxFreeSlots]
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].StartTime.Before(slots[j].StartTime)
+	})
+
+	return slots
+}
+
+func newFreeSlot(start, end time.Time) domain.FreeSlot {
+	return domain.FreeSlot{StartTime: start, EndTime: end, Hours: round2(end.Sub(start).Hours())}
+}
+
+// inExamPeriod 判断某天是否处于任一学期的考试周
+func inExamPeriod(day time.Time, semesters []*domain.Semester) bool {
+	for _, semester := range semesters {
+		if semester.ExamPeriods.Contains(day) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasExamPeriods(semesters []*domain.Semester) bool {
+	for _, semester := range semesters {
+		if len(semester.ExamPeriods) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// semestersByID indexes semesters by ID for recurrence bounds
+func semestersByID(semesters []*domain.Semester) map[int64]*domain.Semester {
+	result := make(map[int64]*domain.Semester, len(semesters))
+	for _, semester := range semesters {
+		result[semester.ID] = semester
+	}
+	return result
+}
+
+// dayStart truncates t to midnight in its own location
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
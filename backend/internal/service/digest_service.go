@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/digest"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/mail"
+	"github.com/unifocus/backend/internal/notify"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// digestLockKey 多实例部署时保证同一时刻只有一个实例在发送摘要
+const digestLockKey = "digest:scan"
+
+// ErrInvalidUnsubscribeToken indicates an unsubscribe link was altered or is malformed
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+// digestStatusLabels 摘要中显示的机会状态
+var digestStatusLabels = map[string]string{
+	domain.UserOpportunityStatusSaved:   "已收藏",
+	domain.UserOpportunityStatusApplied: "已报名",
+}
+
+// DigestService builds and sends the daily/weekly digest emails
+type DigestService struct {
+	cfg              config.DigestConfig
+	loc              *time.Location
+	secret           []byte // 退订链接签名密钥
+	digestRepo       *postgres.DigestRepository
+	uoRepo           *postgres.UserOpportunityRepository
+	notificationRepo *postgres.NotificationRepository
+	recService       *RecommendationService
+	dispatcher       *notify.Dispatcher // 推送新机会匹配通知
+	sender           mail.Sender
+	locker           *redis.Client
+}
+
+// NewDigestService creates a new digest service
+// secret signs unsubscribe links; in dry-run mode sender should write to disk
+func NewDigestService(
+	cfg config.DigestConfig,
+	loc *time.Location,
+	secret string,
+	digestRepo *postgres.DigestRepository,
+	uoRepo *postgres.UserOpportunityRepository,
+	notificationRepo *postgres.NotificationRepository,
+	recService *RecommendationService,
+	dispatcher *notify.Dispatcher,
+	sender mail.Sender,
+	locker *redis.Client,
+) *DigestService {
+	return &DigestService{
+		cfg:              cfg,
+		loc:              loc,
+		secret:           []byte(secret),
+		digestRepo:       digestRepo,
+		uoRepo:           uoRepo,
+		notificationRepo: notificationRepo,
+		recService:       recService,
+		dispatcher:       dispatcher,
+		sender:           sender,
+		locker:           locker,
+	}
+}
+
+// GetPreference returns the user's effective digest frequency
+func (s *DigestService) GetPreference(ctx context.Context, userID int64) (*domain.DigestPreference, error) {
+	recipient, err := s.digestRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.preference(recipient), nil
+}
+
+// UpdatePreference changes the user's digest frequency
+func (s *DigestService) UpdatePreference(ctx context.Context, userID int64, req *domain.UpdateDigestPreferenceRequest) (*domain.DigestPreference, error) {
+	if err := s.digestRepo.SetFrequency(ctx, userID, req.Frequency); err != nil {
+		if err.Error() == "user not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update digest preference: %w", err)
+	}
+	return s.GetPreference(ctx, userID)
+}
+
+// Unsubscribe turns the digest off for the user identified by a signed unsubscribe token
+func (s *DigestService) Unsubscribe(ctx context.Context, token string) error {
+	userID, err := s.verifyUnsubscribeToken(token)
+	if err != nil {
+		return err
+	}
+	if err := s.digestRepo.SetFrequency(ctx, userID, domain.DigestFrequencyOff); err != nil {
+		if err.Error() == "user not found" {
+			return ErrInvalidUnsubscribeToken
+		}
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	return nil
+}
+
+// Start sends due digests immediately and then on every interval until ctx is cancelled
+func (s *DigestService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.GetInterval())
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Errorf("Digest run failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends the digests due at now. last_sent_at is claimed before sending so
+// concurrent runs never send the same digest twice; it is restored when sending fails.
+// Users with nothing new are marked as sent without an email. The new matches of
+// each digest are also pushed as new_match notifications.
+func (s *DigestService) RunOnce(ctx context.Context, now time.Time) error {
+	locked, err := s.locker.Lock(ctx, digestLockKey, s.cfg.GetInterval())
+	if err != nil {
+		return fmt.Errorf("failed to acquire digest lock: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		if err := s.locker.Unlock(context.Background(), digestLockKey); err != nil {
+			logger.Warnf("failed to release digest lock: %v", err)
+		}
+	}()
+
+	daily, weekly := digestCutoffs(now.In(s.loc), s.cfg.SendHour, s.cfg.WeeklyDay)
+	recipients, err := s.digestRepo.ListDue(ctx, s.cfg.DefaultFrequency, daily.UTC(), weekly.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to list digest recipients: %w", err)
+	}
+
+	sent := 0
+	for _, recipient := range recipients {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		ok, err := s.send(ctx, recipient, now)
+		if err != nil {
+			logger.Warnf("failed to send digest to user %d: %v", recipient.User.ID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		logger.Infof("Sent %d digest emails", sent)
+	}
+	return nil
+}
+
+// send 领取并发送一个用户的摘要，返回是否实际发送了邮件
+func (s *DigestService) send(ctx context.Context, recipient *domain.DigestRecipient, now time.Time) (bool, error) {
+	claimed, err := s.digestRepo.Claim(ctx, recipient.User.ID, recipient.LastSentAt, now.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	restore := func(cause error) (bool, error) {
+		if err := s.digestRepo.Restore(context.Background(), recipient.User.ID, recipient.LastSentAt); err != nil {
+			logger.Errorf("failed to restore digest state of user %d: %v", recipient.User.ID, err)
+		}
+		return false, cause
+	}
+
+	data, err := s.Build(ctx, recipient, now)
+	if err != nil {
+		return restore(err)
+	}
+
+	sent := false
+	if !data.Empty() && recipient.User.Email != "" {
+		msg, err := s.message(recipient, data)
+		if err != nil {
+			return restore(err)
+		}
+		if err := s.sender.Send(ctx, msg); err != nil {
+			return restore(err)
+		}
+		sent = true
+	}
+
+	// 摘要已领取且不会重发，此时推送不会重复
+	s.notifyMatches(ctx, recipient.User, data.Matches)
+	return sent, nil
+}
+
+// notifyMatches 将摘要中的新机会逐条推送为 new_match 通知，渠道由用户偏好决定
+func (s *DigestService) notifyMatches(ctx context.Context, user *domain.User, matches []digest.Match) {
+	for _, match := range matches {
+		oppID := match.OpportunityID
+		body := fmt.Sprintf("匹配度%d分", match.Score)
+		if match.Organizer != "" {
+			body = fmt.Sprintf("%s发布，匹配度%d分", match.Organizer, match.Score)
+		}
+		if match.Deadline != "" {
+			body += "，截止日期：" + match.Deadline
+		}
+		n := &domain.Notification{
+			UserID:        user.ID,
+			Type:          domain.NotificationTypeNewMatch,
+			Title:         fmt.Sprintf("新机会「%s」与你匹配", match.Title),
+			Body:          body + "。",
+			URL:           match.URL,
+			OpportunityID: &oppID,
+			Data: domain.JSONB{
+				"score": match.Score,
+			},
+		}
+		if err := s.dispatcher.Notify(ctx, user, n); err != nil {
+			logger.Warnf("failed to notify user %d of matching opportunity %d: %v", user.ID, oppID, err)
+		}
+	}
+}
+
+// Build collects the digest content for a user: opportunities published since the
+// last digest that match the profile, saved items closing soon, and changes to
+// followed opportunities since the last digest
+func (s *DigestService) Build(ctx context.Context, recipient *domain.DigestRecipient, now time.Time) (*digest.Data, error) {
+	frequency := s.preference(recipient).Frequency
+	since := now.AddDate(0, 0, -7)
+	period := "每周"
+	if frequency == domain.DigestFrequencyDaily {
+		since = now.AddDate(0, 0, -1)
+		period = "每日"
+	}
+	if recipient.LastSentAt != nil {
+		since = *recipient.LastSentAt
+	}
+
+	userID := recipient.User.ID
+	data := &digest.Data{
+		UserName:       recipient.User.Username,
+		Period:         period,
+		Since:          since.In(s.loc).Format("2006-01-02 15:04"),
+		UnsubscribeURL: s.unsubscribeURL(userID),
+	}
+	if s.cfg.BaseURL != "" {
+		data.DashboardURL = s.cfg.BaseURL + "/dashboard"
+	}
+
+	matches, err := s.recService.NewMatches(ctx, userID, since, s.cfg.MinScore, s.cfg.MaxItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find new matches: %w", err)
+	}
+	for _, item := range matches {
+		match := digest.Match{
+			OpportunityID: item.Opportunity.ID,
+			Title:         item.Opportunity.Title,
+			Organizer:     item.Opportunity.Organizer,
+			Score:         int(math.Round(item.Score)),
+			URL:           item.Opportunity.SourceURL,
+		}
+		if item.Opportunity.Deadline != nil {
+			match.Deadline = item.Opportunity.Deadline.Format("2006-01-02")
+		}
+		data.Matches = append(data.Matches, match)
+	}
+
+	statuses := []string{domain.UserOpportunityStatusSaved, domain.UserOpportunityStatusApplied}
+	followed, err := s.uoRepo.ListByStatuses(ctx, userID, statuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved opportunities: %w", err)
+	}
+	y, m, d := now.In(s.loc).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for _, item := range followed {
+		if item.Opportunity.Deadline == nil || !item.Opportunity.IsActive {
+			continue
+		}
+		daysLeft := daysBetween(today, *item.Opportunity.Deadline)
+		if daysLeft < 0 || daysLeft > s.cfg.DeadlineDays {
+			continue
+		}
+		data.Deadlines = append(data.Deadlines, digest.Deadline{
+			Title:    item.Opportunity.Title,
+			Deadline: item.Opportunity.Deadline.Format("2006-01-02"),
+			DaysLeft: daysLeft,
+			Status:   digestStatusLabels[item.Status],
+			URL:      item.Opportunity.SourceURL,
+		})
+	}
+	sort.SliceStable(data.Deadlines, func(i, j int) bool {
+		return data.Deadlines[i].DaysLeft < data.Deadlines[j].DaysLeft
+	})
+	if len(data.Deadlines) > s.cfg.MaxItems {
+		data.Deadlines = data.Deadlines[:s.cfg.MaxItems]
+	}
+
+	changes, err := s.notificationRepo.ListSince(ctx, userID, domain.NotificationTypeStatusChange, since, s.cfg.MaxItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list opportunity changes: %w", err)
+	}
+	for _, n := range changes {
+		data.Changes = append(data.Changes, digest.Change{
+			Title: n.Title,
+			Body:  n.Body,
+			Time:  n.CreatedAt.Format("01-02 15:04"),
+			URL:   n.URL,
+		})
+	}
+
+	return data, nil
+}
+
+// message 渲染摘要邮件，附带一键退订头（RFC 8058）
+func (s *DigestService) message(recipient *domain.DigestRecipient, data *digest.Data) (*mail.Message, error) {
+	text, html, err := digest.Render(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mail.Message{
+		To:      []string{recipient.User.Email},
+		Subject: data.Subject(),
+		Text:    text,
+		HTML:    html,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + data.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// preference 计算用户的生效频率
+func (s *DigestService) preference(recipient *domain.DigestRecipient) *domain.DigestPreference {
+	pref := &domain.DigestPreference{
+		UserID:     recipient.User.ID,
+		Frequency:  recipient.Frequency,
+		LastSentAt: recipient.LastSentAt,
+	}
+	if pref.Frequency == "" {
+		pref.Frequency = s.cfg.DefaultFrequency
+		pref.IsDefault = true
+	}
+	return pref
+}
+
+// unsubscribeURL 构造带签名令牌的退订链接
+func (s *DigestService) unsubscribeURL(userID int64) string {
+	return s.cfg.BaseURL + "/api/v1/digest/unsubscribe?token=" + url.QueryEscape(s.unsubscribeToken(userID))
+}
+
+// unsubscribeToken 生成 <用户ID>.<签名> 格式的退订令牌，无需存储且不会过期
+func (s *DigestService) unsubscribeToken(userID int64) string {
+	id := strconv.FormatInt(userID, 10)
+	return id + "." + base64.RawURLEncoding.EncodeToString(s.unsubscribeSignature(id))
+}
+
+// verifyUnsubscribeToken 校验退订令牌并返回用户ID
+func (s *DigestService) verifyUnsubscribeToken(token string) (int64, error) {
+	id, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, s.unsubscribeSignature(id)) {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	return userID, nil
+}
+
+// unsubscribeSignature 退订令牌签名（截取前16字节）
+func (s *DigestService) unsubscribeSignature(id string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("digest-unsubscribe:" + id))
+	return mac.Sum(nil)[:16]
+}
+
+// digestCutoffs 返回最近一次已到达的每日与每周发送时刻（now所在时区）
+// 上次发送早于对应时刻的用户即为待发送
+func digestCutoffs(now time.Time, sendHour, weeklyDay int) (time.Time, time.Time) {
+	y, m, d := now.Date()
+	daily := time.Date(y, m, d, sendHour, 0, 0, 0, now.Location())
+	if daily.After(now) {
+		daily = daily.AddDate(0, 0, -1)
+	}
+
+	// weeklyDay: 1-7 表示周一至周日
+	weekday := int(daily.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	weekly := daily.AddDate(0, 0, -((weekday - weeklyDay + 7) % 7))
+
+	return daily, weekly
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/taxonomy"
+)
+
+// certThresholdPattern 匹配带分数线的证书要求，如 "CET-6>=425"、"雅思：6.5分"、"CET-6 425分"、"雅思 6.5"
+// 整数分数须带比较符或"分"字，避免把"CET 6"中的6当作分数线；带小数的分数可省略单位
+var certThresholdPattern = regexp.MustCompile(`^(.+?)(?:\s*(?:>=|≥|>|:|：)\s*(\d+(?:\.\d+)?)\s*分?|\s*(\d+(?:\.\d+)?)\s*分|\s*(\d+\.\d+))$`)
+
+// GapService diagnoses the gap between a user's profile and opportunity requirements
+type GapService struct {
+	userRepo    *postgres.UserRepository
+	profileRepo *postgres.ProfileRepository
+	oppRepo     *postgres.OpportunityRepository
+	ruleRepo    *postgres.CompetitionRuleRepository
+	taxonomy    *TaxonomyService // 技能/专业规范化，可为nil
+}
+
+// NewGapService creates a new skill gap service
+func NewGapService(
+	userRepo *postgres.UserRepository,
+	profileRepo *postgres.ProfileRepository,
+	oppRepo *postgres.OpportunityRepository,
+	ruleRepo *postgres.CompetitionRuleRepository,
+	taxonomy *TaxonomyService,
+) *GapService {
+	return &GapService{
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+		oppRepo:     oppRepo,
+		ruleRepo:    ruleRepo,
+		taxonomy:    taxonomy,
+	}
+}
+
+// Diagnose compares the user's profile with the requirements of one opportunity
+func (s *GapService) Diagnose(ctx context.Context, userID, opportunityID int64) (*domain.SkillGapReport, error) {
+	user, profile, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opp, err := s.oppRepo.GetByID(ctx, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.ruleRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list competition rules: %w", err)
+	}
+
+	return diagnoseGap(user, profile, opp, matchCompetitionRule(opp, rules), s.taxonomy.Index()), nil
+}
+
+// TopSkillGaps aggregates missing skills over the user's candidate opportunities and
+// returns the skills that would unlock the most opportunities
+func (s *GapService) TopSkillGaps(ctx context.Context, userID int64, limit int) ([]*domain.SkillDemand, error) {
+	user, profile, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := listCandidates(ctx, s.oppRepo, user)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.ruleRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list competition rules: %w", err)
+	}
+
+	demand := make(map[string]*domain.SkillDemand)
+	for _, opp := range candidates {
+		report := diagnoseGap(user, profile, opp, matchCompetitionRule(opp, rules), s.taxonomy.Index())
+		// 年级/专业不符的机会无法通过补技能解锁
+		if len(report.UnmetConstraints) > 0 {
+			continue
+		}
+
+		for _, skill := range report.MissingSkills {
+			d, ok := demand[skill]
+			if !ok {
+				d = &domain.SkillDemand{Skill: skill}
+				demand[skill] = d
+			}
+			d.Opportunities++
+			if len(report.MissingSkills) == 1 {
+				d.Unlocks++
+			}
+		}
+	}
+
+	result := make([]*domain.SkillDemand, 0, len(demand))
+	for _, d := range demand {
+		result = append(result, d)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Unlocks != result[j].Unlocks {
+			return result[i].Unlocks > result[j].Unlocks
+		}
+		if result[i].Opportunities != result[j].Opportunities {
+			return result[i].Opportunities > result[j].Opportunities
+		}
+		return result[i].Skill < result[j].Skill
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// loadUser loads the user and profile, treating a missing profile as empty
+func (s *GapService) loadUser(ctx context.Context, userID int64) (*domain.User, *domain.UserProfile, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if err.Error() != "profile not found" {
+			return nil, nil, fmt.Errorf("failed to get profile: %w", err)
+		}
+		profile = &domain.UserProfile{UserID: userID}
+	}
+
+	return user, profile, nil
+}
+
+// diagnoseGap 对比用户画像与机会要求（含匹配到的竞赛规则技能要求）
+func diagnoseGap(user *domain.User, profile *domain.UserProfile, opp *domain.Opportunity, rule *domain.CompetitionLevelRule, idx *taxonomy.Index) *domain.SkillGapReport {
+	report := &domain.SkillGapReport{
+		OpportunityID:     opp.ID,
+		RequiredSkills:    []string{},
+		MatchedSkills:     []string{},
+		MissingSkills:     []string{},
+		UnmetCertificates: []domain.CertificateGap{},
+		UnmetConstraints:  []domain.ConstraintGap{},
+	}
+
+	required := append([]string{}, opp.Requirements.Skills...)
+	if rule != nil {
+		report.MatchedRule = rule.CompetitionName
+		required = append(required, rule.SkillRequirements...)
+	}
+
+	// 技能缺口（去重，按规范名称展示）
+	required = idx.NormalizeAll(taxonomy.KindSkill, required)
+	owned := termSet(idx.NormalizeAll(taxonomy.KindSkill, profile.Skills))
+	seen := make(map[string]bool)
+	for _, skill := range required {
+		key := normalizeTerm(skill)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		report.RequiredSkills = append(report.RequiredSkills, skill)
+		if owned[key] {
+			report.MatchedSkills = append(report.MatchedSkills, skill)
+		} else {
+			report.MissingSkills = append(report.MissingSkills, skill)
+		}
+	}
+
+	// 证书及分数线
+	for _, requirement := range opp.Requirements.Certificates {
+		if gap, unmet := certificateGap(requirement, profile.Certificates); unmet {
+			report.UnmetCertificates = append(report.UnmetCertificates, gap)
+		}
+	}
+
+	// 年级约束
+	if grades := opp.Requirements.Grade; len(grades) > 0 && !containsInt(grades, user.Grade) {
+		required := make([]string, len(grades))
+		for i, g := range grades {
+			required[i] = strconv.Itoa(g)
+		}
+		report.UnmetConstraints = append(report.UnmetConstraints, domain.ConstraintGap{
+			Type:     "grade",
+			Required: required,
+			Current:  strconv.Itoa(user.Grade),
+		})
+	}
+
+	// 专业约束
+	if majors := opp.Requirements.Major; len(majors) > 0 && majorMatchScore(user.Major, majors, idx) == 0 {
+		report.UnmetConstraints = append(report.UnmetConstraints, domain.ConstraintGap{
+			Type:     "major",
+			Required: majors,
+			Current:  user.Major,
+		})
+	}
+
+	report.Eligible = len(report.UnmetCertificates) == 0 && len(report.UnmetConstraints) == 0
+	return report
+}
+
+// certificateGap 检查单项证书要求，返回缺口以及是否未满足
+func certificateGap(requirement string, certs []domain.Cert) (domain.CertificateGap, bool) {
+	name, threshold := parseCertRequirement(requirement)
+	gap := domain.CertificateGap{Name: name, RequiredScore: threshold}
+
+	// 按名称精确匹配（忽略大小写与空白），"PMP"不满足"PMP-ACP"
+	key := taxonomy.Key(name)
+	for _, cert := range certs {
+		if c := taxonomy.Key(cert.Name); c == "" || c != key {
+			continue
+		}
+
+		gap.Owned = true
+		if cert.Score > gap.CurrentScore {
+			gap.CurrentScore = cert.Score
+		}
+	}
+
+	unmet := !gap.Owned || (threshold > 0 && gap.CurrentScore < threshold)
+	return gap, unmet
+}
+
+// parseCertRequirement 拆分证书名称与分数线，无分数线时threshold为0
+func parseCertRequirement(requirement string) (string, float64) {
+	requirement = strings.TrimSpace(requirement)
+	m := certThresholdPattern.FindStringSubmatch(requirement)
+	if m == nil {
+		return requirement, 0
+	}
+
+	digits := m[2] + m[3] + m[4]
+	score, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return requirement, 0
+	}
+	return strings.TrimSpace(m[1]), score
+}
+
+// matchCompetitionRule 为机会匹配竞赛认定规则
+// 依次按竞赛全称/简称、关键词、主办方模式、URL模式匹配，返回第一条命中的规则
+func matchCompetitionRule(opp *domain.Opportunity, rules []*domain.CompetitionLevelRule) *domain.CompetitionLevelRule {
+	title := normalizeTerm(opp.Title)
+	text := normalizeTerm(opp.Title + " " + opp.Description)
+
+	for _, rule := range rules {
+		if name := normalizeTerm(rule.CompetitionName); name != "" && strings.Contains(title, name) {
+			return rule
+		}
+		if short := normalizeTerm(rule.ShortName); short != "" && strings.Contains(title, short) {
+			return rule
+		}
+	}
+
+	for _, rule := range rules {
+		for _, keyword := range rule.Keywords {
+			if k := normalizeTerm(keyword); k != "" && strings.Contains(text, k) {
+				return rule
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		for _, pattern := range rule.OrganizerPatterns {
+			if opp.Organizer != "" && likeMatch(pattern, opp.Organizer) {
+				return rule
+			}
+		}
+		for _, pattern := range rule.URLPatterns {
+			if opp.SourceURL != "" && likeMatch(pattern, opp.SourceURL) {
+				return rule
+			}
+		}
+	}
+
+	return nil
+}
+
+// likeMatch 实现SQL LIKE语义的简单匹配（仅支持%通配符）
+func likeMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "%")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(s, part)
+		if idx < 0 {
+			return false
+		}
+		s = s[idx+len(part):]
+	}
+
+	return strings.HasSuffix(s, last)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/notify"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// maxReplay 实时连接重连时最多补发的通知数
+const maxReplay = 100
+
+// ErrInvalidNotificationPreferences indicates a notification preferences update is invalid
+var ErrInvalidNotificationPreferences = errors.New("invalid notification preferences")
+
+// NotificationService handles the in-app notification center
+type NotificationService struct {
+	notificationRepo *postgres.NotificationRepository
+	uoRepo           *postgres.UserOpportunityRepository
+	profileRepo      *postgres.ProfileRepository
+	userRepo         *postgres.UserRepository
+	dispatcher       *notify.Dispatcher
+	broker           *notify.Broker
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(
+	notificationRepo *postgres.NotificationRepository,
+	uoRepo *postgres.UserOpportunityRepository,
+	profileRepo *postgres.ProfileRepository,
+	userRepo *postgres.UserRepository,
+	dispatcher *notify.Dispatcher,
+	broker *notify.Broker,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		uoRepo:           uoRepo,
+		profileRepo:      profileRepo,
+		userRepo:         userRepo,
+		dispatcher:       dispatcher,
+		broker:           broker,
+	}
+}
+
+// List retrieves the user's notifications, newest first
+func (s *NotificationService) List(ctx context.Context, userID int64, filter *domain.NotificationFilter) ([]*domain.Notification, int64, error) {
+	notifications, total, err := s.notificationRepo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notifications, total, nil
+}
+
+// UnreadCount returns the number of unread notifications
+func (s *NotificationService) UnreadCount(ctx context.Context, userID int64) (int64, error) {
+	count, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks a notification as read
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id int64) (*domain.Notification, error) {
+	return s.notificationRepo.MarkRead(ctx, userID, id)
+}
+
+// MarkAllRead marks all notifications as read and returns how many changed
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	updated, err := s.notificationRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return updated, nil
+}
+
+// Subscribe registers a live connection; the returned function must be called when it closes
+func (s *NotificationService) Subscribe(userID int64) (<-chan *domain.Notification, func()) {
+	return s.broker.Subscribe(userID)
+}
+
+// Missed returns the notifications created after lastID, for a reconnecting live connection
+func (s *NotificationService) Missed(ctx context.Context, userID, lastID int64) ([]*domain.Notification, error) {
+	notifications, err := s.notificationRepo.ListAfter(ctx, userID, lastID, maxReplay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list missed notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// OpportunityChanged notifies the users who saved or applied for an opportunity
+// that it was modified or taken down. changes describes what changed, e.g. "截止日期".
+func (s *NotificationService) OpportunityChanged(ctx context.Context, opp *domain.Opportunity, removed bool, changes []string) {
+	if !removed && len(changes) == 0 {
+		return
+	}
+
+	userIDs, err := s.uoRepo.FollowerIDs(ctx, opp.ID)
+	if err != nil {
+		logger.Warnf("failed to list followers of opportunity %d: %v", opp.ID, err)
+		return
+	}
+
+	title := fmt.Sprintf("「%s」信息有更新", opp.Title)
+	body := fmt.Sprintf("%s已变更，请查看最新信息。", strings.Join(changes, "、"))
+	if removed {
+		title = fmt.Sprintf("「%s」已下架", opp.Title)
+		body = "该机会已被下架，可能已取消或不再接受报名。"
+	}
+
+	for _, userID := range userIDs {
+		oppID := opp.ID
+		n := &domain.Notification{
+			Type:          domain.NotificationTypeStatusChange,
+			Title:         title,
+			Body:          body,
+			URL:           opp.SourceURL,
+			OpportunityID: &oppID,
+			Data: domain.JSONB{
+				"removed": removed,
+				"changes": changes,
+			},
+		}
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			logger.Warnf("failed to load user %d for opportunity %d change: %v", userID, opp.ID, err)
+			continue
+		}
+		if err := s.dispatcher.Notify(ctx, user, n); err != nil {
+			logger.Warnf("failed to notify user %d of opportunity %d change: %v", userID, opp.ID, err)
+		}
+	}
+}
+
+// GetPreferences returns the user's notification preferences with system defaults filled in
+func (s *NotificationService) GetPreferences(ctx context.Context, userID int64) (*domain.NotificationPreferences, error) {
+	stored, err := s.profileRepo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return s.dispatcher.Resolve(stored), nil
+}
+
+// UpdatePreferences validates and stores the user's notification preferences
+// Types left out of the request keep the system defaults
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID int64, prefs *domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	if err := s.validatePreferences(prefs); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.UpdateNotificationPreferences(ctx, userID, prefs); err != nil {
+		if err.Error() == "user not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+	return s.dispatcher.Resolve(prefs), nil
+}
+
+// validatePreferences 校验通知偏好，并对渠道与提醒档位去重
+func (s *NotificationService) validatePreferences(prefs *domain.NotificationPreferences) error {
+	for typ, pref := range prefs.Types {
+		if !containsString(domain.NotificationTypes, typ) {
+			return fmt.Errorf("%w: unknown notification type %q", ErrInvalidNotificationPreferences, typ)
+		}
+		if pref == nil {
+			return fmt.Errorf("%w: missing settings for %q", ErrInvalidNotificationPreferences, typ)
+		}
+
+		channels := make([]string, 0, len(pref.Channels))
+		for _, channel := range pref.Channels {
+			if !s.dispatcher.Available(channel) {
+				return fmt.Errorf("%w: channel %q is not available", ErrInvalidNotificationPreferences, channel)
+			}
+			if !containsString(channels, channel) {
+				channels = append(channels, channel)
+			}
+		}
+		pref.Channels = channels
+	}
+
+	offsets := make([]int, 0, len(prefs.ReminderOffsetDays))
+	for _, days := range prefs.ReminderOffsetDays {
+		if days < 0 || days > domain.MaxReminderOffsetDays {
+			return fmt.Errorf("%w: reminder_offset_days must be between 0 and %d", ErrInvalidNotificationPreferences, domain.MaxReminderOffsetDays)
+		}
+		if !containsInt(offsets, days) {
+			offsets = append(offsets, days)
+		}
+	}
+	sort.Ints(offsets)
+	prefs.ReminderOffsetDays = offsets
+
+	q := prefs.QuietHours
+	if q.Timezone != "" {
+		if _, err := time.LoadLocation(q.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %q", ErrInvalidNotificationPreferences, q.Timezone)
+		}
+	}
+	if q.Enabled || q.Start != "" || q.End != "" {
+		start, err := notify.ParseClock(q.Start)
+		if err != nil {
+			return fmt.Errorf("%w: quiet_hours.start: %v", ErrInvalidNotificationPreferences, err)
+		}
+		end, err := notify.ParseClock(q.End)
+		if err != nil {
+			return fmt.Errorf("%w: quiet_hours.end: %v", ErrInvalidNotificationPreferences, err)
+		}
+		if start == end {
+			return fmt.Errorf("%w: quiet_hours start and end must differ", ErrInvalidNotificationPreferences)
+		}
+	}
+
+	return nil
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// opportunityChanges 比较机会更新前后对用户有影响的字段
+func opportunityChanges(before, after *domain.Opportunity) []string {
+	var changes []string
+	if !sameDate(before.Deadline, after.Deadline) {
+		changes = append(changes, "截止日期")
+	}
+	if !sameDate(before.StartDate, after.StartDate) {
+		changes = append(changes, "开始日期")
+	}
+	if !sameDate(before.EventDate, after.EventDate) {
+		changes = append(changes, "活动日期")
+	}
+	if before.Location != after.Location {
+		changes = append(changes, "地点")
+	}
+	return changes
+}
+
+// sameDate 比较两个可空日期
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
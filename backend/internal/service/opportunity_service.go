@@ -2,27 +2,39 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/unifocus/backend/internal/domain"
 	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/pkg/logger"
 )
 
+// ErrForbidden indicates the user's role does not allow the operation
+var ErrForbidden = errors.New("permission denied")
+
 // OpportunityService handles opportunity business logic
 type OpportunityService struct {
-	oppRepo *postgres.OpportunityRepository
+	oppRepo       *postgres.OpportunityRepository
+	nlpClient     NLPClient            // 用于生成描述向量，可为nil
+	taxonomy      *TaxonomyService     // 技能/专业名称规范化，可为nil
+	notifications *NotificationService // 通知收藏者机会变更，可为nil
 }
 
 // NewOpportunityService creates a new opportunity service
-func NewOpportunityService(oppRepo *postgres.OpportunityRepository) *OpportunityService {
+func NewOpportunityService(oppRepo *postgres.OpportunityRepository, nlpClient NLPClient, taxonomy *TaxonomyService, notifications *NotificationService) *OpportunityService {
 	return &OpportunityService{
-		oppRepo: oppRepo,
+		oppRepo:       oppRepo,
+		nlpClient:     nlpClient,
+		taxonomy:      taxonomy,
+		notifications: notifications,
 	}
 }
 
-// Create creates a new opportunity
-func (s *OpportunityService) Create(ctx context.Context, req *domain.CreateOpportunityRequest) (*domain.Opportunity, error) {
+// Create creates a new opportunity published by actor
+func (s *OpportunityService) Create(ctx context.Context, actor *domain.User, req *domain.CreateOpportunityRequest) (*domain.Opportunity, error) {
 	opp := &domain.Opportunity{
+		CreatedBy:    &actor.ID,
 		Title:        req.Title,
 		Type:         req.Type,
 		Description:  req.Description,
@@ -39,6 +51,9 @@ func (s *OpportunityService) Create(ctx context.Context, req *domain.CreateOppor
 		IsActive:     true,
 	}
 
+	s.normalize(opp)
+	s.vectorize(ctx, opp)
+
 	if err := s.oppRepo.Create(ctx, opp); err != nil {
 		return nil, fmt.Errorf("failed to create opportunity: %w", err)
 	}
@@ -78,13 +93,18 @@ func (s *OpportunityService) List(ctx context.Context, filter *domain.Opportunit
 	return opportunities, total, nil
 }
 
-// Update updates an opportunity
-func (s *OpportunityService) Update(ctx context.Context, id int64, req *domain.CreateOpportunityRequest) (*domain.Opportunity, error) {
+// Update updates an opportunity; publishers may only update their own
+func (s *OpportunityService) Update(ctx context.Context, actor *domain.User, id int64, req *domain.CreateOpportunityRequest) (*domain.Opportunity, error) {
 	// Get existing opportunity
 	opp, err := s.oppRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if !canManageOpportunity(actor, opp) {
+		return nil, ErrForbidden
+	}
+
+	before := *opp
 
 	// Update fields
 	opp.Title = req.Title
@@ -99,20 +119,82 @@ func (s *OpportunityService) Update(ctx context.Context, id int64, req *domain.C
 	opp.Requirements = req.Requirements
 	opp.TargetMajors = req.TargetMajors
 	opp.Tags = req.Tags
+	s.normalize(opp)
+
+	// 参与向量化的文本（标题、描述、标签）变化后旧向量失效，重新向量化（失败时清空，等待回填）
+	if opportunityText(&before) != opportunityText(opp) {
+		opp.DescriptionVector = nil
+		s.vectorize(ctx, opp)
+	}
 
 	if err := s.oppRepo.Update(ctx, opp); err != nil {
 		return nil, fmt.Errorf("failed to update opportunity: %w", err)
 	}
 
+	// 通知已收藏/报名的用户（异步，不阻塞请求）
+	if changes := opportunityChanges(&before, opp); len(changes) > 0 && s.notifications != nil {
+		updated := *opp
+		go s.notifications.OpportunityChanged(context.Background(), &updated, false, changes)
+	}
+
 	return opp, nil
 }
 
-// Delete soft deletes an opportunity
-func (s *OpportunityService) Delete(ctx context.Context, id int64) error {
-	return s.oppRepo.Delete(ctx, id)
+// Delete soft deletes an opportunity and notifies the users following it; publishers may only delete their own
+func (s *OpportunityService) Delete(ctx context.Context, actor *domain.User, id int64) error {
+	opp, err := s.oppRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !canManageOpportunity(actor, opp) {
+		return ErrForbidden
+	}
+
+	if err := s.oppRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if opp.IsActive && s.notifications != nil {
+		go s.notifications.OpportunityChanged(context.Background(), opp, true, nil)
+	}
+
+	return nil
 }
 
 // IncrementSaveCount increments the save count for an opportunity
 func (s *OpportunityService) IncrementSaveCount(ctx context.Context, id int64) error {
 	return s.oppRepo.IncrementSaveCount(ctx, id)
 }
+
+// canManageOpportunity 学校管理员与系统管理员可管理全部机会，发布者仅限自己发布的
+func canManageOpportunity(actor *domain.User, opp *domain.Opportunity) bool {
+	switch actor.Role {
+	case domain.RoleAdmin, domain.RoleSchoolAdmin:
+		return true
+	case domain.RolePublisher:
+		return opp.CreatedBy != nil && *opp.CreatedBy == actor.ID
+	}
+	return false
+}
+
+// normalize maps required skills and majors to canonical taxonomy names
+func (s *OpportunityService) normalize(opp *domain.Opportunity) {
+	opp.Requirements.Skills = s.taxonomy.NormalizeSkills(opp.Requirements.Skills)
+	opp.Requirements.Major = s.taxonomy.NormalizeMajors(opp.Requirements.Major)
+	opp.TargetMajors = s.taxonomy.NormalizeMajors(opp.TargetMajors)
+}
+
+// vectorize fills the description vector of an opportunity when an NLP client is configured
+// Failures are logged and leave the vector empty so that it can be backfilled later
+func (s *OpportunityService) vectorize(ctx context.Context, opp *domain.Opportunity) {
+	if s.nlpClient == nil {
+		return
+	}
+
+	vector, err := s.nlpClient.VectorizeText(ctx, opportunityText(opp))
+	if err != nil {
+		logger.Warnf("failed to vectorize opportunity %q: %v", opp.Title, err)
+		return
+	}
+	opp.DescriptionVector = vector
+}
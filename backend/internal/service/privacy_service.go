@@ -0,0 +1,290 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// purgeLockKey 多实例部署时保证同一时刻只有一个实例在清除到期账号
+const purgeLockKey = "privacy:purge"
+
+// purgeBatchSize 每次扫描最多删除的账号数
+const purgeBatchSize = 100
+
+// ErrDeletionNotRequested indicates the user has no pending deletion request to cancel
+var ErrDeletionNotRequested = errors.New("account deletion has not been requested")
+
+// resumeVersion 导出的简历版本；上传的简历只保存解析出的文本，不保留原文件与历史版本
+type resumeVersion struct {
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PrivacyService handles exporting a user's personal data and deleting accounts
+// after a grace period. Both are recorded in the audit log.
+type PrivacyService struct {
+	cfg          config.PrivacyConfig
+	userRepo     *postgres.UserRepository
+	profileRepo  *postgres.ProfileRepository
+	uoRepo       *postgres.UserOpportunityRepository
+	scheduleRepo *postgres.ScheduleRepository
+	semesterRepo *postgres.SemesterRepository
+	notifRepo    *postgres.NotificationRepository
+	deletionRepo *postgres.AccountDeletionRepository
+	auditRepo    *postgres.AuditLogRepository
+	users        *UserService
+	recService   *RecommendationService
+	locker       *redis.Client
+}
+
+// NewPrivacyService creates a new privacy service
+func NewPrivacyService(
+	cfg config.PrivacyConfig,
+	userRepo *postgres.UserRepository,
+	profileRepo *postgres.ProfileRepository,
+	uoRepo *postgres.UserOpportunityRepository,
+	scheduleRepo *postgres.ScheduleRepository,
+	semesterRepo *postgres.SemesterRepository,
+	notifRepo *postgres.NotificationRepository,
+	deletionRepo *postgres.AccountDeletionRepository,
+	auditRepo *postgres.AuditLogRepository,
+	users *UserService,
+	recService *RecommendationService,
+	locker *redis.Client,
+) *PrivacyService {
+	return &PrivacyService{
+		cfg:          cfg,
+		userRepo:     userRepo,
+		profileRepo:  profileRepo,
+		uoRepo:       uoRepo,
+		scheduleRepo: scheduleRepo,
+		semesterRepo: semesterRepo,
+		notifRepo:    notifRepo,
+		deletionRepo: deletionRepo,
+		auditRepo:    auditRepo,
+		users:        users,
+		recService:   recService,
+		locker:       locker,
+	}
+}
+
+// Export returns a zip archive with everything stored about the user, one JSON file per kind of data
+func (s *PrivacyService) Export(ctx context.Context, userID int64, client *domain.ClientInfo) ([]byte, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil && err.Error() != "profile not found" {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	resumes := make([]resumeVersion, 0, 1)
+	if profile != nil && profile.ResumeText != "" {
+		resumes = append(resumes, resumeVersion{Text: profile.ResumeText, UpdatedAt: profile.UpdatedAt})
+	}
+
+	relations, err := s.uoRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user opportunities: %w", err)
+	}
+	semesters, err := s.semesterRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list semesters: %w", err)
+	}
+	schedules, err := s.scheduleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	notifications, err := s.notifRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"user.json", user},
+		{"profile.json", profile},
+		{"resume_versions.json", resumes},
+		{"user_opportunities.json", relations},
+		{"semesters.json", semesters},
+		{"schedules.json", schedules},
+		{"notifications.json", notifications},
+	}
+	for _, file := range files {
+		if err := writeJSONFile(archive, file.name, file.data); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish export archive: %w", err)
+	}
+
+	if err := s.audit(ctx, userID, domain.AuditAccountExported, nil, client); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RequestDeletion schedules the account for deletion once the grace period ends.
+// The user can keep signing in and cancel until then; asking again keeps the original date.
+func (s *PrivacyService) RequestDeletion(ctx context.Context, userID int64, password string, client *domain.ClientInfo) (*domain.AccountDeletion, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	now := time.Now()
+	deletion := &domain.AccountDeletion{
+		UserID:       userID,
+		RequestedAt:  now,
+		ScheduledFor: now.AddDate(0, 0, s.cfg.DeletionGraceDays),
+	}
+	if err := s.deletionRepo.Schedule(ctx, deletion, clientIP(client)); err != nil {
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	detail := domain.JSONB{"scheduled_for": deletion.ScheduledFor.UTC().Format(time.RFC3339)}
+	if err := s.audit(ctx, userID, domain.AuditAccountDeletionRequested, detail, client); err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+// GetDeletion returns the user's pending deletion request
+func (s *PrivacyService) GetDeletion(ctx context.Context, userID int64) (*domain.AccountDeletion, error) {
+	deletion, err := s.deletionRepo.Get(ctx, userID)
+	if err != nil {
+		if err.Error() == "deletion request not found" {
+			return nil, ErrDeletionNotRequested
+		}
+		return nil, fmt.Errorf("failed to get deletion request: %w", err)
+	}
+	return deletion, nil
+}
+
+// CancelDeletion withdraws the user's pending deletion request
+func (s *PrivacyService) CancelDeletion(ctx context.Context, userID int64, client *domain.ClientInfo) error {
+	if err := s.deletionRepo.Cancel(ctx, userID); err != nil {
+		if err.Error() == "deletion request not found" {
+			return ErrDeletionNotRequested
+		}
+		return fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+	return s.audit(ctx, userID, domain.AuditAccountDeletionCancelled, nil, client)
+}
+
+// Start deletes accounts whose grace period has ended immediately and then on every interval until ctx is cancelled
+func (s *PrivacyService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.GetPurgeInterval())
+	defer ticker.Stop()
+
+	for {
+		if err := s.PurgeDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Errorf("Account purge failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeDue deletes the accounts whose grace period ended before now. The avatar
+// file is removed from storage; deleting the user row removes the profile
+// (including resume text and vector), relations, schedules, notifications,
+// sessions and every other table owned by the user.
+func (s *PrivacyService) PurgeDue(ctx context.Context, now time.Time) error {
+	locked, err := s.locker.Lock(ctx, purgeLockKey, s.cfg.GetPurgeInterval())
+	if err != nil {
+		return fmt.Errorf("failed to acquire purge lock: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		if err := s.locker.Unlock(context.Background(), purgeLockKey); err != nil {
+			logger.Warnf("failed to release purge lock: %v", err)
+		}
+	}()
+
+	userIDs, err := s.deletionRepo.ListDue(ctx, now, purgeBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list due deletions: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		if err := s.users.DeleteAvatar(ctx, userID); err != nil && err.Error() != "user not found" {
+			logger.Errorf("failed to delete avatar of user %d: %v", userID, err)
+			continue
+		}
+		if err := s.userRepo.Delete(ctx, userID); err != nil && err.Error() != "user not found" {
+			logger.Errorf("failed to delete user %d: %v", userID, err)
+			continue
+		}
+		s.recService.Invalidate(ctx, userID)
+
+		if err := s.audit(ctx, userID, domain.AuditAccountDeleted, nil, nil); err != nil {
+			logger.Errorf("%v", err)
+		}
+		logger.Infof("Deleted user %d after the deletion grace period", userID)
+	}
+	return nil
+}
+
+// audit 记录审计日志；client 为空表示系统任务
+func (s *PrivacyService) audit(ctx context.Context, userID int64, action string, detail domain.JSONB, client *domain.ClientInfo) error {
+	entry := &domain.AuditLog{
+		UserID: userID,
+		Action: action,
+		Detail: detail,
+		IP:     clientIP(client),
+	}
+	if client != nil {
+		entry.ActorID = &userID
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to write audit log for %s: %w", action, err)
+	}
+	return nil
+}
+
+// clientIP 客户端IP，最长64个字符
+func clientIP(client *domain.ClientInfo) string {
+	if client == nil {
+		return ""
+	}
+	return truncateRunes(client.IP, 64)
+}
+
+// writeJSONFile 以带缩进的JSON写入压缩包中的一个文件
+func writeJSONFile(archive *zip.Writer, name string, v interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -13,7 +13,9 @@ import (
 // ProfileService handles user profile business logic
 type ProfileService struct {
 	profileRepo *postgres.ProfileRepository
-	nlpClient   NLPClient // NLP服务客户端（待实现）
+	nlpClient   NLPClient        // NLP服务客户端，未配置时为nil
+	taxonomy    *TaxonomyService // 技能名称规范化，可为nil
+	recService  *RecommendationService
 }
 
 // NLPClient NLP服务客户端接口
@@ -24,10 +26,12 @@ type NLPClient interface {
 }
 
 // NewProfileService creates a new profile service
-func NewProfileService(profileRepo *postgres.ProfileRepository, nlpClient NLPClient) *ProfileService {
+func NewProfileService(profileRepo *postgres.ProfileRepository, nlpClient NLPClient, taxonomy *TaxonomyService, recService *RecommendationService) *ProfileService {
 	return &ProfileService{
 		profileRepo: profileRepo,
 		nlpClient:   nlpClient,
+		taxonomy:    taxonomy,
+		recService:  recService,
 	}
 }
 
@@ -46,7 +50,7 @@ func (s *ProfileService) UpdateProfile(ctx context.Context, userID int64, req *d
 	profile := &domain.UserProfile{
 		UserID:       userID,
 		ResumeText:   req.ResumeText,
-		Skills:       req.Skills,
+		Skills:       s.taxonomy.NormalizeSkills(req.Skills),
 		Certificates: req.Certificates,
 		Interests:    req.Interests,
 	}
@@ -63,6 +67,8 @@ func (s *ProfileService) UpdateProfile(ctx context.Context, userID int64, req *d
 		return nil, fmt.Errorf("failed to update profile: %w", err)
 	}
 
+	// 技能、兴趣与简历向量都参与推荐评分
+	s.recService.Invalidate(ctx, userID)
 	return profile, nil
 }
 
@@ -97,11 +103,18 @@ func (s *ProfileService) UploadResume(ctx context.Context, userID int64, file mu
 		}
 	}
 
-	// 更新或创建profile
-	profile := &domain.UserProfile{
-		UserID:     userID,
-		ResumeText: text,
-		Skills:     skills,
+	// 更新或创建profile（保留已填写的证书与兴趣，未识别出技能时保留原有技能）
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if err.Error() != "profile not found" {
+			return nil, fmt.Errorf("failed to get profile: %w", err)
+		}
+		profile = &domain.UserProfile{UserID: userID}
+	}
+	profile.ResumeText = text
+	profile.ResumeVector = nil
+	if len(skills) > 0 {
+		profile.Skills = s.taxonomy.NormalizeSkills(skills)
 	}
 
 	// 向量化简历文本
@@ -116,5 +129,6 @@ func (s *ProfileService) UploadResume(ctx context.Context, userID int64, file mu
 		return nil, fmt.Errorf("failed to save profile: %w", err)
 	}
 
+	s.recService.Invalidate(ctx, userID)
 	return profile, nil
 }
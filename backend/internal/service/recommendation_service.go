@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// maxReasons 每个推荐项返回的解释分项数量
+const maxReasons = 3
+
+// candidateBatchSize 分批读取候选机会时每批的数量
+const candidateBatchSize = 500
+
+// ErrInvalidCursor indicates the pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// RecommendationService builds the personalized opportunity feed
+type RecommendationService struct {
+	cfg          config.RecommendationConfig
+	scoring      *ScoringService
+	userRepo     *postgres.UserRepository
+	profileRepo  *postgres.ProfileRepository
+	oppRepo      *postgres.OpportunityRepository
+	scheduleRepo *postgres.ScheduleRepository
+	semesterRepo *postgres.SemesterRepository
+	uoRepo       *postgres.UserOpportunityRepository
+	cache        *redis.Client
+}
+
+// NewRecommendationService creates a new recommendation service
+func NewRecommendationService(
+	cfg config.RecommendationConfig,
+	scoring *ScoringService,
+	userRepo *postgres.UserRepository,
+	profileRepo *postgres.ProfileRepository,
+	oppRepo *postgres.OpportunityRepository,
+	scheduleRepo *postgres.ScheduleRepository,
+	semesterRepo *postgres.SemesterRepository,
+	uoRepo *postgres.UserOpportunityRepository,
+	cache *redis.Client,
+) *RecommendationService {
+	return &RecommendationService{
+		cfg:          cfg,
+		scoring:      scoring,
+		userRepo:     userRepo,
+		profileRepo:  profileRepo,
+		oppRepo:      oppRepo,
+		scheduleRepo: scheduleRepo,
+		semesterRepo: semesterRepo,
+		uoRepo:       uoRepo,
+		cache:        cache,
+	}
+}
+
+// Feed returns one page of the user's recommendation feed
+func (s *RecommendationService) Feed(ctx context.Context, userID int64, query *domain.RecommendationQuery) (*domain.RecommendationPage, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	ranked, err := s.ranked(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if query.Cursor != "" {
+		score, id, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// 定位到游标之后的第一项（排序: score降序, id升序）
+		start = sort.Search(len(ranked), func(i int) bool {
+			item := ranked[i]
+			if item.Score != score {
+				return item.Score < score
+			}
+			return item.Opportunity.ID > id
+		})
+	}
+
+	end := start + limit
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+
+	page := &domain.RecommendationPage{Items: ranked[start:end]}
+	if end < len(ranked) {
+		last := ranked[end-1]
+		page.NextCursor = encodeCursor(last.Score, last.Opportunity.ID)
+	}
+
+	return page, nil
+}
+
+// NewMatches returns the best-ranked opportunities published after since whose
+// feed score is at least minScore, at most limit of them
+func (s *RecommendationService) NewMatches(ctx context.Context, userID int64, since time.Time, minScore float64, limit int) ([]*domain.RecommendationItem, error) {
+	ranked, err := s.ranked(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*domain.RecommendationItem
+	for _, item := range ranked {
+		if len(matches) >= limit {
+			break
+		}
+		if item.Score >= minScore && item.Opportunity.CreatedAt.After(since) {
+			matches = append(matches, item)
+		}
+	}
+
+	return matches, nil
+}
+
+// Invalidate drops the cached feed of a user so the next request re-ranks
+func (s *RecommendationService) Invalidate(ctx context.Context, userID int64) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Delete(ctx, recommendationCacheKey(userID)); err != nil {
+		logger.Warnf("failed to invalidate recommendations for user %d: %v", userID, err)
+	}
+}
+
+// ranked returns the full ranked feed, from cache when available
+func (s *RecommendationService) ranked(ctx context.Context, userID int64) ([]*domain.RecommendationItem, error) {
+	key := recommendationCacheKey(userID)
+
+	if s.cache != nil {
+		if data, err := s.cache.GetBytes(ctx, key); err == nil {
+			var items []*domain.RecommendationItem
+			if err := json.Unmarshal(data, &items); err == nil {
+				return items, nil
+			}
+		}
+	}
+
+	items, err := s.rank(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		if data, err := json.Marshal(items); err == nil {
+			if err := s.cache.Set(ctx, key, data, s.cfg.GetCacheTTL()); err != nil {
+				logger.Warnf("failed to cache recommendations for user %d: %v", userID, err)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// rank scores every candidate opportunity and sorts them by blended score
+func (s *RecommendationService) rank(ctx context.Context, userID int64) ([]*domain.RecommendationItem, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if err.Error() != "profile not found" {
+			return nil, fmt.Errorf("failed to get profile: %w", err)
+		}
+		profile = &domain.UserProfile{UserID: userID}
+	}
+
+	schedules, err := s.scheduleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	semesters, err := s.semesterRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list semesters: %w", err)
+	}
+
+	candidates, err := listCandidates(ctx, s.oppRepo, user)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(candidates))
+	for i, opp := range candidates {
+		ids[i] = opp.ID
+	}
+
+	peerRates, err := s.uoRepo.PeerParticipationRates(ctx, ids, user.Major)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute peer participation: %w", err)
+	}
+
+	disliked, err := s.oppRepo.ListByFeedback(ctx, userID, []string{domain.UserFeedbackNotInterested, domain.UserFeedbackIrrelevant})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list negative feedback: %w", err)
+	}
+	dislikes := newDislikeProfile(disliked)
+
+	now := time.Now()
+	items := make([]*domain.RecommendationItem, 0, len(candidates))
+	for _, opp := range candidates {
+		detail := s.scoring.Compute(&ScoreInput{
+			User:              user,
+			Profile:           profile,
+			Opportunity:       opp,
+			Schedules:         schedules,
+			Semesters:         semesters,
+			PeerParticipation: peerRates[opp.ID],
+		})
+
+		urgency := deadlineUrgency(opp.Deadline, now, s.cfg.UrgencyWindowDays)
+		score, reasons := s.blend(detail, urgency)
+
+		// 与负反馈机会相似（标签/类型/主办方）时按比例降权
+		penalty := s.cfg.FeedbackPenalty * dislikes.similarity(opp)
+		score = round2(score * (1 - penalty))
+
+		items = append(items, &domain.RecommendationItem{
+			Opportunity:        opp,
+			Score:              score,
+			AccessibilityScore: detail.Accessibility.Total,
+			RelevanceScore:     detail.Relevance.Total,
+			Urgency:            round2(urgency),
+			FeedbackPenalty:    round2(penalty),
+			Reasons:            reasons,
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Score != items[j].Score {
+			return items[i].Score > items[j].Score
+		}
+		return items[i].Opportunity.ID < items[j].Opportunity.ID
+	})
+
+	// 对全部候选评分后只保留排名靠前的部分
+	if len(items) > s.cfg.CandidateLimit {
+		items = items[:s.cfg.CandidateLimit]
+	}
+	return items, nil
+}
+
+// listCandidates 按id分批读取用户可参与的全部候选机会，保证排序覆盖完整候选集
+func listCandidates(ctx context.Context, oppRepo *postgres.OpportunityRepository, user *domain.User) ([]*domain.Opportunity, error) {
+	var candidates []*domain.Opportunity
+	afterID := int64(0)
+	for {
+		batch, err := oppRepo.ListRecommendationCandidates(ctx, user.ID, user.Grade, afterID, candidateBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list candidates: %w", err)
+		}
+		candidates = append(candidates, batch...)
+		if len(batch) < candidateBatchSize {
+			return candidates, nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+// blend combines relevance, accessibility and urgency into the feed score (0-100)
+// and returns the components that contributed most to it
+func (s *RecommendationService) blend(detail domain.ScoreDetail, urgency float64) (float64, []domain.ScoreReason) {
+	wr, wa, wu := s.cfg.RelevanceWeight, s.cfg.AccessibilityWeight, s.cfg.UrgencyWeight
+	sum := wr + wa + wu
+	if sum == 0 {
+		return 0, nil
+	}
+
+	score := (wr*detail.Relevance.Total + wa*detail.Accessibility.Total + wu*100*urgency) / sum
+
+	var reasons []domain.ScoreReason
+	for component, points := range s.scoring.Contributions(detail) {
+		weight := wr
+		if component == "eligibility" || component == "skills_match" || component == "time_cost" {
+			weight = wa
+		}
+		reasons = append(reasons, domain.ScoreReason{Component: component, Contribution: weight * points / sum})
+	}
+	reasons = append(reasons, domain.ScoreReason{Component: "deadline_urgency", Contribution: wu * 100 * urgency / sum})
+
+	return round2(score), topReasons(reasons, maxReasons)
+}
+
+// topReasons keeps the n largest positive contributions
+func topReasons(reasons []domain.ScoreReason, n int) []domain.ScoreReason {
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Contribution != reasons[j].Contribution {
+			return reasons[i].Contribution > reasons[j].Contribution
+		}
+		return reasons[i].Component < reasons[j].Component
+	})
+
+	top := make([]domain.ScoreReason, 0, n)
+	for _, r := range reasons {
+		if len(top) == n || r.Contribution <= 0 {
+			break
+		}
+		r.Contribution = round2(r.Contribution)
+		top = append(top, r)
+	}
+	return top
+}
+
+// dislikeProfile 用户标记为不感兴趣/无关的机会特征分布
+type dislikeProfile struct {
+	total      int
+	types      map[string]int
+	organizers map[string]int
+	tags       map[string]int
+}
+
+// newDislikeProfile counts the type, organizer and tags of disliked opportunities
+func newDislikeProfile(opps []*domain.Opportunity) *dislikeProfile {
+	p := &dislikeProfile{
+		total:      len(opps),
+		types:      make(map[string]int),
+		organizers: make(map[string]int),
+		tags:       make(map[string]int),
+	}
+
+	for _, opp := range opps {
+		if key := normalizeTerm(opp.Type); key != "" {
+			p.types[key]++
+		}
+		if key := normalizeTerm(opp.Organizer); key != "" {
+			p.organizers[key]++
+		}
+		for tag := range termSet(opp.Tags) {
+			p.tags[tag]++
+		}
+	}
+
+	return p
+}
+
+// similarity 计算机会与负反馈机会的相似度（0-1）
+// 类型、主办方、标签三项各占1/3，每项取该特征在负反馈机会中出现的比例
+func (p *dislikeProfile) similarity(opp *domain.Opportunity) float64 {
+	if p.total == 0 {
+		return 0
+	}
+
+	n := float64(p.total)
+	typeSim := float64(p.types[normalizeTerm(opp.Type)]) / n
+	organizerSim := 0.0
+	if key := normalizeTerm(opp.Organizer); key != "" {
+		organizerSim = float64(p.organizers[key]) / n
+	}
+	tagSim := 0.0
+	for tag := range termSet(opp.Tags) {
+		tagSim = math.Max(tagSim, float64(p.tags[tag])/n)
+	}
+
+	return clamp01((typeSim + organizerSim + tagSim) / 3)
+}
+
+// deadlineUrgency 计算截止紧迫度（0-1）
+// 截止日期在窗口期内线性增长，当天截止为1，无截止日期为0
+func deadlineUrgency(deadline *time.Time, now time.Time, windowDays int) float64 {
+	if deadline == nil || windowDays <= 0 {
+		return 0
+	}
+
+	days := deadline.Sub(now).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+
+	return math.Max(0, 1-days/float64(windowDays))
+}
+
+func recommendationCacheKey(userID int64) string {
+	return fmt.Sprintf("recommendations:user:%d", userID)
+}
+
+// encodeCursor 将最后一项的得分和ID编码为不透明游标
+func encodeCursor(score float64, id int64) string {
+	raw := strconv.FormatFloat(score, 'f', -1, 64) + ":" + strconv.FormatInt(id, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor 解析encodeCursor生成的游标
+func decodeCursor(cursor string) (float64, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, ErrInvalidCursor
+	}
+
+	score, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+
+	return score, id, nil
+}
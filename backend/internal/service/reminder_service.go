@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/notify"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// reminderLockKey 多实例部署时保证同一时刻只有一个实例在扫描
+const reminderLockKey = "reminder:scan"
+
+// ReminderService sends deadline reminders for saved and applied opportunities
+type ReminderService struct {
+	cfg          config.ReminderConfig
+	loc          *time.Location
+	window       int // 扫描截止日期的最大提前天数，覆盖系统与用户设置的提醒档位
+	reminderRepo *postgres.ReminderRepository
+	uoRepo       *postgres.UserOpportunityRepository
+	dispatcher   *notify.Dispatcher
+	locker       *redis.Client
+}
+
+// NewReminderService creates a new reminder service
+func NewReminderService(
+	cfg config.ReminderConfig,
+	loc *time.Location,
+	reminderRepo *postgres.ReminderRepository,
+	uoRepo *postgres.UserOpportunityRepository,
+	dispatcher *notify.Dispatcher,
+	locker *redis.Client,
+) *ReminderService {
+	window := domain.MaxReminderOffsetDays
+	for _, offset := range cfg.OffsetDays {
+		if offset > window {
+			window = offset
+		}
+	}
+
+	return &ReminderService{
+		cfg:          cfg,
+		loc:          loc,
+		window:       window,
+		reminderRepo: reminderRepo,
+		uoRepo:       uoRepo,
+		dispatcher:   dispatcher,
+		locker:       locker,
+	}
+}
+
+// Start scans for due reminders immediately and then on every interval until ctx is cancelled
+func (s *ReminderService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.GetInterval())
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Errorf("Deadline reminder scan failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends the reminders due at now, using each user's lead times and channels.
+// Each (relation, deadline, offset, channel) is claimed in the database before sending,
+// so a restart or a concurrent scan never sends it twice; a failed delivery releases
+// its claim and is retried on the next scan. During the user's quiet hours the
+// dispatcher stores in-app reminders silently and holds back other pushes; both
+// count as sent.
+func (s *ReminderService) RunOnce(ctx context.Context, now time.Time) error {
+	locked, err := s.locker.Lock(ctx, reminderLockKey, s.cfg.GetInterval())
+	if err != nil {
+		return fmt.Errorf("failed to acquire reminder lock: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		if err := s.locker.Unlock(context.Background(), reminderLockKey); err != nil {
+			logger.Warnf("failed to release reminder lock: %v", err)
+		}
+	}()
+
+	// 截止日期为DATE列，按配置时区的“今天”计算剩余天数
+	y, m, d := now.In(s.loc).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	due, err := s.reminderRepo.ListDue(ctx, today, today.AddDate(0, 0, s.window))
+	if err != nil {
+		return fmt.Errorf("failed to list due reminders: %w", err)
+	}
+
+	sent := 0
+	prefs := make(map[int64]*domain.NotificationPreferences)
+	for _, reminder := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		userPrefs, ok := prefs[reminder.User.ID]
+		if !ok {
+			userPrefs = s.dispatcher.Preferences(ctx, reminder.User.ID)
+			prefs[reminder.User.ID] = userPrefs
+		}
+
+		deadline := *reminder.Opportunity.Deadline
+		daysLeft := daysBetween(today, deadline)
+		offset, ok := reminderOffset(userPrefs.ReminderOffsetDays, daysLeft)
+		if !ok {
+			continue
+		}
+
+		delivered, channels := s.deliver(ctx, reminder, userPrefs, deadline, daysLeft, offset, now)
+		if delivered {
+			sent++
+		}
+		if len(channels) == 0 {
+			continue
+		}
+		if err := s.uoRepo.MarkPushed(ctx, reminder.UserOpportunityID, channels); err != nil {
+			logger.Warnf("failed to record push for user opportunity %d: %v", reminder.UserOpportunityID, err)
+		}
+	}
+
+	if sent > 0 {
+		logger.Infof("Sent %d deadline reminders", sent)
+	}
+	return nil
+}
+
+// deliver 在用户选择且尚未发送过的渠道上推送提醒
+// 返回是否有渠道发送或暂存成功，以及立即送达的渠道
+func (s *ReminderService) deliver(ctx context.Context, reminder *domain.DueReminder, prefs *domain.NotificationPreferences, deadline time.Time, daysLeft, offset int, now time.Time) (bool, []string) {
+	delivered := false
+	var channels []string
+	for _, channel := range s.dispatcher.Channels(prefs, domain.NotificationTypeDeadlineReminder) {
+		claimed, err := s.reminderRepo.Claim(ctx, reminder.UserOpportunityID, deadline, offset, channel)
+		if err != nil {
+			logger.Warnf("failed to claim reminder for user opportunity %d: %v", reminder.UserOpportunityID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		held, err := s.dispatcher.Deliver(ctx, reminder.User, prefs, channel, reminderNotification(reminder, daysLeft, offset), now)
+		if err != nil {
+			logger.Warnf("failed to send %s reminder to user %d: %v", channel, reminder.User.ID, err)
+			if err := s.reminderRepo.Release(context.Background(), reminder.UserOpportunityID, deadline, offset, channel); err != nil {
+				logger.Errorf("failed to release reminder claim for user opportunity %d: %v", reminder.UserOpportunityID, err)
+			}
+			continue
+		}
+		delivered = true
+		if !held {
+			channels = append(channels, channel)
+		}
+	}
+	return delivered, channels
+}
+
+// reminderOffset 返回不小于剩余天数的最小提醒档位
+// 例如档位[1,3,7]、剩余5天时发送7天档；之后剩余3天时再发送3天档
+func reminderOffset(offsets []int, daysLeft int) (int, bool) {
+	for _, offset := range offsets {
+		if offset >= daysLeft {
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+// daysBetween 返回两个日期（零点）之间相差的天数
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Round(time.Hour).Hours() / 24)
+}
+
+// reminderNotification 构造截止提醒的通知内容
+func reminderNotification(reminder *domain.DueReminder, daysLeft, offset int) *domain.Notification {
+	opp := reminder.Opportunity
+	deadline := opp.Deadline.Format("2006-01-02")
+
+	title := fmt.Sprintf("「%s」将在%d天后截止", opp.Title, daysLeft)
+	if daysLeft == 0 {
+		title = fmt.Sprintf("「%s」今天截止", opp.Title)
+	}
+
+	action := "尚未报名，请尽快完成报名"
+	if reminder.Status == domain.UserOpportunityStatusApplied {
+		action = "你已报名，请确认材料已全部提交"
+	}
+
+	oppID := opp.ID
+	return &domain.Notification{
+		UserID:        reminder.User.ID,
+		Type:          domain.NotificationTypeDeadlineReminder,
+		Title:         title,
+		Body:          fmt.Sprintf("截止日期：%s。%s。", deadline, action),
+		URL:           opp.SourceURL,
+		OpportunityID: &oppID,
+		Data: domain.JSONB{
+			"deadline":    deadline,
+			"days_left":   daysLeft,
+			"offset_days": offset,
+			"status":      reminder.Status,
+		},
+	}
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/ical"
+	"github.com/unifocus/backend/internal/recurrence"
+	"github.com/unifocus/backend/internal/timetable"
+)
+
+const (
+	// maxImportKeyLength import_key列长度
+	maxImportKeyLength = 255
+	// maxScheduleTitleLength 日程标题长度上限，与创建请求一致
+	maxScheduleTitleLength = 200
+)
+
+// Import creates or updates schedules from an .ics file or a CSV timetable
+// Entries are keyed by their source (ICS UID or CSV course/weekday/period),
+// so importing the same file again updates the existing schedules.
+func (s *ScheduleService) Import(ctx context.Context, userID int64, school string, req *domain.ScheduleImportRequest, filename string, src io.Reader) (*domain.ScheduleImportResult, error) {
+	format := req.Format
+	if format == "" {
+		format = importFormat(filename)
+	}
+	if format == "" {
+		return nil, fmt.Errorf("%w: unsupported file type, expected .ics or .csv", ErrInvalidSchedule)
+	}
+
+	var semester *domain.Semester
+	if req.SemesterID != nil {
+		var err error
+		if semester, err = s.semesterRepo.GetByID(ctx, userID, *req.SemesterID); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+	}
+
+	scheduleType := req.Type
+	if scheduleType == "" {
+		scheduleType = domain.ScheduleTypeCourse
+	}
+
+	result := &domain.ScheduleImportResult{
+		Errors:    []*domain.ScheduleImportError{},
+		Schedules: []*domain.Schedule{},
+	}
+
+	var schedules []*importedSchedule
+	switch format {
+	case domain.ScheduleImportFormatICS:
+		events, invalid, err := ical.Parse(src, s.loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		for _, e := range invalid {
+			result.Errors = append(result.Errors, &domain.ScheduleImportError{Line: e.Line, UID: e.UID, Message: e.Err.Error()})
+		}
+		schedules = icsSchedules(events, semester, scheduleType, s.loc)
+	case domain.ScheduleImportFormatCSV:
+		if semester == nil {
+			return nil, fmt.Errorf("%w: semester_id is required for CSV timetables", ErrInvalidSchedule)
+		}
+		entries, invalid, err := timetable.ParseCSV(src)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		for _, e := range invalid {
+			result.Errors = append(result.Errors, &domain.ScheduleImportError{Line: e.Line, Message: e.Err.Error()})
+		}
+		schedules = csvSchedules(entries, s.timetable.PeriodsFor(school), semester, scheduleType)
+	}
+	result.Skipped = len(result.Errors)
+
+	for _, item := range schedules {
+		if item.err != nil {
+			result.Skipped++
+			result.Errors = append(result.Errors, &domain.ScheduleImportError{Line: item.line, UID: item.uid, Message: item.err.Error()})
+			continue
+		}
+
+		item.schedule.UserID = userID
+		created, err := s.scheduleRepo.Upsert(ctx, item.schedule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import schedule %q: %w", item.schedule.Title, err)
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		result.Schedules = append(result.Schedules, item.schedule)
+	}
+
+	return result, nil
+}
+
+// importedSchedule 待写入的导入日程，或无法导入的原因
+type importedSchedule struct {
+	schedule *domain.Schedule
+	line     int
+	uid      string
+	err      error
+}
+
+// importFormat 根据文件扩展名判断导入格式
+func importFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ics", ".ical", ".icalendar":
+		return domain.ScheduleImportFormatICS
+	case ".csv":
+		return domain.ScheduleImportFormatCSV
+	}
+	return ""
+}
+
+// icsSchedules 将VEVENT转换为日程；覆盖某次发生的事件（RECURRENCE-ID）
+// 作为单次日程导入，并将原日期加入重复日程的例外日期
+func icsSchedules(events []*ical.Event, semester *domain.Semester, scheduleType string, loc *time.Location) []*importedSchedule {
+	var items []*importedSchedule
+	masters := make(map[string]*domain.Schedule)
+	var overrides []*ical.Event
+
+	for _, e := range events {
+		if e.RecurrenceID != nil {
+			overrides = append(overrides, e)
+			continue
+		}
+
+		item := &importedSchedule{uid: e.UID}
+		items = append(items, item)
+		if strings.TrimSpace(e.Summary) == "" {
+			item.err = errors.New("missing SUMMARY")
+			continue
+		}
+
+		schedule := &domain.Schedule{
+			Title:       truncateRunes(e.Summary, maxScheduleTitleLength),
+			Type:        scheduleType,
+			StartTime:   wallClock(e.Start),
+			EndTime:     wallClock(e.End),
+			Location:    e.Location,
+			Description: e.Description,
+			ImportKey:   importKey("ics:", e.UID, e.Summary+"|"+e.Start.Format(time.RFC3339)),
+		}
+		if !schedule.EndTime.After(schedule.StartTime) {
+			schedule.EndTime = schedule.StartTime.Add(time.Hour)
+		}
+
+		if e.RRule != "" {
+			rule, err := normalizeImportedRule(e.RRule, loc)
+			if err != nil {
+				item.err = err
+				continue
+			}
+			schedule.IsRecurring = true
+			schedule.RecurrenceRule = rule
+			for _, d := range e.ExDates {
+				schedule.ExceptionDates = appendDate(schedule.ExceptionDates, d)
+			}
+			if semester != nil {
+				schedule.SemesterID = &semester.ID
+			}
+		}
+
+		item.schedule = schedule
+		if e.UID != "" {
+			masters[e.UID] = schedule
+		}
+	}
+
+	for _, e := range overrides {
+		if master, ok := masters[e.UID]; ok {
+			master.ExceptionDates = appendDate(master.ExceptionDates, *e.RecurrenceID)
+		}
+
+		item := &importedSchedule{uid: e.UID}
+		items = append(items, item)
+		title := e.Summary
+		if strings.TrimSpace(title) == "" {
+			if master, ok := masters[e.UID]; ok {
+				title = master.Title
+			}
+		}
+		if strings.TrimSpace(title) == "" {
+			item.err = errors.New("missing SUMMARY")
+			continue
+		}
+
+		schedule := &domain.Schedule{
+			Title:       truncateRunes(title, maxScheduleTitleLength),
+			Type:        scheduleType,
+			StartTime:   wallClock(e.Start),
+			EndTime:     wallClock(e.End),
+			Location:    e.Location,
+			Description: e.Description,
+			ImportKey:   importKey("ics:", e.UID+"#"+e.RecurrenceID.Format("20060102T150405"), title),
+		}
+		if !schedule.EndTime.After(schedule.StartTime) {
+			schedule.EndTime = schedule.StartTime.Add(time.Hour)
+		}
+		item.schedule = schedule
+	}
+
+	return items
+}
+
+// normalizeImportedRule 校验RRULE，并将UTC时刻的UNTIL转换为loc中的日期（含当天），
+// 与重复规则按墙上时间展开的方式保持一致
+func normalizeImportedRule(value string, loc *time.Location) (string, error) {
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	if rule.Until != nil {
+		local := *rule.Until
+		if !isMidnight(local) {
+			local = local.In(loc)
+		}
+		until := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		rule.Until = &until
+	}
+	return rule.String(), nil
+}
+
+// csvSchedules 将课表行转换为每周重复的日程
+// 同一课程在同一时段（星期与起止节次均相同）的多行（如 1-8周 与 10-16周）合并为一条日程
+func csvSchedules(entries []*timetable.Entry, periods []config.ClassPeriod, semester *domain.Semester, scheduleType string) []*importedSchedule {
+	byPeriod := make(map[int]config.ClassPeriod, len(periods))
+	for _, p := range periods {
+		byPeriod[p.Period] = p
+	}
+
+	var keys []string
+	merged := make(map[string]*timetable.Entry)
+	for _, entry := range entries {
+		key := fmt.Sprintf("%s|%d|%d-%d", entry.Title, entry.Weekday, entry.FirstPeriod, entry.LastPeriod)
+		existing, ok := merged[key]
+		if !ok {
+			copied := *entry
+			merged[key] = &copied
+			keys = append(keys, key)
+			continue
+		}
+		existing.Weeks = mergeWeeks(existing.Weeks, entry.Weeks)
+	}
+
+	week1 := weekMonday(semester.StartDate)
+	items := make([]*importedSchedule, 0, len(keys))
+	for _, key := range keys {
+		entry := merged[key]
+		item := &importedSchedule{line: entry.Line}
+		items = append(items, item)
+
+		first, ok := byPeriod[entry.FirstPeriod]
+		if !ok {
+			item.err = fmt.Errorf("period %d is not configured for this school", entry.FirstPeriod)
+			continue
+		}
+		last, ok := byPeriod[entry.LastPeriod]
+		if !ok {
+			item.err = fmt.Errorf("period %d is not configured for this school", entry.LastPeriod)
+			continue
+		}
+
+		dayOffset := (int(entry.Weekday) + 6) % 7
+		dateOf := func(week int) time.Time {
+			return week1.AddDate(0, 0, (week-1)*7+dayOffset)
+		}
+		firstDate := dateOf(entry.Weeks[0])
+
+		schedule := &domain.Schedule{
+			Title:      truncateRunes(entry.Title, maxScheduleTitleLength),
+			Type:       scheduleType,
+			StartTime:  atClock(firstDate, first.Start),
+			EndTime:    atClock(firstDate, last.End),
+			Location:   entry.Location,
+			SemesterID: &semester.ID,
+			ImportKey:  importKey("csv:", fmt.Sprintf("%d|%s", semester.ID, key), ""),
+		}
+		if entry.Teacher != "" {
+			schedule.Description = "教师：" + entry.Teacher
+		}
+		if !schedule.EndTime.After(schedule.StartTime) {
+			item.err = fmt.Errorf("periods %d-%d end before they start", entry.FirstPeriod, entry.LastPeriod)
+			continue
+		}
+
+		if len(entry.Weeks) > 1 {
+			interval := weekInterval(entry.Weeks)
+			lastWeek := entry.Weeks[len(entry.Weeks)-1]
+			until := dateOf(lastWeek)
+			rule := &recurrence.Rule{Freq: recurrence.FreqWeekly, Interval: interval, Until: &until}
+
+			// 区间内未排课的周次作为例外日期
+			listed := make(map[int]bool, len(entry.Weeks))
+			for _, w := range entry.Weeks {
+				listed[w] = true
+			}
+			for w := entry.Weeks[0]; w <= lastWeek; w += interval {
+				if !listed[w] {
+					schedule.ExceptionDates = append(schedule.ExceptionDates, dateOf(w))
+				}
+			}
+
+			schedule.IsRecurring = true
+			schedule.RecurrenceRule = rule.String()
+		}
+
+		item.schedule = schedule
+	}
+
+	return items
+}
+
+// isMidnight 判断是否为仅含日期的值
+func isMidnight(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0
+}
+
+// weekInterval 所有周次奇偶相同时按隔周重复，否则每周重复
+func weekInterval(weeks []int) int {
+	for _, w := range weeks[1:] {
+		if (w-weeks[0])%2 != 0 {
+			return 1
+		}
+	}
+	return 2
+}
+
+// mergeWeeks 合并两个升序周次列表并去重
+func mergeWeeks(a, b []int) []int {
+	seen := make(map[int]bool, len(a)+len(b))
+	var weeks []int
+	for _, w := range append(append([]int{}, a...), b...) {
+		if !seen[w] {
+			seen[w] = true
+			weeks = append(weeks, w)
+		}
+	}
+	sort.Ints(weeks)
+	return weeks
+}
+
+// weekMonday 返回某天所在周的周一（UTC墙上时间）
+func weekMonday(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// atClock 返回某天的 HH:MM 时刻；格式已在加载配置时校验
+func atClock(day time.Time, clock string) time.Time {
+	parts := strings.SplitN(clock, ":", 2)
+	hour, _ := strconv.Atoi(parts[0])
+	minute := 0
+	if len(parts) == 2 {
+		minute, _ = strconv.Atoi(parts[1])
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.UTC)
+}
+
+// wallClock 保留墙上时间并转换为UTC位置（TIMESTAMP列不带时区）
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// appendDate 追加日期（去掉时刻），已存在时忽略
+func appendDate(dates []time.Time, t time.Time) []time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	for _, d := range dates {
+		if d.Equal(day) {
+			return dates
+		}
+	}
+	return append(dates, day)
+}
+
+// importKey 生成导入来源键；id为空时使用fallback的摘要，过长时使用摘要
+func importKey(prefix, id, fallback string) string {
+	if id == "" {
+		return fmt.Sprintf("%s%x", prefix, sha1.Sum([]byte(fallback)))
+	}
+	if len(prefix)+len(id) > maxImportKeyLength {
+		return fmt.Sprintf("%s%x", prefix, sha1.Sum([]byte(id)))
+	}
+	return prefix + id
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
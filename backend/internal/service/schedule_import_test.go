@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/timetable"
+)
+
+func TestCSVSchedulesMerge(t *testing.T) {
+	periods := []config.ClassPeriod{
+		{Period: 1, Start: "08:00", End: "08:45"},
+		{Period: 2, Start: "08:55", End: "09:40"},
+		{Period: 3, Start: "10:00", End: "10:45"},
+	}
+	entries := []*timetable.Entry{
+		{Line: 2, Title: "高等数学", Weekday: time.Monday, FirstPeriod: 1, LastPeriod: 2, Weeks: []int{1, 2, 3}},
+		{Line: 3, Title: "高等数学", Weekday: time.Monday, FirstPeriod: 1, LastPeriod: 2, Weeks: []int{5, 6}},
+		{Line: 4, Title: "高等数学", Weekday: time.Monday, FirstPeriod: 1, LastPeriod: 3, Weeks: []int{8}},
+	}
+	spring := &domain.Semester{ID: 1, StartDate: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)}
+	autumn := &domain.Semester{ID: 2, StartDate: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)}
+
+	items := csvSchedules(entries, periods, spring, domain.ScheduleTypeCourse)
+	if len(items) != 2 {
+		t.Fatalf("csvSchedules() returned %d schedules, want 2 (different last periods are not merged)", len(items))
+	}
+
+	merged, longer := items[0].schedule, items[1].schedule
+	if merged == nil || longer == nil {
+		t.Fatalf("csvSchedules() errors = %v, %v", items[0].err, items[1].err)
+	}
+	if len(merged.ExceptionDates) != 1 || !merged.ExceptionDates[0].Equal(time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("merged exception dates = %v, want week 4 only", merged.ExceptionDates)
+	}
+	if merged.EndTime.Hour() != 9 || merged.EndTime.Minute() != 40 {
+		t.Errorf("merged end time = %v, want 09:40", merged.EndTime)
+	}
+	if longer.EndTime.Hour() != 10 || longer.EndTime.Minute() != 45 {
+		t.Errorf("longer end time = %v, want 10:45", longer.EndTime)
+	}
+	if merged.ImportKey == longer.ImportKey {
+		t.Errorf("schedules with different periods share import key %q", merged.ImportKey)
+	}
+
+	other := csvSchedules(entries, periods, autumn, domain.ScheduleTypeCourse)
+	if other[0].schedule.ImportKey == merged.ImportKey {
+		t.Errorf("the same course in another semester shares import key %q", merged.ImportKey)
+	}
+}
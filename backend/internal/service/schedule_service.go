@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/recurrence"
+	"github.com/unifocus/backend/internal/repository/postgres"
+)
+
+// maxWindowDays 单次展开允许的最大时间窗口
+const maxWindowDays = 366
+
+// ErrInvalidSchedule indicates a schedule or semester request is inconsistent
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ScheduleService handles schedule and semester business logic
+type ScheduleService struct {
+	timetable    config.TimetableConfig
+	loc          *time.Location // 导入ICS时将时间转换为此时区的墙上时间
+	scheduleRepo *postgres.ScheduleRepository
+	semesterRepo *postgres.SemesterRepository
+}
+
+// NewScheduleService creates a new schedule service
+func NewScheduleService(timetable config.TimetableConfig, loc *time.Location, scheduleRepo *postgres.ScheduleRepository, semesterRepo *postgres.SemesterRepository) *ScheduleService {
+	return &ScheduleService{
+		timetable:    timetable,
+		loc:          loc,
+		scheduleRepo: scheduleRepo,
+		semesterRepo: semesterRepo,
+	}
+}
+
+// List retrieves all schedules of a user
+func (s *ScheduleService) List(ctx context.Context, userID int64) ([]*domain.Schedule, error) {
+	schedules, err := s.scheduleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// GetByID retrieves one schedule of a user
+func (s *ScheduleService) GetByID(ctx context.Context, userID, id int64) (*domain.Schedule, error) {
+	return s.scheduleRepo.GetByID(ctx, userID, id)
+}
+
+// Create creates a schedule after validating its recurrence rule and semester
+func (s *ScheduleService) Create(ctx context.Context, userID int64, req *domain.CreateScheduleRequest) (*domain.Schedule, error) {
+	schedule := &domain.Schedule{UserID: userID}
+	if err := s.apply(ctx, schedule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// Update updates a schedule of a user
+func (s *ScheduleService) Update(ctx context.Context, userID, id int64, req *domain.CreateScheduleRequest) (*domain.Schedule, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, schedule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// Delete deletes a schedule of a user
+func (s *ScheduleService) Delete(ctx context.Context, userID, id int64) error {
+	return s.scheduleRepo.Delete(ctx, userID, id)
+}
+
+// Occurrences expands the user's schedules into concrete occurrences in [from, to)
+func (s *ScheduleService) Occurrences(ctx context.Context, userID int64, from, to time.Time) ([]*domain.ScheduleOccurrence, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: window end must be after start", ErrInvalidSchedule)
+	}
+	if to.Sub(from) > maxWindowDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: window cannot exceed %d days", ErrInvalidSchedule, maxWindowDays)
+	}
+
+	schedules, err := s.scheduleRepo.ListInWindow(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	semesters, err := s.semesterMap(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return expandSchedules(schedules, semesters, from, to), nil
+}
+
+// ListSemesters retrieves all semesters of a user
+func (s *ScheduleService) ListSemesters(ctx context.Context, userID int64) ([]*domain.Semester, error) {
+	semesters, err := s.semesterRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list semesters: %w", err)
+	}
+	return semesters, nil
+}
+
+// CreateSemester creates a semester
+func (s *ScheduleService) CreateSemester(ctx context.Context, userID int64, req *domain.CreateSemesterRequest) (*domain.Semester, error) {
+	semester := &domain.Semester{UserID: userID}
+	if err := applySemester(semester, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.semesterRepo.Create(ctx, semester); err != nil {
+		return nil, fmt.Errorf("failed to create semester: %w", err)
+	}
+
+	return semester, nil
+}
+
+// UpdateSemester updates a semester of a user
+func (s *ScheduleService) UpdateSemester(ctx context.Context, userID, id int64, req *domain.CreateSemesterRequest) (*domain.Semester, error) {
+	semester, err := s.semesterRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applySemester(semester, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.semesterRepo.Update(ctx, semester); err != nil {
+		return nil, err
+	}
+
+	return semester, nil
+}
+
+// DeleteSemester deletes a semester of a user
+func (s *ScheduleService) DeleteSemester(ctx context.Context, userID, id int64) error {
+	return s.semesterRepo.Delete(ctx, userID, id)
+}
+
+// semesterMap loads the user's semesters keyed by ID
+func (s *ScheduleService) semesterMap(ctx context.Context, userID int64) (map[int64]*domain.Semester, error) {
+	semesters, err := s.semesterRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list semesters: %w", err)
+	}
+
+	return semestersByID(semesters), nil
+}
+
+// apply copies a request onto a schedule after validation
+func (s *ScheduleService) apply(ctx context.Context, schedule *domain.Schedule, req *domain.CreateScheduleRequest) error {
+	if !req.EndTime.After(req.StartTime) {
+		return fmt.Errorf("%w: end_time must be after start_time", ErrInvalidSchedule)
+	}
+
+	rule := ""
+	if req.IsRecurring {
+		if req.RecurrenceRule == "" {
+			return fmt.Errorf("%w: recurrence_rule is required for recurring schedules", ErrInvalidSchedule)
+		}
+		if _, err := recurrence.Parse(req.RecurrenceRule); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		rule = req.RecurrenceRule
+	}
+
+	if req.SemesterID != nil {
+		if _, err := s.semesterRepo.GetByID(ctx, schedule.UserID, *req.SemesterID); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+	}
+
+	schedule.Title = req.Title
+	schedule.Type = req.Type
+	schedule.StartTime = req.StartTime
+	schedule.EndTime = req.EndTime
+	schedule.Location = req.Location
+	schedule.Description = req.Description
+	schedule.IsRecurring = req.IsRecurring
+	schedule.RecurrenceRule = rule
+	schedule.SemesterID = req.SemesterID
+	schedule.ExceptionDates = req.ExceptionDates
+
+	return nil
+}
+
+// applySemester copies a request onto a semester after validation
+func applySemester(semester *domain.Semester, req *domain.CreateSemesterRequest) error {
+	if req.EndDate.Before(req.StartDate) {
+		return fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidSchedule)
+	}
+	for _, period := range append(append(domain.Holidays{}, req.Holidays...), req.ExamPeriods...) {
+		if period.EndDate.Before(period.StartDate) {
+			return fmt.Errorf("%w: period %q ends before it starts", ErrInvalidSchedule, period.Name)
+		}
+	}
+
+	semester.Name = req.Name
+	semester.StartDate = req.StartDate
+	semester.EndDate = req.EndDate
+	semester.Holidays = req.Holidays
+	semester.ExamPeriods = req.ExamPeriods
+	if semester.Holidays == nil {
+		semester.Holidays = domain.Holidays{}
+	}
+	if semester.ExamPeriods == nil {
+		semester.ExamPeriods = domain.Holidays{}
+	}
+
+	return nil
+}
+
+// expandSchedules 将日程展开为[from, to)内的具体发生，按开始时间排序
+// 重复日程受所属学期起止日期约束，并跳过学期节假日与单独取消的日期；
+// 未填写规则的重复日程按首次发生的星期几每周重复，无法解析的规则按单次日程处理
+func expandSchedules(schedules []*domain.Schedule, semesters map[int64]*domain.Semester, from, to time.Time) []*domain.ScheduleOccurrence {
+	var occurrences []*domain.ScheduleOccurrence
+
+	for _, sch := range schedules {
+		duration := sch.EndTime.Sub(sch.StartTime)
+		starts := []time.Time{sch.StartTime}
+
+		if sch.IsRecurring {
+			value := sch.RecurrenceRule
+			if value == "" {
+				value = recurrence.FreqWeekly
+			}
+			if rule, err := recurrence.Parse(value); err == nil {
+				// 向前多取一个时长，以包含开始于窗口之前、结束于窗口之内的发生
+				starts = rule.Between(sch.StartTime, from.Add(-duration), to, scheduleBounds(sch, semesters[derefID(sch.SemesterID)]))
+			}
+		}
+
+		for _, start := range starts {
+			end := start.Add(duration)
+			if !end.After(from) || !start.Before(to) {
+				continue
+			}
+			occurrences = append(occurrences, &domain.ScheduleOccurrence{
+				ScheduleID: sch.ID,
+				Title:      sch.Title,
+				Type:       sch.Type,
+				Location:   sch.Location,
+				StartTime:  start,
+				EndTime:    end,
+			})
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartTime.Before(occurrences[j].StartTime)
+	})
+
+	return occurrences
+}
+
+// scheduleBounds builds the expansion bounds of a recurring schedule
+func scheduleBounds(sch *domain.Schedule, semester *domain.Semester) recurrence.Bounds {
+	excluded := make(map[string]bool, len(sch.ExceptionDates))
+	for _, d := range sch.ExceptionDates {
+		excluded[d.Format("2006-01-02")] = true
+	}
+
+	bounds := recurrence.Bounds{
+		Excluded: func(day time.Time) bool {
+			if excluded[day.Format("2006-01-02")] {
+				return true
+			}
+			return semester != nil && semester.Holidays.Contains(day)
+		},
+	}
+	if semester != nil {
+		bounds.Start = &semester.StartDate
+		bounds.End = &semester.EndDate
+	}
+
+	return bounds
+}
+
+// derefID returns the value of an optional ID, or 0
+func derefID(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
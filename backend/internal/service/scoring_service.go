@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/taxonomy"
+)
+
+// 不限专业的常见写法
+var unrestrictedMajors = []string{"全部专业", "不限专业", "不限", "全部"}
+
+// busyHoursPerDay 一天内被日程占满视为时间成本为1的小时数
+const busyHoursPerDay = 8.0
+
+// ScoringService computes accessibility and relevance scores for user-opportunity pairs
+type ScoringService struct {
+	weights      config.ScoringConfig
+	userRepo     *postgres.UserRepository
+	profileRepo  *postgres.ProfileRepository
+	oppRepo      *postgres.OpportunityRepository
+	scheduleRepo *postgres.ScheduleRepository
+	semesterRepo *postgres.SemesterRepository
+	uoRepo       *postgres.UserOpportunityRepository
+	taxonomy     *TaxonomyService // 专业层级匹配，可为nil
+}
+
+// ScoreInput holds everything needed to score one user-opportunity pair
+type ScoreInput struct {
+	User              *domain.User
+	Profile           *domain.UserProfile
+	Opportunity       *domain.Opportunity
+	Schedules         []*domain.Schedule
+	Semesters         []*domain.Semester // 学期边界、节假日与考试周
+	PeerParticipation float64            // 同专业参与率 0-1
+}
+
+// NewScoringService creates a new scoring service
+func NewScoringService(
+	weights config.ScoringConfig,
+	userRepo *postgres.UserRepository,
+	profileRepo *postgres.ProfileRepository,
+	oppRepo *postgres.OpportunityRepository,
+	scheduleRepo *postgres.ScheduleRepository,
+	semesterRepo *postgres.SemesterRepository,
+	uoRepo *postgres.UserOpportunityRepository,
+	taxonomy *TaxonomyService,
+) *ScoringService {
+	return &ScoringService{
+		weights:      weights,
+		userRepo:     userRepo,
+		profileRepo:  profileRepo,
+		oppRepo:      oppRepo,
+		scheduleRepo: scheduleRepo,
+		semesterRepo: semesterRepo,
+		uoRepo:       uoRepo,
+		taxonomy:     taxonomy,
+	}
+}
+
+// Compute scores a user-opportunity pair without touching the database
+func (s *ScoringService) Compute(in *ScoreInput) domain.ScoreDetail {
+	profile := in.Profile
+	if profile == nil {
+		profile = &domain.UserProfile{}
+	}
+
+	// 历史数据可能未规范化，比较前统一映射到规范名称
+	idx := s.taxonomy.Index()
+	skills := idx.NormalizeAll(taxonomy.KindSkill, profile.Skills)
+
+	access := domain.AccessibilityDetail{
+		Eligibility: round2(eligibilityScore(in.User, profile, in.Opportunity, idx)),
+		SkillsMatch: round2(skillsMatchScore(skills, idx.NormalizeAll(taxonomy.KindSkill, in.Opportunity.Requirements.Skills))),
+		TimeCost:    round2(timeCostScore(in.Opportunity, in.Schedules, in.Semesters)),
+	}
+	access.Total = accessibilityTotal(access, s.weights.Accessibility)
+
+	relevance := domain.RelevanceDetail{
+		MajorMatch:        round2(majorMatchScore(in.User.Major, opportunityMajors(in.Opportunity), idx)),
+		SkillOverlap:      round2(skillOverlapScore(skills, idx.NormalizeAll(taxonomy.KindSkill, opportunitySkillTerms(in.Opportunity)))),
+		CareerAlignment:   round2(careerAlignmentScore(profile.Interests, in.Opportunity)),
+		PeerParticipation: round2(clamp01(in.PeerParticipation)),
+	}
+	relevance.Total = relevanceTotal(relevance, s.weights.Relevance)
+
+	return domain.ScoreDetail{
+		Accessibility: access,
+		Relevance:     relevance,
+	}
+}
+
+// Contributions returns how many points each component adds to its dimension total (0-100)
+// Keys are the JSON names of the ScoreDetail fields; time_cost contributes the free-time share
+func (s *ScoringService) Contributions(d domain.ScoreDetail) map[string]float64 {
+	result := make(map[string]float64, 7)
+
+	a := s.weights.Accessibility
+	if sum := a.Sum(); sum > 0 && d.Accessibility.Eligibility > 0 {
+		result["eligibility"] = 100 * a.Eligibility * d.Accessibility.Eligibility / sum
+		result["skills_match"] = 100 * a.SkillsMatch * d.Accessibility.SkillsMatch / sum
+		result["time_cost"] = 100 * a.TimeCost * (1 - d.Accessibility.TimeCost) / sum
+	}
+
+	r := s.weights.Relevance
+	if sum := r.Sum(); sum > 0 {
+		result["major_match"] = 100 * r.MajorMatch * d.Relevance.MajorMatch / sum
+		result["skill_overlap"] = 100 * r.SkillOverlap * d.Relevance.SkillOverlap / sum
+		result["career_alignment"] = 100 * r.CareerAlignment * d.Relevance.CareerAlignment / sum
+		result["peer_participation"] = 100 * r.PeerParticipation * d.Relevance.PeerParticipation / sum
+	}
+
+	return result
+}
+
+// ScoreAndSave loads the user, profile, opportunity and schedules, computes the
+// scores and stores them on the user_opportunities row
+func (s *ScoringService) ScoreAndSave(ctx context.Context, userID, opportunityID int64) (*domain.UserOpportunity, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opp, err := s.oppRepo.GetByID(ctx, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 用户尚未填写画像时按空画像评分
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if err.Error() != "profile not found" {
+			return nil, fmt.Errorf("failed to get profile: %w", err)
+		}
+		profile = &domain.UserProfile{UserID: userID}
+	}
+
+	schedules, err := s.scheduleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	semesters, err := s.semesterRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list semesters: %w", err)
+	}
+
+	peerRate, err := s.uoRepo.PeerParticipationRate(ctx, opportunityID, user.Major)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute peer participation: %w", err)
+	}
+
+	detail := s.Compute(&ScoreInput{
+		User:              user,
+		Profile:           profile,
+		Opportunity:       opp,
+		Schedules:         schedules,
+		Semesters:         semesters,
+		PeerParticipation: peerRate,
+	})
+
+	uo := &domain.UserOpportunity{
+		UserID:             userID,
+		OpportunityID:      opportunityID,
+		AccessibilityScore: detail.Accessibility.Total,
+		RelevanceScore:     detail.Relevance.Total,
+		ScoreDetails:       detail,
+	}
+
+	if err := s.uoRepo.UpsertScores(ctx, uo); err != nil {
+		return nil, fmt.Errorf("failed to save scores: %w", err)
+	}
+
+	return uo, nil
+}
+
+// accessibilityTotal 计算匹配度总分（0-100）
+// 硬性门槛不满足时总分直接为0；时间成本越高得分越低
+func accessibilityTotal(d domain.AccessibilityDetail, w config.AccessibilityWeights) float64 {
+	sum := w.Sum()
+	if d.Eligibility == 0 || sum == 0 {
+		return 0
+	}
+
+	score := w.Eligibility*d.Eligibility + w.SkillsMatch*d.SkillsMatch + w.TimeCost*(1-d.TimeCost)
+	return round2(100 * score / sum)
+}
+
+// relevanceTotal 计算专业度总分（0-100）
+func relevanceTotal(d domain.RelevanceDetail, w config.RelevanceWeights) float64 {
+	sum := w.Sum()
+	if sum == 0 {
+		return 0
+	}
+
+	score := w.MajorMatch*d.MajorMatch + w.SkillOverlap*d.SkillOverlap +
+		w.CareerAlignment*d.CareerAlignment + w.PeerParticipation*d.PeerParticipation
+	return round2(100 * score / sum)
+}
+
+// eligibilityScore 计算硬性门槛得分（0-1）
+// 年级或专业不符合要求时为0，否则为已满足证书要求（含分数线）的比例
+func eligibilityScore(user *domain.User, profile *domain.UserProfile, opp *domain.Opportunity, idx *taxonomy.Index) float64 {
+	req := opp.Requirements
+
+	if len(req.Grade) > 0 && !containsInt(req.Grade, user.Grade) {
+		return 0
+	}
+
+	if len(req.Major) > 0 && majorMatchScore(user.Major, req.Major, idx) == 0 {
+		return 0
+	}
+
+	if len(req.Certificates) == 0 {
+		return 1
+	}
+
+	met := 0
+	for _, requirement := range req.Certificates {
+		if _, unmet := certificateGap(requirement, profile.Certificates); !unmet {
+			met++
+		}
+	}
+
+	return float64(met) / float64(len(req.Certificates))
+}
+
+// skillsMatchScore 计算技能要求满足度（0-1），无技能要求时为1
+func skillsMatchScore(have, required []string) float64 {
+	if len(required) == 0 {
+		return 1
+	}
+	return matchRatio(have, required)
+}
+
+// timeCostScore 计算时间冲突成本（0-1），越高表示与日程冲突越严重
+// 详见 timeConflicts：活动当天、截止准备期、开始当天的加权占用比例，考试与考试周视为占满
+func timeCostScore(opp *domain.Opportunity, schedules []*domain.Schedule, semesters []*domain.Semester) float64 {
+	cost, _, _ := timeConflicts(opp, schedules, semestersByID(semesters), semesters)
+	return cost
+}
+
+// majorMatchScore 计算专业匹配度（0-1）
+// 完全一致为1，互相包含（如"计算机"与"计算机科学与技术"）为0.8；
+// 未限定专业或不限专业时为0.5
+func majorMatchScore(major string, targets []string, idx *taxonomy.Index) float64 {
+	if len(targets) == 0 {
+		return 0.5
+	}
+
+	raw := major
+	major = normalizeTerm(major)
+	best := 0.0
+	unrestricted := false
+	for _, target := range targets {
+		t := normalizeTerm(target)
+		switch {
+		case t == "":
+			continue
+		case isUnrestrictedMajor(t):
+			unrestricted = true
+		case major == "":
+			continue
+		}
+
+		// 双方都在分类体系中时按层级判断，否则退化为字符串匹配
+		switch idx.Relation(taxonomy.KindMajor, target, raw) {
+		case taxonomy.RelationSame:
+			return 1
+		case taxonomy.RelationAncestor, taxonomy.RelationDescendant:
+			best = math.Max(best, 0.8)
+			continue
+		case taxonomy.RelationUnrelated:
+			continue
+		}
+
+		switch {
+		case t == major:
+			return 1
+		case strings.Contains(major, t) || strings.Contains(t, major):
+			best = math.Max(best, 0.8)
+		}
+	}
+
+	if best == 0 && unrestricted {
+		return 0.5
+	}
+	return best
+}
+
+// skillOverlapScore 计算技能重叠度（0-1），使用重叠系数 |A∩B| / min(|A|,|B|)
+func skillOverlapScore(have, terms []string) float64 {
+	a, b := termSet(have), termSet(terms)
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for term := range a {
+		if b[term] {
+			common++
+		}
+	}
+
+	return float64(common) / math.Min(float64(len(a)), float64(len(b)))
+}
+
+// careerAlignmentScore 计算职业发展相关性（0-1）
+// 统计出现在机会标题、类型、标签或描述中的兴趣数量，命中3个即为满分
+func careerAlignmentScore(interests []string, opp *domain.Opportunity) float64 {
+	set := termSet(interests)
+	if len(set) == 0 {
+		return 0
+	}
+
+	text := normalizeTerm(strings.Join(append([]string{opp.Title, opp.Type, opp.Description}, opp.Tags...), " "))
+
+	hits := 0
+	for interest := range set {
+		if strings.Contains(text, interest) {
+			hits++
+		}
+	}
+
+	return math.Min(1, float64(hits)/math.Min(float64(len(set)), 3))
+}
+
+// opportunityMajors 合并机会的目标专业和专业要求
+func opportunityMajors(opp *domain.Opportunity) []string {
+	majors := make([]string, 0, len(opp.TargetMajors)+len(opp.Requirements.Major))
+	majors = append(majors, opp.TargetMajors...)
+	return append(majors, opp.Requirements.Major...)
+}
+
+// opportunitySkillTerms 合并机会的技能要求和标签
+func opportunitySkillTerms(opp *domain.Opportunity) []string {
+	terms := make([]string, 0, len(opp.Requirements.Skills)+len(opp.Tags))
+	terms = append(terms, opp.Requirements.Skills...)
+	return append(terms, opp.Tags...)
+}
+
+// matchRatio 返回required中被have覆盖的比例（忽略大小写，按名称精确匹配）
+// 同义词由调用方先经分类体系规范化，这里不做子串匹配，避免"Java"覆盖"JavaScript"
+func matchRatio(have, required []string) float64 {
+	req := termSet(required)
+	if len(req) == 0 {
+		return 1
+	}
+
+	owned := termSet(have)
+	matched := 0
+	for r := range req {
+		if owned[r] {
+			matched++
+		}
+	}
+
+	return float64(matched) / float64(len(req))
+}
+
+// termSet 将字符串列表规范化为集合
+func termSet(terms []string) map[string]bool {
+	set := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		if t := normalizeTerm(term); t != "" {
+			set[t] = true
+		}
+	}
+	return set
+}
+
+// normalizeTerm 统一大小写并去除首尾空白
+func normalizeTerm(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// isUnrestrictedMajor 判断是否为"不限专业"
+func isUnrestrictedMajor(major string) bool {
+	for _, m := range unrestrictedMajors {
+		if major == m {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
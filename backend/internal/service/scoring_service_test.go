@@ -0,0 +1,348 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/taxonomy"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEligibilityScore(t *testing.T) {
+	user := &domain.User{Grade: 2, Major: "计算机科学与技术"}
+	profile := &domain.UserProfile{Certificates: []domain.Cert{{Name: "CET-6", Score: 480}}}
+
+	tests := []struct {
+		name string
+		req  domain.Requirements
+		want float64
+	}{
+		{"no requirements", domain.Requirements{}, 1},
+		{"grade not allowed", domain.Requirements{Grade: []int{3, 4}}, 0},
+		{"grade allowed", domain.Requirements{Grade: []int{1, 2}}, 1},
+		{"major mismatch", domain.Requirements{Major: []string{"临床医学"}}, 0},
+		{"major partial match", domain.Requirements{Major: []string{"计算机"}}, 1},
+		{"unrestricted major", domain.Requirements{Major: []string{"全部专业"}}, 1},
+		{"half certificates", domain.Requirements{Certificates: []string{"cet-6", "软考中级"}}, 0.5},
+		{"certificate score met", domain.Requirements{Certificates: []string{"CET-6 425分"}}, 1},
+		{"certificate score unmet", domain.Requirements{Certificates: []string{"CET-6>=500"}}, 0},
+		{"certificate prefix is not a match", domain.Requirements{Certificates: []string{"CET"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opp := &domain.Opportunity{Requirements: tt.req}
+			if got := eligibilityScore(user, profile, opp, nil); !almostEqual(got, tt.want) {
+				t.Errorf("eligibilityScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCertRequirement(t *testing.T) {
+	tests := []struct {
+		requirement string
+		wantName    string
+		wantScore   float64
+	}{
+		{"CET-6", "CET-6", 0},
+		{"CET 6", "CET 6", 0},
+		{"CET-6 425", "CET-6 425", 0},
+		{"CET-6 425分", "CET-6", 425},
+		{"CET-6>=425", "CET-6", 425},
+		{"CET-6 ≥ 425", "CET-6", 425},
+		{"雅思：6分", "雅思", 6},
+		{"雅思6分", "雅思", 6},
+		{"雅思：6.5分", "雅思", 6.5},
+		{"雅思≥6.5", "雅思", 6.5},
+		{"雅思 6.5", "雅思", 6.5},
+		{"雅思6.5", "雅思", 6.5},
+		{"托福 100", "托福 100", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requirement, func(t *testing.T) {
+			name, score := parseCertRequirement(tt.requirement)
+			if name != tt.wantName || score != tt.wantScore {
+				t.Errorf("parseCertRequirement(%q) = (%q, %v), want (%q, %v)", tt.requirement, name, score, tt.wantName, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestCertificateGap(t *testing.T) {
+	certs := []domain.Cert{{Name: "PMP"}, {Name: "IELTS", Score: 6.5}, {Name: "CET-6", Score: 480}}
+
+	tests := []struct {
+		requirement string
+		wantOwned   bool
+		wantCurrent float64
+		wantUnmet   bool
+	}{
+		{"PMP", true, 0, false},
+		{"pmp", true, 0, false},
+		{"PMP-ACP", false, 0, true},
+		{"CET", false, 0, true},
+		{"CET-6 425分", true, 480, false},
+		{"IELTS 6.5", true, 6.5, false},
+		{"IELTS>=7", true, 6.5, true},
+		{"I E L T S ≥ 6", true, 6.5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requirement, func(t *testing.T) {
+			gap, unmet := certificateGap(tt.requirement, certs)
+			if gap.Owned != tt.wantOwned || gap.CurrentScore != tt.wantCurrent || unmet != tt.wantUnmet {
+				t.Errorf("certificateGap(%q) = (owned %v, current %v, unmet %v), want (owned %v, current %v, unmet %v)",
+					tt.requirement, gap.Owned, gap.CurrentScore, unmet, tt.wantOwned, tt.wantCurrent, tt.wantUnmet)
+			}
+		})
+	}
+}
+
+func TestSkillsMatchScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		have     []string
+		required []string
+		want     float64
+	}{
+		{"no requirements", []string{"Go"}, nil, 1},
+		{"no skills", nil, []string{"Go"}, 0},
+		{"case insensitive", []string{"python", "go"}, []string{"Python", "Java"}, 0.5},
+		{"all covered", []string{"Python", "SQL"}, []string{"python"}, 1},
+		{"prefix is not a match", []string{"Java"}, []string{"JavaScript"}, 0},
+		{"substring is not a match", []string{"Go"}, []string{"MongoDB", "Algorithm"}, 0},
+		{"longer skill does not cover shorter", []string{"JavaScript"}, []string{"Java"}, 0},
+		{"single letter", []string{"C"}, []string{"C", "C++", "C#"}, 1.0 / 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := skillsMatchScore(tt.have, tt.required); !almostEqual(got, tt.want) {
+				t.Errorf("skillsMatchScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeSkillSynonyms(t *testing.T) {
+	idx := taxonomy.NewIndex([]*domain.TaxonomyTerm{
+		{ID: 1, Kind: taxonomy.KindSkill, Code: "python", Name: "Python", Synonyms: []string{"Python3", "Python编程"}, IsActive: true},
+		{ID: 2, Kind: taxonomy.KindSkill, Code: "javascript", Name: "JavaScript", Synonyms: []string{"JS"}, IsActive: true},
+		{ID: 3, Kind: taxonomy.KindSkill, Code: "java", Name: "Java", IsActive: true},
+	})
+	svc := &ScoringService{weights: config.DefaultScoringConfig(), taxonomy: &TaxonomyService{index: idx}}
+
+	detail := svc.Compute(&ScoreInput{
+		User:    &domain.User{Grade: 2},
+		Profile: &domain.UserProfile{Skills: []string{"python3", "Java"}},
+		Opportunity: &domain.Opportunity{
+			Requirements: domain.Requirements{Skills: []string{"Python编程", "JS"}},
+		},
+	})
+
+	// Python3 与 Python编程 是同义词；Java 不覆盖 JavaScript
+	if detail.Accessibility.SkillsMatch != 0.5 {
+		t.Errorf("SkillsMatch = %v, want 0.5", detail.Accessibility.SkillsMatch)
+	}
+	if detail.Relevance.SkillOverlap != 0.5 {
+		t.Errorf("SkillOverlap = %v, want 0.5", detail.Relevance.SkillOverlap)
+	}
+}
+
+func TestMajorMatchScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		major   string
+		targets []string
+		want    float64
+	}{
+		{"no targets", "数学", nil, 0.5},
+		{"exact", "数学", []string{"物理", "数学"}, 1},
+		{"partial", "计算机科学与技术", []string{"计算机"}, 0.8},
+		{"unrestricted", "历史学", []string{"全部专业"}, 0.5},
+		{"mismatch", "历史学", []string{"电子"}, 0},
+		{"empty major", "", []string{"电子"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := majorMatchScore(tt.major, tt.targets, nil); !almostEqual(got, tt.want) {
+				t.Errorf("majorMatchScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMajorMatchScoreWithTaxonomy(t *testing.T) {
+	engineering, category := int64(1), int64(2)
+	idx := taxonomy.NewIndex([]*domain.TaxonomyTerm{
+		{ID: 1, Kind: taxonomy.KindMajor, Code: "08", Name: "工学", Level: taxonomy.LevelDiscipline, IsActive: true},
+		{ID: 2, Kind: taxonomy.KindMajor, Code: "0809", Name: "计算机类", Level: taxonomy.LevelCategory, ParentID: &engineering, Synonyms: []string{"计算机"}, IsActive: true},
+		{ID: 3, Kind: taxonomy.KindMajor, Code: "080901", Name: "计算机科学与技术", Level: taxonomy.LevelMajor, ParentID: &category, Synonyms: []string{"计科"}, IsActive: true},
+		{ID: 4, Kind: taxonomy.KindMajor, Code: "080902", Name: "软件工程", Level: taxonomy.LevelMajor, ParentID: &category, IsActive: true},
+		{ID: 5, Kind: taxonomy.KindMajor, Code: "100201K", Name: "临床医学", Level: taxonomy.LevelMajor, IsActive: true},
+	})
+
+	tests := []struct {
+		name    string
+		major   string
+		targets []string
+		want    float64
+	}{
+		{"synonym", "计科", []string{"计算机科学与技术"}, 1},
+		{"category target", "软件工程", []string{"计算机类"}, 0.8},
+		{"discipline target", "软件工程", []string{"工学"}, 0.8},
+		{"sibling majors", "软件工程", []string{"计算机科学与技术"}, 0},
+		{"unrelated", "临床医学", []string{"计算机"}, 0},
+		{"unknown falls back to substring", "软件工程（中外合作）", []string{"软件工程"}, 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := majorMatchScore(tt.major, tt.targets, idx); !almostEqual(got, tt.want) {
+				t.Errorf("majorMatchScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkillOverlapScore(t *testing.T) {
+	if got := skillOverlapScore(nil, []string{"Go"}); got != 0 {
+		t.Errorf("empty skills: got %v, want 0", got)
+	}
+
+	// 2 common terms, smaller set has 2 elements
+	got := skillOverlapScore([]string{"Go", "SQL"}, []string{"go", "sql", "Docker", "K8s"})
+	if !almostEqual(got, 1) {
+		t.Errorf("full overlap of smaller set: got %v, want 1", got)
+	}
+
+	got = skillOverlapScore([]string{"Go", "Rust", "C"}, []string{"go", "java"})
+	if !almostEqual(got, 0.5) {
+		t.Errorf("partial overlap: got %v, want 0.5", got)
+	}
+}
+
+func TestCareerAlignmentScore(t *testing.T) {
+	opp := &domain.Opportunity{
+		Title: "字节跳动后端开发实习",
+		Type:  "实习",
+		Tags:  []string{"后端", "Go"},
+	}
+
+	tests := []struct {
+		name      string
+		interests []string
+		want      float64
+	}{
+		{"no interests", nil, 0},
+		{"one of one", []string{"后端"}, 1},
+		{"one of two", []string{"后端", "设计"}, 0.5},
+		{"capped at three", []string{"后端", "go", "实习", "金融", "设计"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := careerAlignmentScore(tt.interests, opp); !almostEqual(got, tt.want) {
+				t.Errorf("careerAlignmentScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeCostScore(t *testing.T) {
+	event := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC) // Wednesday
+	opp := &domain.Opportunity{EventDate: &event}
+
+	if got := timeCostScore(opp, nil, nil); got != 0 {
+		t.Errorf("no schedules: got %v, want 0", got)
+	}
+
+	schedules := []*domain.Schedule{
+		// 4 hours on the event day
+		{StartTime: time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 12, 12, 0, 0, 0, time.UTC)},
+		// recurring Wednesday class, 2 hours
+		{StartTime: time.Date(2025, 2, 26, 14, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 2, 26, 16, 0, 0, 0, time.UTC), IsRecurring: true},
+		// recurring Monday class, ignored
+		{StartTime: time.Date(2025, 2, 24, 14, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 2, 24, 16, 0, 0, 0, time.UTC), IsRecurring: true},
+	}
+
+	if got := timeCostScore(opp, schedules, nil); !almostEqual(got, 0.75) {
+		t.Errorf("timeCostScore() = %v, want 0.75", got)
+	}
+
+	// Deadline preparation window (Mar 7-9) is free: (1*0.75 + 0.5*0) / 1.5
+	deadline := time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC) // Sunday
+	opp.Deadline = &deadline
+	if got := timeCostScore(opp, schedules, nil); !almostEqual(got, 0.5) {
+		t.Errorf("timeCostScore() with deadline = %v, want 0.5", got)
+	}
+}
+
+func TestTotals(t *testing.T) {
+	weights := config.DefaultScoringConfig()
+
+	access := domain.AccessibilityDetail{Eligibility: 1, SkillsMatch: 0.5, TimeCost: 0.5}
+	// 0.5*1 + 0.3*0.5 + 0.2*0.5 = 0.75
+	if got := accessibilityTotal(access, weights.Accessibility); !almostEqual(got, 75) {
+		t.Errorf("accessibilityTotal() = %v, want 75", got)
+	}
+
+	access.Eligibility = 0
+	if got := accessibilityTotal(access, weights.Accessibility); got != 0 {
+		t.Errorf("ineligible accessibilityTotal() = %v, want 0", got)
+	}
+
+	relevance := domain.RelevanceDetail{MajorMatch: 1, SkillOverlap: 1, CareerAlignment: 0, PeerParticipation: 0}
+	// 0.35 + 0.25 = 0.6
+	if got := relevanceTotal(relevance, weights.Relevance); !almostEqual(got, 60) {
+		t.Errorf("relevanceTotal() = %v, want 60", got)
+	}
+
+	// Weights do not need to sum to 1
+	custom := config.RelevanceWeights{MajorMatch: 2, SkillOverlap: 2}
+	if got := relevanceTotal(relevance, custom); !almostEqual(got, 100) {
+		t.Errorf("relevanceTotal() with custom weights = %v, want 100", got)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	svc := &ScoringService{weights: config.DefaultScoringConfig()}
+
+	detail := svc.Compute(&ScoreInput{
+		User: &domain.User{Grade: 3, Major: "计算机科学与技术"},
+		Opportunity: &domain.Opportunity{
+			Title:        "全国大学生数学建模竞赛",
+			Type:         "竞赛",
+			TargetMajors: []string{"计算机"},
+			Requirements: domain.Requirements{Skills: []string{"Python", "MATLAB"}},
+			Tags:         []string{"建模"},
+		},
+		PeerParticipation: 1.5,
+	})
+
+	if detail.Accessibility.Eligibility != 1 {
+		t.Errorf("Eligibility = %v, want 1", detail.Accessibility.Eligibility)
+	}
+	if detail.Accessibility.SkillsMatch != 0 {
+		t.Errorf("SkillsMatch with nil profile = %v, want 0", detail.Accessibility.SkillsMatch)
+	}
+	if detail.Relevance.MajorMatch != 0.8 {
+		t.Errorf("MajorMatch = %v, want 0.8", detail.Relevance.MajorMatch)
+	}
+	if detail.Relevance.PeerParticipation != 1 {
+		t.Errorf("PeerParticipation should be clamped to 1, got %v", detail.Relevance.PeerParticipation)
+	}
+	// 0.5*1 + 0.3*0 + 0.2*1 = 0.7
+	if detail.Accessibility.Total != 70 {
+		t.Errorf("Accessibility.Total = %v, want 70", detail.Accessibility.Total)
+	}
+}
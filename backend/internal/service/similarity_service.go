@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+const (
+	// fallbackCandidateLimit 未启用pgvector时在Go中参与相似度计算的候选上限
+	fallbackCandidateLimit = 1000
+	// backfillBatchSize 每轮补齐向量的机会/简历数量上限
+	backfillBatchSize = 100
+	// backfillLockKey 多实例部署时保证同一时刻只有一个实例在补齐向量
+	backfillLockKey = "similarity:backfill"
+	// pgvectorRetryInterval 检测pgvector扩展失败后的重试间隔
+	pgvectorRetryInterval = time.Minute
+)
+
+// ErrVectorUnavailable indicates the source text has not been vectorized yet
+var ErrVectorUnavailable = errors.New("vector not available")
+
+// SimilarityService provides semantic retrieval over opportunity and resume vectors
+type SimilarityService struct {
+	cfg         config.NLPServiceConfig
+	db          *postgres.DB
+	oppRepo     *postgres.OpportunityRepository
+	profileRepo *postgres.ProfileRepository
+	nlpClient   NLPClient
+	locker      *redis.Client
+
+	// pgvector探测结果；探测出错时不缓存，pgvectorRetryAt之后重新探测
+	pgvectorMu      sync.Mutex
+	pgvectorChecked bool
+	pgvector        bool
+	pgvectorRetryAt time.Time
+}
+
+// NewSimilarityService creates a new similarity service
+func NewSimilarityService(cfg config.NLPServiceConfig, db *postgres.DB, oppRepo *postgres.OpportunityRepository, profileRepo *postgres.ProfileRepository, nlpClient NLPClient, locker *redis.Client) *SimilarityService {
+	return &SimilarityService{
+		cfg:         cfg,
+		db:          db,
+		oppRepo:     oppRepo,
+		profileRepo: profileRepo,
+		nlpClient:   nlpClient,
+		locker:      locker,
+	}
+}
+
+// Start periodically vectorizes opportunities and resumes that have no vector yet
+// until ctx is cancelled
+func (s *SimilarityService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.GetBackfillInterval())
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunBackfill(ctx); err != nil && ctx.Err() == nil {
+			logger.Errorf("Vector backfill failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunBackfill runs one backfill round for opportunities and resumes
+func (s *SimilarityService) RunBackfill(ctx context.Context) error {
+	locked, err := s.locker.Lock(ctx, backfillLockKey, s.cfg.GetBackfillInterval())
+	if err != nil {
+		return fmt.Errorf("failed to acquire backfill lock: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer func() {
+		if err := s.locker.Unlock(context.Background(), backfillLockKey); err != nil {
+			logger.Warnf("failed to release backfill lock: %v", err)
+		}
+	}()
+
+	opportunities, err := s.BackfillVectors(ctx, backfillBatchSize)
+	if err != nil {
+		return err
+	}
+	resumes, err := s.BackfillResumeVectors(ctx, backfillBatchSize)
+	if err != nil {
+		return err
+	}
+
+	if opportunities > 0 || resumes > 0 {
+		logger.Infof("Vector backfill: %d opportunities, %d resumes", opportunities, resumes)
+	}
+	return nil
+}
+
+// SimilarOpportunities returns the opportunities whose descriptions are closest to the given one
+func (s *SimilarityService) SimilarOpportunities(ctx context.Context, opportunityID int64, limit int) ([]*domain.SimilarOpportunity, error) {
+	opp, err := s.oppRepo.GetByID(ctx, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	vector := opp.DescriptionVector
+	if len(vector) == 0 {
+		// 尚未向量化时尝试即时向量化并回写
+		vector, err = s.VectorizeOpportunity(ctx, opp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.nearest(ctx, vector, opportunityID, limit)
+}
+
+// ResumeMatches returns the opportunities closest to the user's resume
+func (s *SimilarityService) ResumeMatches(ctx context.Context, userID int64, limit int) ([]*domain.SimilarOpportunity, error) {
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(profile.ResumeVector) == 0 {
+		return nil, ErrVectorUnavailable
+	}
+
+	return s.nearest(ctx, profile.ResumeVector, 0, limit)
+}
+
+// VectorizeOpportunity computes the description vector of an opportunity and writes it back
+func (s *SimilarityService) VectorizeOpportunity(ctx context.Context, opp *domain.Opportunity) ([]float32, error) {
+	if s.nlpClient == nil {
+		return nil, ErrVectorUnavailable
+	}
+
+	vector, err := s.nlpClient.VectorizeText(ctx, opportunityText(opp))
+	if err != nil {
+		return nil, fmt.Errorf("failed to vectorize opportunity: %w", err)
+	}
+
+	if err := s.oppRepo.UpdateDescriptionVector(ctx, opp.ID, vector); err != nil {
+		return nil, fmt.Errorf("failed to save description vector: %w", err)
+	}
+
+	opp.DescriptionVector = vector
+	return vector, nil
+}
+
+// BackfillVectors vectorizes up to batchSize opportunities that have no description vector
+// Returns the number of opportunities vectorized
+func (s *SimilarityService) BackfillVectors(ctx context.Context, batchSize int) (int, error) {
+	if s.nlpClient == nil {
+		return 0, ErrVectorUnavailable
+	}
+
+	opportunities, err := s.oppRepo.ListMissingVectors(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list opportunities without vectors: %w", err)
+	}
+
+	count := 0
+	for _, opp := range opportunities {
+		if _, err := s.VectorizeOpportunity(ctx, opp); err != nil {
+			logger.Warnf("failed to vectorize opportunity %d: %v", opp.ID, err)
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// BackfillResumeVectors vectorizes up to batchSize resumes that have no resume vector
+// Returns the number of resumes vectorized
+func (s *SimilarityService) BackfillResumeVectors(ctx context.Context, batchSize int) (int, error) {
+	if s.nlpClient == nil {
+		return 0, ErrVectorUnavailable
+	}
+
+	profiles, err := s.profileRepo.ListMissingResumeVectors(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list resumes without vectors: %w", err)
+	}
+
+	count := 0
+	for _, profile := range profiles {
+		vector, err := s.nlpClient.VectorizeText(ctx, profile.ResumeText)
+		if err != nil {
+			logger.Warnf("failed to vectorize resume of user %d: %v", profile.UserID, err)
+			continue
+		}
+		if err := s.profileRepo.UpdateResumeVector(ctx, profile.UserID, vector); err != nil {
+			logger.Warnf("failed to save resume vector of user %d: %v", profile.UserID, err)
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// nearest finds the opportunities closest to vector, excluding excludeID
+func (s *SimilarityService) nearest(ctx context.Context, vector []float32, excludeID int64, limit int) ([]*domain.SimilarOpportunity, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if s.usePgvector(ctx) {
+		opportunities, similarities, err := s.oppRepo.NearestByVector(ctx, vector, excludeID, limit)
+		if err == nil {
+			results := make([]*domain.SimilarOpportunity, len(opportunities))
+			for i, opp := range opportunities {
+				results[i] = &domain.SimilarOpportunity{Opportunity: opp, Similarity: round4(similarities[i])}
+			}
+			return results, nil
+		}
+		logger.Warnf("pgvector search failed, falling back to in-process similarity: %v", err)
+	}
+
+	candidates, err := s.oppRepo.ListVectorCandidates(ctx, len(vector), excludeID, fallbackCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vector candidates: %w", err)
+	}
+
+	results := make([]*domain.SimilarOpportunity, 0, len(candidates))
+	for _, opp := range candidates {
+		results = append(results, &domain.SimilarOpportunity{
+			Opportunity: opp,
+			Similarity:  round4(cosineSimilarity(vector, opp.DescriptionVector)),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Similarity > results[j].Similarity
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// usePgvector reports whether the pgvector extension is installed. A successful
+// probe is cached; a failed one is retried after pgvectorRetryInterval
+func (s *SimilarityService) usePgvector(ctx context.Context) bool {
+	s.pgvectorMu.Lock()
+	defer s.pgvectorMu.Unlock()
+
+	if s.pgvectorChecked || time.Now().Before(s.pgvectorRetryAt) {
+		return s.pgvector
+	}
+
+	installed, err := s.db.ExtensionInstalled(ctx, "vector")
+	if err != nil {
+		logger.Warnf("failed to detect pgvector extension: %v", err)
+		s.pgvectorRetryAt = time.Now().Add(pgvectorRetryInterval)
+		return false
+	}
+	s.pgvectorChecked = true
+	s.pgvector = installed
+	logger.Infof("pgvector extension available: %v", installed)
+	return s.pgvector
+}
+
+// opportunityText 拼接用于向量化的机会文本
+func opportunityText(opp *domain.Opportunity) string {
+	parts := []string{opp.Title, opp.Description}
+	if len(opp.Tags) > 0 {
+		parts = append(parts, strings.Join(opp.Tags, " "))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// cosineSimilarity 计算两个向量的余弦相似度，维度不一致或零向量时为0
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		normA += x * x
+		normB += y * y
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
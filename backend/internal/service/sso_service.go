@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/internal/sso"
+	"github.com/unifocus/backend/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ssoStateKey 登录发起时保存的 state，回调时取出并删除，防止CSRF与重放
+const ssoStateKey = "auth:sso:state:%s"
+
+// SSOStateTTL 从跳转到身份提供方到回调的最长时间，也是绑定 state 的浏览器Cookie的有效期
+const SSOStateTTL = 10 * time.Minute
+
+var (
+	// ErrSSOProviderNotFound indicates no campus SSO provider is configured with the given ID
+	ErrSSOProviderNotFound = errors.New("sso provider not found")
+	// ErrInvalidSSOState indicates the callback state is unknown, expired, belongs to another
+	// provider or was not started by the same browser
+	ErrInvalidSSOState = errors.New("invalid or expired sso state")
+	// ErrSSOEmailNotVerified indicates the identity provider did not vouch for the account's email,
+	// so it can neither be linked to an existing user nor used to create one
+	ErrSSOEmailNotVerified = errors.New("sso account has no verified email")
+)
+
+// ssoState 一次登录跳转的上下文
+type ssoState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+}
+
+// ssoProvider 配置与协议实现
+type ssoProvider struct {
+	cfg  config.SSOProviderConfig
+	impl sso.Provider
+}
+
+// SSOService handles campus single sign-on: it links provider accounts to users
+// by verified email, provisions new users from identity attributes and issues
+// the usual UniFocus tokens
+type SSOService struct {
+	baseURL      string
+	providers    map[string]*ssoProvider
+	order        []string
+	userRepo     *postgres.UserRepository
+	identityRepo *postgres.UserIdentityRepository
+	auth         *AuthService
+	rdb          *redis.Client
+	taxonomy     *TaxonomyService
+}
+
+// NewSSOService creates a new SSO service for the providers configured in auth.sso
+func NewSSOService(
+	cfg config.AuthConfig,
+	userRepo *postgres.UserRepository,
+	identityRepo *postgres.UserIdentityRepository,
+	auth *AuthService,
+	rdb *redis.Client,
+	taxonomy *TaxonomyService,
+) *SSOService {
+	s := &SSOService{
+		baseURL:      strings.TrimSuffix(cfg.BaseURL, "/"),
+		providers:    make(map[string]*ssoProvider, len(cfg.SSO)),
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		auth:         auth,
+		rdb:          rdb,
+		taxonomy:     taxonomy,
+	}
+
+	for _, providerCfg := range cfg.SSO {
+		impl, err := sso.New(providerCfg, nil)
+		if err != nil {
+			logger.Errorf("Skipping sso provider %s: %v", providerCfg.ID, err)
+			continue
+		}
+		s.providers[providerCfg.ID] = &ssoProvider{cfg: providerCfg, impl: impl}
+		s.order = append(s.order, providerCfg.ID)
+	}
+
+	return s
+}
+
+// Providers lists the configured providers in configuration order
+func (s *SSOService) Providers() []*domain.SSOProvider {
+	providers := make([]*domain.SSOProvider, 0, len(s.order))
+	for _, id := range s.order {
+		cfg := s.providers[id].cfg
+		providers = append(providers, &domain.SSOProvider{
+			ID:       cfg.ID,
+			Name:     cfg.Name,
+			School:   cfg.School,
+			Protocol: cfg.Protocol,
+		})
+	}
+	return providers
+}
+
+// Begin starts a login with the provider and returns the URL to redirect the
+// browser to, along with the state the caller must bind to the browser (e.g. in
+// an HttpOnly cookie) and pass back to Complete
+func (s *SSOService) Begin(ctx context.Context, providerID string) (string, string, error) {
+	provider, ok := s.providers[providerID]
+	if !ok {
+		return "", "", ErrSSOProviderNotFound
+	}
+
+	state, err := newSecureToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := newSecureToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err := json.Marshal(&ssoState{Provider: providerID, Nonce: nonce})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode sso state: %w", err)
+	}
+	if err := s.rdb.Set(ctx, fmt.Sprintf(ssoStateKey, state), data, SSOStateTTL); err != nil {
+		return "", "", fmt.Errorf("failed to save sso state: %w", err)
+	}
+
+	authURL, err := provider.impl.AuthURL(ctx, s.callbackURL(providerID), state, nonce)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to build sso login url: %w", err)
+	}
+	return authURL, state, nil
+}
+
+// Complete finishes a login from the parameters the provider sent back to the
+// callback page, and signs the matching user in, creating them if needed.
+// browserState is the state Begin bound to the browser; it must match the
+// returned state so a login started in one browser cannot be finished in another.
+func (s *SSOService) Complete(ctx context.Context, providerID string, params url.Values, browserState string, client *domain.ClientInfo) (*domain.User, *domain.TokenPair, error) {
+	provider, ok := s.providers[providerID]
+	if !ok {
+		return nil, nil, ErrSSOProviderNotFound
+	}
+
+	returned := params.Get("state")
+	if browserState == "" || subtle.ConstantTimeCompare([]byte(browserState), []byte(returned)) != 1 {
+		return nil, nil, ErrInvalidSSOState
+	}
+	state, err := s.takeState(ctx, returned)
+	if err != nil {
+		return nil, nil, err
+	}
+	if state.Provider != providerID {
+		return nil, nil, ErrInvalidSSOState
+	}
+
+	identity, err := provider.impl.Authenticate(ctx, s.callbackURL(providerID), params, state.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.resolveUser(ctx, provider.cfg, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.auth.startSession(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// callbackURL 身份提供方登录后回到的前端页面，前端把收到的参数提交给 Complete
+func (s *SSOService) callbackURL(providerID string) string {
+	return fmt.Sprintf("%s/sso/%s/callback", s.baseURL, url.PathEscape(providerID))
+}
+
+// takeState 取出并删除 state，每个 state 只能使用一次
+func (s *SSOService) takeState(ctx context.Context, state string) (*ssoState, error) {
+	if state == "" {
+		return nil, ErrInvalidSSOState
+	}
+
+	data, err := s.rdb.GetDel(ctx, s.rdb.Key(fmt.Sprintf(ssoStateKey, state))).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrInvalidSSOState
+		}
+		return nil, fmt.Errorf("failed to load sso state: %w", err)
+	}
+
+	var st ssoState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, ErrInvalidSSOState
+	}
+	return &st, nil
+}
+
+// resolveUser 找到身份对应的用户：已绑定的直接使用；否则按已验证的邮箱绑定现有用户；
+// 都没有时用身份属性创建新用户。仅凭 trust_email 视为已验证的邮箱必须属于学校邮箱域名
+func (s *SSOService) resolveUser(ctx context.Context, cfg config.SSOProviderConfig, identity *sso.Identity) (*domain.User, error) {
+	link := &domain.UserIdentity{
+		Provider: cfg.ID,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	existing, err := s.identityRepo.Get(ctx, cfg.ID, identity.Subject)
+	if err == nil {
+		user, err := s.userRepo.GetByID(ctx, existing.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get linked user: %w", err)
+		}
+		link.UserID = user.ID
+		if err := s.identityRepo.Link(ctx, link); err != nil {
+			return nil, fmt.Errorf("failed to update identity: %w", err)
+		}
+		return user, nil
+	}
+	if err.Error() != "identity not found" {
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrSSOEmailNotVerified
+	}
+	if identity.EmailTrusted && !emailInDomains(identity.Email, cfg.EmailDomains) {
+		logger.Warnf("[SECURITY] %s account %s has email %s outside the school domains, refusing to link", cfg.ID, identity.Subject, identity.Email)
+		return nil, ErrSSOEmailNotVerified
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		logger.Infof("Linking %s account %s to user %d by verified email", cfg.ID, identity.Subject, user.ID)
+	case err.Error() == "user not found":
+		if user, err = s.provision(ctx, cfg, identity); err != nil {
+			return nil, err
+		}
+		logger.Infof("Provisioned user %d from %s account %s", user.ID, cfg.ID, identity.Subject)
+	default:
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	// 身份提供方已验证该邮箱
+	if user.EmailVerifiedAt == nil {
+		if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("failed to mark email verified: %w", err)
+		}
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	link.UserID = user.ID
+	if err := s.identityRepo.Link(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+	return user, nil
+}
+
+// provision 用身份属性创建用户；密码随机生成，用户可通过找回密码设置
+func (s *SSOService) provision(ctx context.Context, cfg config.SSOProviderConfig, identity *sso.Identity) (*domain.User, error) {
+	username, err := s.uniqueUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := newSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	grade := identity.Grade
+	if grade < 1 {
+		grade = 1
+	}
+	if grade > 4 {
+		grade = 4
+	}
+
+	user := &domain.User{
+		Username: username,
+		Email:    identity.Email,
+		Password: string(hashedPassword),
+		School:   cfg.School,
+		Major:    s.taxonomy.NormalizeMajor(truncateRunes(identity.Major, 100)),
+		Grade:    grade,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+// emailInDomains 判断邮箱是否属于给定域名或其子域名
+func emailInDomains(email string, domains []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	host := strings.ToLower(email[at+1:])
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueUsername 由身份的用户名或邮箱前缀生成未被占用的用户名
+func (s *SSOService) uniqueUsername(ctx context.Context, identity *sso.Identity) (string, error) {
+	base := sanitizeUsername(identity.Username)
+	if len(base) < 3 {
+		base = sanitizeUsername(strings.SplitN(identity.Email, "@", 2)[0])
+	}
+	if len(base) < 3 {
+		base = "user"
+	}
+
+	candidate := base
+	for i := 0; i < 10; i++ {
+		exists, err := s.userRepo.ExistsByUsername(ctx, candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check username existence: %w", err)
+		}
+		if !exists {
+			return candidate, nil
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate username: %w", err)
+		}
+		candidate = fmt.Sprintf("%s_%04d", base, n.Int64())
+	}
+	return "", errors.New("failed to generate a unique username")
+}
+
+// sanitizeUsername 只保留字母、数字与 . _ -，最长40个字符（为去重后缀留出空间）
+func sanitizeUsername(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			b.WriteRune(r)
+		}
+		if b.Len() >= 40 {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/repository/redis"
+	"github.com/unifocus/backend/internal/taxonomy"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// taxonomyRefreshInterval 定期全量重建索引的间隔，兜底错过的变更广播
+const taxonomyRefreshInterval = 10 * time.Minute
+
+// ErrInvalidTaxonomyTerm indicates a taxonomy term request violates the hierarchy rules
+var ErrInvalidTaxonomyTerm = errors.New("invalid taxonomy term")
+
+// TaxonomyService manages taxonomy terms and normalizes skills and majors
+// It keeps an in-memory index of active terms that is rebuilt after each change.
+// Changes are broadcast over a Redis channel so that every instance rebuilds its index.
+type TaxonomyService struct {
+	repo    *postgres.TaxonomyRepository
+	rdb     *redis.Client
+	channel string
+	mu      sync.RWMutex
+	index   *taxonomy.Index
+}
+
+// NewTaxonomyService creates a new taxonomy service
+// Call Load before use; until then normalization is a no-op
+func NewTaxonomyService(repo *postgres.TaxonomyRepository, rdb *redis.Client) *TaxonomyService {
+	return &TaxonomyService{
+		repo:    repo,
+		rdb:     rdb,
+		channel: rdb.Key("taxonomy:changed"),
+	}
+}
+
+// Start rebuilds the index whenever any instance changes a term, and periodically
+// as a fallback for missed broadcasts, until ctx is cancelled
+func (s *TaxonomyService) Start(ctx context.Context) {
+	pubsub := s.rdb.Subscribe(ctx, s.channel)
+	defer pubsub.Close()
+
+	ticker := time.NewTicker(taxonomyRefreshInterval)
+	defer ticker.Stop()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-messages:
+			if !ok {
+				return
+			}
+			s.reload(ctx)
+		case <-ticker.C:
+			s.reload(ctx)
+		}
+	}
+}
+
+// Load (re)builds the in-memory index from active terms
+func (s *TaxonomyService) Load(ctx context.Context) error {
+	terms, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load taxonomy: %w", err)
+	}
+
+	idx := taxonomy.NewIndex(terms)
+
+	s.mu.Lock()
+	s.index = idx
+	s.mu.Unlock()
+
+	logger.Infof("Taxonomy loaded: %d terms", idx.Len())
+	return nil
+}
+
+// Index returns the current taxonomy index
+// It is safe to call on a nil service and returns a nil (empty) index
+func (s *TaxonomyService) Index() *taxonomy.Index {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index
+}
+
+// NormalizeSkills maps free-text skills to canonical skill names
+func (s *TaxonomyService) NormalizeSkills(skills []string) []string {
+	return s.Index().NormalizeAll(taxonomy.KindSkill, skills)
+}
+
+// NormalizeMajor maps a free-text major to its canonical name
+func (s *TaxonomyService) NormalizeMajor(major string) string {
+	if strings.TrimSpace(major) == "" {
+		return major
+	}
+	return s.Index().Normalize(taxonomy.KindMajor, major)
+}
+
+// NormalizeMajors maps free-text majors to canonical names
+func (s *TaxonomyService) NormalizeMajors(majors []string) []string {
+	return s.Index().NormalizeAll(taxonomy.KindMajor, majors)
+}
+
+// List retrieves taxonomy terms with filtering and pagination
+func (s *TaxonomyService) List(ctx context.Context, filter *domain.TaxonomyFilter) ([]*domain.TaxonomyTerm, int64, error) {
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+
+	terms, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list taxonomy terms: %w", err)
+	}
+
+	return terms, total, nil
+}
+
+// GetByID retrieves a taxonomy term by ID
+func (s *TaxonomyService) GetByID(ctx context.Context, id int64) (*domain.TaxonomyTerm, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Create creates a new taxonomy term
+func (s *TaxonomyService) Create(ctx context.Context, req *domain.TaxonomyTermRequest) (*domain.TaxonomyTerm, error) {
+	term := &domain.TaxonomyTerm{IsActive: true}
+	if err := s.apply(ctx, term, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, term); err != nil {
+		return nil, err
+	}
+
+	s.changed(ctx)
+	return term, nil
+}
+
+// Update updates an existing taxonomy term
+func (s *TaxonomyService) Update(ctx context.Context, id int64, req *domain.TaxonomyTermRequest) (*domain.TaxonomyTerm, error) {
+	term, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, term, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, term); err != nil {
+		return nil, err
+	}
+
+	s.changed(ctx)
+	return term, nil
+}
+
+// Delete deactivates a taxonomy term
+func (s *TaxonomyService) Delete(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.changed(ctx)
+	return nil
+}
+
+// apply copies a request onto a term after validating the hierarchy
+func (s *TaxonomyService) apply(ctx context.Context, term *domain.TaxonomyTerm, req *domain.TaxonomyTermRequest) error {
+	if req.Kind == taxonomy.KindSkill && (req.Level != "" || req.ParentID != nil) {
+		return fmt.Errorf("%w: skill terms have no hierarchy", ErrInvalidTaxonomyTerm)
+	}
+	if req.Kind == taxonomy.KindMajor && req.Level == "" {
+		return fmt.Errorf("%w: level is required for major terms", ErrInvalidTaxonomyTerm)
+	}
+
+	if req.ParentID != nil {
+		if term.ID != 0 && *req.ParentID == term.ID {
+			return fmt.Errorf("%w: term cannot be its own parent", ErrInvalidTaxonomyTerm)
+		}
+		parent, err := s.repo.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			return fmt.Errorf("%w: parent %d: %v", ErrInvalidTaxonomyTerm, *req.ParentID, err)
+		}
+		if parent.Kind != req.Kind {
+			return fmt.Errorf("%w: parent must be of kind %s", ErrInvalidTaxonomyTerm, req.Kind)
+		}
+	}
+
+	term.Kind = req.Kind
+	term.Code = strings.TrimSpace(req.Code)
+	term.Name = strings.TrimSpace(req.Name)
+	term.Level = req.Level
+	term.ParentID = req.ParentID
+	term.Synonyms = cleanSynonyms(term.Name, req.Synonyms)
+	if req.IsActive != nil {
+		term.IsActive = *req.IsActive
+	}
+
+	return nil
+}
+
+// changed rebuilds the local index and tells the other instances to rebuild theirs
+func (s *TaxonomyService) changed(ctx context.Context) {
+	s.reload(ctx)
+	if err := s.rdb.Publish(ctx, s.channel, "reload").Err(); err != nil {
+		logger.Warnf("failed to broadcast taxonomy change: %v", err)
+	}
+}
+
+// reload rebuilds the index after a change; failures keep the previous index
+func (s *TaxonomyService) reload(ctx context.Context) {
+	if err := s.Load(ctx); err != nil {
+		logger.Errorf("failed to reload taxonomy: %v", err)
+	}
+}
+
+// cleanSynonyms trims synonyms and drops blanks, duplicates and the canonical name itself
+func cleanSynonyms(name string, synonyms []string) []string {
+	result := make([]string, 0, len(synonyms))
+	seen := map[string]bool{taxonomy.Key(name): true}
+	for _, synonym := range synonyms {
+		synonym = strings.TrimSpace(synonym)
+		key := taxonomy.Key(synonym)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, synonym)
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+)
+
+// ErrInvalidStatusTransition indicates the requested lifecycle change is not allowed
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// statusTransitions 允许的状态流转：saved → applied → completed，未完成前可放弃，放弃后可重新保存
+var statusTransitions = map[string][]string{
+	"": {domain.UserOpportunityStatusSaved},
+	domain.UserOpportunityStatusSaved: {
+		domain.UserOpportunityStatusApplied,
+		domain.UserOpportunityStatusAbandoned,
+	},
+	domain.UserOpportunityStatusApplied: {
+		domain.UserOpportunityStatusCompleted,
+		domain.UserOpportunityStatusAbandoned,
+	},
+	domain.UserOpportunityStatusAbandoned: {domain.UserOpportunityStatusSaved},
+}
+
+// UserOpportunityService handles the save/apply/complete lifecycle of opportunities
+type UserOpportunityService struct {
+	uoRepo     *postgres.UserOpportunityRepository
+	recService *RecommendationService // 反馈后失效推荐缓存，可为nil
+}
+
+// NewUserOpportunityService creates a new user-opportunity service
+func NewUserOpportunityService(uoRepo *postgres.UserOpportunityRepository, recService *RecommendationService) *UserOpportunityService {
+	return &UserOpportunityService{
+		uoRepo:     uoRepo,
+		recService: recService,
+	}
+}
+
+// Save saves an opportunity for the user; saving twice is a no-op
+func (s *UserOpportunityService) Save(ctx context.Context, userID int64, req *domain.SaveOpportunityRequest) (*domain.UserOpportunity, bool, error) {
+	uo, created, err := s.uoRepo.Save(ctx, userID, req.OpportunityID)
+	if err != nil {
+		if err.Error() == "opportunity not found" {
+			return nil, false, err
+		}
+		return nil, false, fmt.Errorf("failed to save opportunity: %w", err)
+	}
+
+	return uo, created, nil
+}
+
+// UpdateStatus moves a saved opportunity through its lifecycle
+func (s *UserOpportunityService) UpdateStatus(ctx context.Context, userID, opportunityID int64, req *domain.UpdateOpportunityStatusRequest) (*domain.UserOpportunity, error) {
+	current, err := s.uoRepo.Get(ctx, userID, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Status == req.Status {
+		return current, nil
+	}
+	if !canTransition(current.Status, req.Status) {
+		from := current.Status
+		if from == "" {
+			from = "unsaved"
+		}
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, from, req.Status)
+	}
+
+	// 重新保存与首次保存走同一路径，以便计入save_count
+	if req.Status == domain.UserOpportunityStatusSaved {
+		uo, _, err := s.Save(ctx, userID, &domain.SaveOpportunityRequest{OpportunityID: opportunityID})
+		return uo, err
+	}
+
+	uo, err := s.uoRepo.UpdateStatus(ctx, userID, opportunityID, current.Status, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return uo, nil
+}
+
+// List retrieves the user's opportunities, optionally filtered by status
+func (s *UserOpportunityService) List(ctx context.Context, userID int64, filter *domain.UserOpportunityFilter) ([]*domain.UserOpportunityDetail, int64, error) {
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	items, total, err := s.uoRepo.ListByUser(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list user opportunities: %w", err)
+	}
+
+	return items, total, nil
+}
+
+// Feedback records the user's feedback on an opportunity and refreshes the feed,
+// which hides irrelevant items and down-weights similar ones
+func (s *UserOpportunityService) Feedback(ctx context.Context, userID, opportunityID int64, req *domain.FeedbackRequest) (*domain.UserOpportunity, error) {
+	uo, err := s.uoRepo.SetFeedback(ctx, userID, opportunityID, req.Feedback, req.Reason)
+	if err != nil {
+		if err.Error() == "opportunity not found" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record feedback: %w", err)
+	}
+
+	if s.recService != nil {
+		s.recService.Invalidate(ctx, userID)
+	}
+
+	return uo, nil
+}
+
+// FeedbackReport returns the opportunities and sources most often marked irrelevant
+func (s *UserOpportunityService) FeedbackReport(ctx context.Context, query *domain.FeedbackReportQuery) (*domain.FeedbackReport, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	opportunities, err := s.uoRepo.TopIrrelevantOpportunities(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate irrelevant opportunities: %w", err)
+	}
+
+	sources, err := s.uoRepo.TopIrrelevantSources(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate irrelevant sources: %w", err)
+	}
+
+	report := &domain.FeedbackReport{
+		Opportunities: opportunities,
+		Sources:       sources,
+	}
+	if report.Opportunities == nil {
+		report.Opportunities = []*domain.IrrelevantOpportunityStat{}
+	}
+	if report.Sources == nil {
+		report.Sources = []*domain.IrrelevantSourceStat{}
+	}
+
+	return report, nil
+}
+
+// canTransition reports whether a relation may move from one status to another
+func canTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // 注册JPEG解码器，用于读取头像尺寸
+	_ "image/png"  // 注册PNG解码器
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+	"github.com/unifocus/backend/internal/domain"
+	"github.com/unifocus/backend/internal/repository/postgres"
+	"github.com/unifocus/backend/internal/storage"
+	"github.com/unifocus/backend/pkg/logger"
+)
+
+// gradeRolloverInterval 检查是否到了学年升级日的间隔
+const gradeRolloverInterval = time.Hour
+
+// avatarTypes 允许的头像格式及保存时的扩展名
+var avatarTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+var (
+	// ErrInvalidUser indicates an account update with invalid values
+	ErrInvalidUser = errors.New("invalid user")
+	// ErrUsernameTaken indicates the requested username belongs to another account; it wraps ErrInvalidUser
+	ErrUsernameTaken = fmt.Errorf("%w: username already exists", ErrInvalidUser)
+	// ErrInvalidAvatar indicates an uploaded avatar that is not a supported image
+	ErrInvalidAvatar = errors.New("invalid avatar")
+	// ErrAvatarTooLarge indicates an uploaded avatar above the configured size limit
+	ErrAvatarTooLarge = errors.New("avatar is too large")
+)
+
+// UserService manages the account record: school, major, grade and avatar.
+// Grades move up automatically at the start of each academic year.
+type UserService struct {
+	cfg        config.UsersConfig
+	loc        *time.Location
+	userRepo   *postgres.UserRepository
+	store      storage.Store
+	taxonomy   *TaxonomyService
+	recService *RecommendationService
+}
+
+// NewUserService creates a new user service
+func NewUserService(
+	cfg config.UsersConfig,
+	loc *time.Location,
+	userRepo *postgres.UserRepository,
+	store storage.Store,
+	taxonomy *TaxonomyService,
+	recService *RecommendationService,
+) *UserService {
+	return &UserService{
+		cfg:        cfg,
+		loc:        loc,
+		userRepo:   userRepo,
+		store:      store,
+		taxonomy:   taxonomy,
+		recService: recService,
+	}
+}
+
+// GetUser retrieves the user's account record
+func (s *UserService) GetUser(ctx context.Context, userID int64) (*domain.User, error) {
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+// UpdateUser changes the fields present in the request. A new school, major or
+// grade changes which opportunities the user is eligible for, so their cached
+// recommendations are dropped.
+func (s *UserService) UpdateUser(ctx context.Context, userID int64, req *domain.UpdateUserRequest) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if len(username) < 3 {
+			return nil, fmt.Errorf("%w: username must be at least 3 characters", ErrInvalidUser)
+		}
+		if username != user.Username {
+			exists, err := s.userRepo.ExistsByUsername(ctx, username)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check username existence: %w", err)
+			}
+			if exists {
+				return nil, ErrUsernameTaken
+			}
+			user.Username = username
+		}
+	}
+	if req.School != nil {
+		school := strings.TrimSpace(*req.School)
+		if school == "" {
+			return nil, fmt.Errorf("%w: school must not be empty", ErrInvalidUser)
+		}
+		user.School = school
+	}
+	if req.Major != nil {
+		major := strings.TrimSpace(*req.Major)
+		if major == "" {
+			return nil, fmt.Errorf("%w: major must not be empty", ErrInvalidUser)
+		}
+		user.Major = s.taxonomy.NormalizeMajor(major)
+	}
+	if req.Grade != nil {
+		user.Grade = *req.Grade
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		switch err.Error() {
+		case "user not found":
+			return nil, err
+		case "username already exists":
+			// 检查之后被其他账号抢先占用
+			return nil, ErrUsernameTaken
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if req.School != nil || req.Major != nil || req.Grade != nil {
+		s.recService.Invalidate(ctx, userID)
+	}
+	return user, nil
+}
+
+// UploadAvatar checks that the upload is a JPEG or PNG image within the size
+// and dimension limits, stores it and replaces the user's previous avatar
+func (s *UserService) UploadAvatar(ctx context.Context, userID int64, file io.Reader) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := s.cfg.GetAvatarMaxSize()
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrAvatarTooLarge
+	}
+
+	// 按文件内容而非扩展名判断格式，并解码尺寸确认是完整的图片
+	contentType := http.DetectContentType(data)
+	ext, ok := avatarTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: only JPEG and PNG images are supported", ErrInvalidAvatar)
+	}
+	img, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: image cannot be decoded", ErrInvalidAvatar)
+	}
+	if img.Width > s.cfg.AvatarMaxDimension || img.Height > s.cfg.AvatarMaxDimension {
+		return nil, fmt.Errorf("%w: image must be at most %dx%d pixels", ErrInvalidAvatar, s.cfg.AvatarMaxDimension, s.cfg.AvatarMaxDimension)
+	}
+
+	// 每次上传使用新的文件名，避免浏览器与CDN缓存旧头像
+	name, err := newSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	avatarURL, err := s.store.Put(ctx, fmt.Sprintf("avatars/%d/%s%s", userID, name, ext), contentType, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store avatar: %w", err)
+	}
+
+	if err := s.userRepo.UpdateAvatar(ctx, userID, avatarURL); err != nil {
+		return nil, fmt.Errorf("failed to update avatar: %w", err)
+	}
+	s.deleteAvatarFile(ctx, user.AvatarURL)
+
+	user.AvatarURL = avatarURL
+	return user, nil
+}
+
+// DeleteAvatar removes the user's avatar
+func (s *UserService) DeleteAvatar(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.AvatarURL == "" {
+		return nil
+	}
+
+	if err := s.userRepo.UpdateAvatar(ctx, userID, ""); err != nil {
+		return fmt.Errorf("failed to update avatar: %w", err)
+	}
+	s.deleteAvatarFile(ctx, user.AvatarURL)
+	return nil
+}
+
+// deleteAvatarFile 删除本存储中的头像文件；外部地址（如注册时填写的链接）不处理
+func (s *UserService) deleteAvatarFile(ctx context.Context, avatarURL string) {
+	key, ok := s.store.Key(avatarURL)
+	if !ok {
+		return
+	}
+	if err := s.store.Delete(ctx, key); err != nil {
+		logger.Warnf("failed to delete avatar %s: %v", key, err)
+	}
+}
+
+// Start rolls grades over once the academic year has started, checking immediately
+// and then every hour until ctx is cancelled
+func (s *UserService) Start(ctx context.Context) {
+	ticker := time.NewTicker(gradeRolloverInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RolloverGrades(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			logger.Errorf("Grade rollover failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RolloverGrades moves up every user whose grade was set before the start of the
+// current academic year. Each user moves at most once per year, so running it
+// repeatedly or on several instances is safe.
+func (s *UserService) RolloverGrades(ctx context.Context, now time.Time) error {
+	yearStart := s.academicYearStart(now)
+
+	count, err := s.userRepo.RolloverGrades(ctx, yearStart)
+	if err != nil {
+		return fmt.Errorf("failed to roll over grades: %w", err)
+	}
+	if count > 0 {
+		logger.Infof("Moved %d users up a grade for the academic year starting %s", count, yearStart.In(s.loc).Format("2006-01-02"))
+	}
+	return nil
+}
+
+// academicYearStart 返回 now 所在学年的升级日零点（calendar.timezone）
+func (s *UserService) academicYearStart(now time.Time) time.Time {
+	local := now.In(s.loc)
+	start := time.Date(local.Year(), time.Month(s.cfg.GradeRolloverMonth), s.cfg.GradeRolloverDay, 0, 0, 0, 0, s.loc)
+	if local.Before(start) {
+		start = start.AddDate(-1, 0, 0)
+	}
+	return start
+}
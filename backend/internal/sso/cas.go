@@ -0,0 +1,115 @@
+package sso
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/unifocus/backend/internal/config"
+)
+
+// casServiceResponse serviceValidate 的XML响应
+type casServiceResponse struct {
+	XMLName xml.Name `xml:"serviceResponse"`
+	Success *struct {
+		User       string `xml:"user"`
+		Attributes struct {
+			Items []casAttribute `xml:",any"`
+		} `xml:"attributes"`
+	} `xml:"authenticationSuccess"`
+	Failure *struct {
+		Code    string `xml:"code,attr"`
+		Message string `xml:",chardata"`
+	} `xml:"authenticationFailure"`
+}
+
+// casAttribute CAS 3.0 的一个属性值，多值属性会出现多次
+type casAttribute struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// casProvider CAS 2.0/3.0，登录后用 serviceValidate 校验票据；
+// CAS 没有 state 参数，state 放在 service 地址中随票据一起带回
+type casProvider struct {
+	cfg    config.SSOProviderConfig
+	client *http.Client
+}
+
+func newCASProvider(cfg config.SSOProviderConfig, client *http.Client) *casProvider {
+	return &casProvider{cfg: cfg, client: client}
+}
+
+// AuthURL 返回CAS登录页地址
+func (p *casProvider) AuthURL(ctx context.Context, callbackURL, state, nonce string) (string, error) {
+	query := url.Values{}
+	query.Set("service", casService(callbackURL, state))
+	return appendQuery(strings.TrimSuffix(p.cfg.CASURL, "/")+"/login", query), nil
+}
+
+// Authenticate 向CAS服务校验票据
+func (p *casProvider) Authenticate(ctx context.Context, callbackURL string, params url.Values, nonce string) (*Identity, error) {
+	ticket := params.Get("ticket")
+	if ticket == "" {
+		return nil, fmt.Errorf("%w: missing ticket", ErrAuthenticationFailed)
+	}
+
+	path := "/p3/serviceValidate"
+	if p.cfg.CASVersion == "2.0" {
+		path = "/serviceValidate"
+	}
+	query := url.Values{}
+	query.Set("service", casService(callbackURL, params.Get("state")))
+	query.Set("ticket", ticket)
+	endpoint := appendQuery(strings.TrimSuffix(p.cfg.CASURL, "/")+path, query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ticket validation request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate cas ticket: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from cas ticket validation", resp.StatusCode)
+	}
+	var result casServiceResponse
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode cas response: %w", err)
+	}
+	if result.Failure != nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrAuthenticationFailed, result.Failure.Code, strings.TrimSpace(result.Failure.Message))
+	}
+	if result.Success == nil || strings.TrimSpace(result.Success.User) == "" {
+		return nil, fmt.Errorf("%w: empty cas response", ErrAuthenticationFailed)
+	}
+
+	attrs := make(map[string]interface{}, len(result.Success.Attributes.Items))
+	for _, item := range result.Success.Attributes.Items {
+		name := item.XMLName.Local
+		value := strings.TrimSpace(item.Value)
+		switch existing := attrs[name].(type) {
+		case nil:
+			attrs[name] = value
+		case string:
+			attrs[name] = []interface{}{existing, value}
+		case []interface{}:
+			attrs[name] = append(existing, value)
+		}
+	}
+
+	// CAS 不声明邮箱是否已验证，由 trust_email 决定
+	return identityFromAttributes(p.cfg, strings.TrimSpace(result.Success.User), attrs, p.cfg.TrustEmail, true), nil
+}
+
+// casService 带 state 的 service 地址，登录与校验票据时必须完全一致
+func casService(callbackURL, state string) string {
+	return appendQuery(callbackURL, url.Values{"state": {state}})
+}
@@ -0,0 +1,326 @@
+package sso
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/unifocus/backend/internal/config"
+)
+
+// maxResponseBytes 身份提供方响应的大小上限
+const maxResponseBytes = 1 << 20
+
+// oidcDiscovery OpenID Provider 元数据中用到的字段
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// jsonWebKey JWKS 中的 RSA 公钥
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// oidcProvider OpenID Connect 授权码模式，ID令牌使用 RS256/RS384/RS512 签名
+type oidcProvider struct {
+	cfg    config.SSOProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+func newOIDCProvider(cfg config.SSOProviderConfig, client *http.Client) *oidcProvider {
+	return &oidcProvider{cfg: cfg, client: client}
+}
+
+// AuthURL 返回授权端点地址
+func (p *oidcProvider) AuthURL(ctx context.Context, callbackURL, state, nonce string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", callbackURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	return appendQuery(discovery.AuthorizationEndpoint, query), nil
+}
+
+// Authenticate 用授权码换取令牌，校验ID令牌，并用 userinfo 端点补全属性
+func (p *oidcProvider) Authenticate(ctx context.Context, callbackURL string, params url.Values, nonce string) (*Identity, error) {
+	if errCode := params.Get("error"); errCode != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrAuthenticationFailed, errCode, params.Get("error_description"))
+	}
+	code := params.Get("code")
+	if code == "" {
+		return nil, fmt.Errorf("%w: missing authorization code", ErrAuthenticationFailed)
+	}
+
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := p.exchange(ctx, discovery, code, callbackURL)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.verifyIDToken(ctx, discovery, tokens.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: id token has no subject", ErrAuthenticationFailed)
+	}
+
+	if discovery.UserinfoEndpoint != "" && tokens.AccessToken != "" {
+		info, err := p.userinfo(ctx, discovery, tokens.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		if sub, _ := info["sub"].(string); sub != subject {
+			return nil, fmt.Errorf("%w: userinfo subject mismatch", ErrAuthenticationFailed)
+		}
+		// ID令牌中的声明优先，userinfo 只补充缺少的属性
+		for name, value := range info {
+			if _, ok := claims[name]; !ok {
+				claims[name] = value
+			}
+		}
+	}
+
+	verified, trusted := p.emailVerified(claims)
+	return identityFromAttributes(p.cfg, subject, claims, verified, trusted), nil
+}
+
+// emailVerified 读取 email_verified 声明，未声明时按 trust_email 配置；
+// trusted 表示结果来自配置而非声明
+func (p *oidcProvider) emailVerified(claims map[string]interface{}) (verified, trusted bool) {
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		return v, false
+	case string:
+		return v == "true", false
+	default:
+		return p.cfg.TrustEmail, true
+	}
+}
+
+// discover 读取并缓存 OpenID Provider 元数据
+func (p *oidcProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(p.cfg.Issuer, "/")
+	var discovery oidcDiscovery
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", "", &discovery); err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider %s: %w", p.cfg.ID, err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc provider %s: issuer mismatch %q", p.cfg.ID, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc provider %s: incomplete discovery document", p.cfg.ID)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// tokenResponse 令牌端点响应
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchange 用授权码换取令牌（client_secret_basic）
+func (p *oidcProvider) exchange(ctx context.Context, discovery *oidcDiscovery, code, callbackURL string) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", callbackURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokens tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || tokens.Error != "" {
+		return nil, fmt.Errorf("%w: token endpoint returned %d %s %s", ErrAuthenticationFailed, resp.StatusCode, tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response has no id_token", ErrAuthenticationFailed)
+	}
+	return &tokens, nil
+}
+
+// verifyIDToken 校验ID令牌的签名、签发方、受众、有效期与 nonce
+func (p *oidcProvider) verifyIDToken(ctx context.Context, discovery *oidcDiscovery, raw, nonce string) (jwtlib.MapClaims, error) {
+	claims := jwtlib.MapClaims{}
+	_, err := jwtlib.ParseWithClaims(raw, claims,
+		func(token *jwtlib.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.publicKey(ctx, discovery, kid)
+		},
+		jwtlib.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwtlib.WithIssuer(discovery.Issuer),
+		jwtlib.WithAudience(p.cfg.ClientID),
+		jwtlib.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid id token: %v", ErrAuthenticationFailed, err)
+	}
+	if claimNonce, _ := claims["nonce"].(string); claimNonce != nonce {
+		return nil, fmt.Errorf("%w: id token nonce mismatch", ErrAuthenticationFailed)
+	}
+	return claims, nil
+}
+
+// publicKey 按 kid 查找签名公钥，找不到时重新拉取 JWKS（身份提供方可能已轮换密钥）
+func (p *oidcProvider) publicKey(ctx context.Context, discovery *oidcDiscovery, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, discovery.JWKSURI, "", &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := rsaPublicKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.keys = keys
+
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("signing key %q not found", kid)
+}
+
+// lookupKey 在已缓存的公钥中查找；令牌未带 kid 且只有一个公钥时使用该公钥
+func (p *oidcProvider) lookupKey(kid string) *rsa.PublicKey {
+	if key, ok := p.keys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+	return nil
+}
+
+// userinfo 读取 userinfo 端点返回的声明
+func (p *oidcProvider) userinfo(ctx context.Context, discovery *oidcDiscovery, accessToken string) (map[string]interface{}, error) {
+	info := map[string]interface{}{}
+	if err := p.getJSON(ctx, discovery.UserinfoEndpoint, accessToken, &info); err != nil {
+		return nil, fmt.Errorf("failed to fetch userinfo: %w", err)
+	}
+	return info, nil
+}
+
+// getJSON 发起GET请求并解析JSON响应，bearer 非空时携带访问令牌
+func (p *oidcProvider) getJSON(ctx context.Context, endpoint, bearer string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v)
+}
+
+// rsaPublicKey 由 JWK 的模数与指数构造 RSA 公钥
+func rsaPublicKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 {
+		return nil, fmt.Errorf("invalid rsa exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// appendQuery 把查询参数追加到可能已带参数的地址后
+func appendQuery(endpoint string, query url.Values) string {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+	return endpoint + separator + query.Encode()
+}
@@ -0,0 +1,388 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/unifocus/backend/internal/config"
+)
+
+const (
+	testClientID     = "unifocus"
+	testClientSecret = "s3cret"
+	testCallbackURL  = "https://unifocus.test/api/v1/auth/sso/campus/callback"
+)
+
+// mockOIDC 本地模拟的 OpenID Provider：授权码 "good-code" 换取以 key 签名的ID令牌
+type mockOIDC struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	nonce    string
+	claims   jwtlib.MapClaims // 覆盖默认的ID令牌声明
+	signWith *rsa.PrivateKey  // 非nil时用另一把密钥签名
+	userinfo map[string]interface{}
+}
+
+func newMockOIDC(t *testing.T) *mockOIDC {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockOIDC{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"userinfo_endpoint":      m.server.URL + "/userinfo",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if r.Method != http.MethodPost || id != testClientID || secret != testClientSecret {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]string{"error": "invalid_client"})
+			return
+		}
+		if r.FormValue("code") != "good-code" || r.FormValue("redirect_uri") != testCallbackURL {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]string{
+			"access_token": "access-1",
+			"token_type":   "Bearer",
+			"id_token":     m.idToken(t),
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, m.userinfo)
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+
+	m.userinfo = map[string]interface{}{"sub": "20210001", "major": "计算机科学与技术", "grade": "2"}
+	return m
+}
+
+func (m *mockOIDC) idToken(t *testing.T) string {
+	claims := jwtlib.MapClaims{
+		"iss":                m.server.URL,
+		"sub":                "20210001",
+		"aud":                testClientID,
+		"exp":                time.Now().Add(5 * time.Minute).Unix(),
+		"iat":                time.Now().Unix(),
+		"nonce":              m.nonce,
+		"email":              "Zhang.San@Campus.EDU.cn",
+		"email_verified":     true,
+		"preferred_username": "zhangsan",
+		"name":               "张三",
+	}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
+	token.Header["kid"] = "k1"
+	key := m.key
+	if m.signWith != nil {
+		key = m.signWith
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func (m *mockOIDC) provider(t *testing.T) Provider {
+	t.Helper()
+	cfg := config.SSOProviderConfig{
+		ID:           "campus",
+		Protocol:     config.SSOProtocolOIDC,
+		Issuer:       m.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		Scopes:       []string{"openid", "email", "profile"},
+		Attributes: config.SSOAttributeMapping{
+			Username: "preferred_username", Email: "email", Name: "name", Major: "major", Grade: "grade",
+		},
+	}
+	p, err := New(cfg, m.server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestOIDCAuthorizationCodeFlow(t *testing.T) {
+	idp := newMockOIDC(t)
+	idp.nonce = "n-1"
+	p := idp.provider(t)
+	ctx := context.Background()
+
+	authURL, err := p.AuthURL(ctx, testCallbackURL, "st-1", "n-1")
+	if err != nil {
+		t.Fatalf("AuthURL() error = %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	if u.Path != "/authorize" || q.Get("response_type") != "code" || q.Get("client_id") != testClientID ||
+		q.Get("redirect_uri") != testCallbackURL || q.Get("state") != "st-1" || q.Get("nonce") != "n-1" ||
+		q.Get("scope") != "openid email profile" {
+		t.Fatalf("AuthURL() = %s", authURL)
+	}
+
+	identity, err := p.Authenticate(ctx, testCallbackURL, url.Values{"code": {"good-code"}, "state": {"st-1"}}, "n-1")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	want := Identity{
+		Subject:       "20210001",
+		Email:         "zhang.san@campus.edu.cn",
+		EmailVerified: true,
+		Username:      "zhangsan",
+		Name:          "张三",
+		Major:         "计算机科学与技术",
+		Grade:         2,
+	}
+	if *identity != want {
+		t.Errorf("Authenticate() = %+v, want %+v", *identity, want)
+	}
+}
+
+func TestOIDCRejectsInvalidResponses(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(m *mockOIDC)
+		params url.Values
+		nonce  string
+	}{
+		{"idp error", nil, url.Values{"error": {"access_denied"}}, "n-1"},
+		{"missing code", nil, url.Values{}, "n-1"},
+		{"bad code", nil, url.Values{"code": {"bad-code"}}, "n-1"},
+		{"nonce mismatch", nil, url.Values{"code": {"good-code"}}, "other"},
+		{"wrong audience", func(m *mockOIDC) { m.claims = jwtlib.MapClaims{"aud": "someone-else"} }, url.Values{"code": {"good-code"}}, "n-1"},
+		{"wrong issuer", func(m *mockOIDC) { m.claims = jwtlib.MapClaims{"iss": "https://evil.test"} }, url.Values{"code": {"good-code"}}, "n-1"},
+		{"expired", func(m *mockOIDC) { m.claims = jwtlib.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()} }, url.Values{"code": {"good-code"}}, "n-1"},
+		{"unknown signing key", func(m *mockOIDC) { m.signWith = otherKey }, url.Values{"code": {"good-code"}}, "n-1"},
+		{"userinfo subject mismatch", func(m *mockOIDC) { m.userinfo = map[string]interface{}{"sub": "someone"} }, url.Values{"code": {"good-code"}}, "n-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newMockOIDC(t)
+			idp.nonce = "n-1"
+			if tt.setup != nil {
+				tt.setup(idp)
+			}
+			_, err := idp.provider(t).Authenticate(context.Background(), testCallbackURL, tt.params, tt.nonce)
+			if !errors.Is(err, ErrAuthenticationFailed) {
+				t.Errorf("Authenticate() error = %v, want ErrAuthenticationFailed", err)
+			}
+		})
+	}
+}
+
+func TestOIDCEmailVerified(t *testing.T) {
+	tests := []struct {
+		name     string
+		claim    interface{}
+		trust    bool
+		verified bool
+		trusted  bool
+	}{
+		{"verified", true, false, true, false},
+		{"verified with trust", true, true, true, false},
+		{"unverified", false, true, false, false},
+		{"string claim", "true", false, true, false},
+		{"absent untrusted", nil, false, false, false},
+		{"absent trusted", nil, true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newMockOIDC(t)
+			idp.nonce = "n-1"
+			idp.claims = jwtlib.MapClaims{"email_verified": tt.claim}
+			p := idp.provider(t).(*oidcProvider)
+			p.cfg.TrustEmail = tt.trust
+
+			identity, err := p.Authenticate(context.Background(), testCallbackURL, url.Values{"code": {"good-code"}}, "n-1")
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if identity.EmailVerified != tt.verified {
+				t.Errorf("EmailVerified = %v, want %v", identity.EmailVerified, tt.verified)
+			}
+			if identity.EmailTrusted != tt.trusted {
+				t.Errorf("EmailTrusted = %v, want %v", identity.EmailTrusted, tt.trusted)
+			}
+		})
+	}
+}
+
+// newMockCAS 本地模拟的CAS服务：票据 "ST-good" 对应的 service 必须与登录时一致
+func newMockCAS(t *testing.T, wantPath string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		q := r.URL.Query()
+		if r.URL.Path != wantPath || q.Get("ticket") != "ST-good" || q.Get("service") != testCallbackURL+"?state=st-1" {
+			fmt.Fprint(w, `<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">
+  <cas:authenticationFailure code="INVALID_TICKET">Ticket not recognized</cas:authenticationFailure>
+</cas:serviceResponse>`)
+			return
+		}
+		fmt.Fprint(w, `<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">
+  <cas:authenticationSuccess>
+    <cas:user>2022012345</cas:user>
+    <cas:attributes>
+      <cas:mail>lisi@campus.edu.cn</cas:mail>
+      <cas:department>软件工程</cas:department>
+      <cas:department>计算机学院</cas:department>
+      <cas:enrollYear>2022</cas:enrollYear>
+    </cas:attributes>
+  </cas:authenticationSuccess>
+</cas:serviceResponse>`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func casConfig(casURL, version string) config.SSOProviderConfig {
+	return config.SSOProviderConfig{
+		ID:         "campus",
+		Protocol:   config.SSOProtocolCAS,
+		CASURL:     casURL + "/cas",
+		CASVersion: version,
+		TrustEmail: true,
+		Attributes: config.SSOAttributeMapping{Email: "mail", Major: "department", Grade: "enrollYear"},
+	}
+}
+
+func TestCASTicketValidation(t *testing.T) {
+	for _, tt := range []struct{ version, path string }{{"3.0", "/cas/p3/serviceValidate"}, {"2.0", "/cas/serviceValidate"}} {
+		t.Run(tt.version, func(t *testing.T) {
+			server := newMockCAS(t, tt.path)
+			p, err := New(casConfig(server.URL, tt.version), server.Client())
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+
+			authURL, err := p.AuthURL(ctx, testCallbackURL, "st-1", "")
+			if err != nil {
+				t.Fatalf("AuthURL() error = %v", err)
+			}
+			if want := server.URL + "/cas/login?service=" + url.QueryEscape(testCallbackURL+"?state=st-1"); authURL != want {
+				t.Errorf("AuthURL() = %s, want %s", authURL, want)
+			}
+
+			identity, err := p.Authenticate(ctx, testCallbackURL, url.Values{"ticket": {"ST-good"}, "state": {"st-1"}}, "")
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if identity.Subject != "2022012345" || identity.Username != "2022012345" ||
+				identity.Email != "lisi@campus.edu.cn" || !identity.EmailVerified || !identity.EmailTrusted || identity.Major != "软件工程" {
+				t.Errorf("Authenticate() = %+v", *identity)
+			}
+			if want := ParseGrade("2022", time.Now()); identity.Grade != want {
+				t.Errorf("Grade = %d, want %d", identity.Grade, want)
+			}
+
+			for name, params := range map[string]url.Values{
+				"missing ticket":   {"state": {"st-1"}},
+				"invalid ticket":   {"ticket": {"ST-bad"}, "state": {"st-1"}},
+				"tampered service": {"ticket": {"ST-good"}, "state": {"st-2"}},
+			} {
+				if _, err := p.Authenticate(ctx, testCallbackURL, params, ""); !errors.Is(err, ErrAuthenticationFailed) {
+					t.Errorf("%s: Authenticate() error = %v, want ErrAuthenticationFailed", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGrade(t *testing.T) {
+	autumn := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	spring := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		now   time.Time
+		want  int
+	}{
+		{"3", autumn, 3},
+		{" 1 ", autumn, 1},
+		{"2025", autumn, 1},
+		{"2025", spring, 1},
+		{"2024", autumn, 2},
+		{"2023", spring, 3},
+		{"2018", autumn, 4},
+		{"2027", autumn, 0},
+		{"大三", autumn, 0},
+		{"", autumn, 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseGrade(tt.value, tt.now); got != tt.want {
+			t.Errorf("ParseGrade(%q, %s) = %d, want %d", tt.value, tt.now.Format("2006-01"), got, tt.want)
+		}
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	attrs := map[string]interface{}{
+		"s":    " x ",
+		"n":    float64(2022),
+		"list": []interface{}{"first", "second"},
+	}
+	for name, want := range map[string]string{"s": "x", "n": "2022", "list": "first", "missing": ""} {
+		if got := attribute(attrs, name); got != want {
+			t.Errorf("attribute(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if !strings.Contains(casService("https://a.test/cb?x=1", "s"), "&state=s") {
+		t.Errorf("casService() should append to an existing query")
+	}
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore 保存在本地目录中的文件，由API服务在 PublicURL 路径下提供；
+// 多实例部署时目录需为共享存储
+type LocalStore struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalStore creates a store rooted at dir whose files are served under publicURL
+func NewLocalStore(dir, publicURL string) *LocalStore {
+	return &LocalStore{dir: dir, publicURL: strings.TrimRight(publicURL, "/")}
+}
+
+// Put 先写入临时文件再重命名，读取方不会看到写了一半的文件
+func (s *LocalStore) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+
+	return s.publicURL + "/" + key, nil
+}
+
+// Delete 删除文件
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// Key 由访问地址反解出 key
+func (s *LocalStore) Key(url string) (string, bool) {
+	if !strings.HasPrefix(url, s.publicURL+"/") {
+		return "", false
+	}
+	key, err := cleanKey(strings.TrimPrefix(url, s.publicURL+"/"))
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// Dir 返回文件保存目录，供路由挂载静态文件服务
+func (s *LocalStore) Dir() string {
+	return s.dir
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/unifocus/backend/internal/config"
+)
+
+// s3RequestTimeout 单次对象存储请求的超时时间
+const s3RequestTimeout = 30 * time.Second
+
+// S3Store 保存在S3兼容对象存储（AWS S3、MinIO、阿里云OSS、腾讯云COS等）中的文件；
+// 请求使用 AWS Signature Version 4 签名，文件的公开读取由存储桶策略或CDN负责
+type S3Store struct {
+	endpoint  *url.URL
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	publicURL string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3Store creates a store in the configured bucket whose files are served under
+// cfg.PublicURL, or directly from the bucket when no public URL is configured
+func NewS3Store(cfg config.StorageConfig) (*S3Store, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid s3 endpoint: %q", cfg.Endpoint)
+	}
+
+	s := &S3Store{
+		endpoint:  endpoint,
+		bucket:    cfg.Bucket,
+		region:    cfg.Region,
+		accessKey: cfg.AccessKeyID,
+		secretKey: cfg.SecretAccessKey,
+		pathStyle: cfg.PathStyle,
+		publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+		client:    &http.Client{Timeout: s3RequestTimeout},
+		now:       time.Now,
+	}
+	if s.publicURL == "" {
+		s.publicURL = strings.TrimRight(s.bucketURL().String(), "/")
+	}
+	return s, nil
+}
+
+// Put 上传对象，同名对象被覆盖
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	if err := s.do(ctx, http.MethodPut, key, header, data, http.StatusOK); err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	return s.publicURL + "/" + key, nil
+}
+
+// Delete 删除对象；S3对不存在的对象同样返回204
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err := s.do(ctx, http.MethodDelete, key, http.Header{}, nil, http.StatusNoContent, http.StatusOK, http.StatusNotFound); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// Key 由访问地址反解出 key
+func (s *S3Store) Key(url string) (string, bool) {
+	if !strings.HasPrefix(url, s.publicURL+"/") {
+		return "", false
+	}
+	key, err := cleanKey(strings.TrimPrefix(url, s.publicURL+"/"))
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// bucketURL 存储桶地址：路径形式为 {endpoint}/{bucket}，否则为 {bucket}.{endpoint host}
+func (s *S3Store) bucketURL() *url.URL {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + s.bucket
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	return &u
+}
+
+// do 发送签名请求，状态码不在 accepted 中时返回响应中的错误信息
+func (s *S3Store) do(ctx context.Context, method, key string, header http.Header, body []byte, accepted ...int) error {
+	u := s.bucketURL()
+	u.Path = strings.TrimRight(u.Path, "/") + "/" + key
+	u.RawPath = escapePath(u.Path)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	s.sign(req, body, s.now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	for _, status := range accepted {
+		if resp.StatusCode == status {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			return nil
+		}
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("object storage returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// sign 按 AWS Signature Version 4 为请求添加 Authorization 等请求头
+func (s *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		signed[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// escapePath 按 RFC 3986 编码路径，保留 /（S3签名要求的规范形式）
+func escapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,49 @@
+// Package storage stores user-uploaded files such as avatars and returns their public URLs
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/unifocus/backend/internal/config"
+)
+
+// ErrInvalidKey indicates a key that is empty or escapes the storage root
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Store 文件存储后端
+type Store interface {
+	// Put 保存文件并返回其访问地址；同一 key 已存在时覆盖
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Delete 删除文件，文件不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// Key 由本存储返回的访问地址反解出 key，不是本存储的地址时返回 false
+	Key(url string) (string, bool)
+}
+
+// New 按配置创建存储后端
+func New(cfg config.StorageConfig) (Store, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL), nil
+	case "s3":
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Driver)
+	}
+}
+
+// cleanKey 校验 key：使用 / 分隔的相对路径，不能包含 .. 等跳出根目录的部分
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,202 @@
+// Package taxonomy normalizes free-text skills and majors to canonical
+// taxonomy terms and answers hierarchy questions between them.
+package taxonomy
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+// 词条类型
+const (
+	KindSkill = "skill"
+	KindMajor = "major"
+)
+
+// 专业层级（学科门类 → 专业类 → 专业）
+const (
+	LevelDiscipline = "discipline"
+	LevelCategory   = "category"
+	LevelMajor      = "major"
+)
+
+// Relation 两个取值在分类体系中的关系
+type Relation int
+
+const (
+	// RelationUnknown 至少一方不在分类体系中
+	RelationUnknown Relation = iota
+	// RelationUnrelated 双方都在分类体系中但不在同一分支
+	RelationUnrelated
+	// RelationSame 规范化后为同一词条
+	RelationSame
+	// RelationAncestor 第一个取值是第二个的上级（如 计算机类 与 软件工程）
+	RelationAncestor
+	// RelationDescendant 第一个取值是第二个的下级
+	RelationDescendant
+)
+
+// levelRank 同名词条冲突时优先匹配更具体的层级
+var levelRank = map[string]int{
+	LevelDiscipline: 1,
+	LevelCategory:   2,
+	LevelMajor:      3,
+}
+
+// Index is an immutable in-memory lookup over taxonomy terms
+// A nil *Index is valid and behaves as an empty taxonomy
+type Index struct {
+	byID   map[int64]*domain.TaxonomyTerm
+	lookup map[string]map[string]*domain.TaxonomyTerm // kind -> key -> term
+}
+
+// NewIndex builds an index from active terms
+// Codes and canonical names take precedence over synonyms when keys collide
+func NewIndex(terms []*domain.TaxonomyTerm) *Index {
+	idx := &Index{
+		byID:   make(map[int64]*domain.TaxonomyTerm, len(terms)),
+		lookup: make(map[string]map[string]*domain.TaxonomyTerm),
+	}
+
+	active := make([]*domain.TaxonomyTerm, 0, len(terms))
+	for _, term := range terms {
+		if !term.IsActive {
+			continue
+		}
+		active = append(active, term)
+		idx.byID[term.ID] = term
+		if idx.lookup[term.Kind] == nil {
+			idx.lookup[term.Kind] = make(map[string]*domain.TaxonomyTerm)
+		}
+	}
+
+	// 第一轮：规范名称和代码
+	for _, term := range active {
+		idx.add(term, term.Name, true)
+		idx.add(term, term.Code, true)
+	}
+	// 第二轮：同义词，不覆盖已有的名称/代码
+	for _, term := range active {
+		for _, synonym := range term.Synonyms {
+			idx.add(term, synonym, false)
+		}
+	}
+
+	return idx
+}
+
+// add registers key for term; on conflict the more specific level wins
+func (i *Index) add(term *domain.TaxonomyTerm, value string, override bool) {
+	key := Key(value)
+	if key == "" {
+		return
+	}
+
+	keys := i.lookup[term.Kind]
+	existing, ok := keys[key]
+	if ok && (!override || levelRank[existing.Level] >= levelRank[term.Level]) {
+		return
+	}
+	keys[key] = term
+}
+
+// Len returns the number of indexed terms
+func (i *Index) Len() int {
+	if i == nil {
+		return 0
+	}
+	return len(i.byID)
+}
+
+// Lookup finds the term matching a code, canonical name or synonym
+func (i *Index) Lookup(kind, value string) (*domain.TaxonomyTerm, bool) {
+	if i == nil {
+		return nil, false
+	}
+	term, ok := i.lookup[kind][Key(value)]
+	return term, ok
+}
+
+// Normalize returns the canonical name of value, or the trimmed input when unknown
+func (i *Index) Normalize(kind, value string) string {
+	if term, ok := i.Lookup(kind, value); ok {
+		return term.Name
+	}
+	return strings.TrimSpace(value)
+}
+
+// NormalizeAll normalizes a list of values, dropping empty values and duplicates
+func (i *Index) NormalizeAll(kind string, values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		name := i.Normalize(kind, value)
+		key := Key(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// Ancestors returns the parents of a term from nearest to root
+func (i *Index) Ancestors(term *domain.TaxonomyTerm) []*domain.TaxonomyTerm {
+	if i == nil || term == nil {
+		return nil
+	}
+
+	var ancestors []*domain.TaxonomyTerm
+	visited := map[int64]bool{term.ID: true}
+	for term.ParentID != nil {
+		parent, ok := i.byID[*term.ParentID]
+		if !ok || visited[parent.ID] {
+			break
+		}
+		visited[parent.ID] = true
+		ancestors = append(ancestors, parent)
+		term = parent
+	}
+	return ancestors
+}
+
+// Relation reports how value a relates to value b within the hierarchy of kind
+func (i *Index) Relation(kind, a, b string) Relation {
+	ta, okA := i.Lookup(kind, a)
+	tb, okB := i.Lookup(kind, b)
+	if !okA || !okB {
+		return RelationUnknown
+	}
+
+	if ta.ID == tb.ID {
+		return RelationSame
+	}
+	for _, ancestor := range i.Ancestors(tb) {
+		if ancestor.ID == ta.ID {
+			return RelationAncestor
+		}
+	}
+	for _, ancestor := range i.Ancestors(ta) {
+		if ancestor.ID == tb.ID {
+			return RelationDescendant
+		}
+	}
+	return RelationUnrelated
+}
+
+// Key returns the lookup key of a value: lower-cased with all whitespace removed
+func Key(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, value)
+}
@@ -0,0 +1,185 @@
+package taxonomy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/unifocus/backend/internal/domain"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func testIndex() *Index {
+	return NewIndex([]*domain.TaxonomyTerm{
+		{ID: 1, Kind: KindMajor, Code: "08", Name: "工学", Level: LevelDiscipline, IsActive: true},
+		{ID: 2, Kind: KindMajor, Code: "0809", Name: "计算机类", Level: LevelCategory, ParentID: int64Ptr(1), IsActive: true},
+		{ID: 3, Kind: KindMajor, Code: "080901", Name: "计算机科学与技术", Level: LevelMajor, ParentID: int64Ptr(2), Synonyms: []string{"计科", "CS"}, IsActive: true},
+		{ID: 4, Kind: KindMajor, Code: "080902", Name: "软件工程", Level: LevelMajor, ParentID: int64Ptr(2), Synonyms: []string{"软工"}, IsActive: true},
+		{ID: 5, Kind: KindMajor, Code: "1001", Name: "临床医学", Level: LevelMajor, IsActive: true},
+		// 与专业类同名的专业，查找时应优先命中更具体的层级
+		{ID: 6, Kind: KindMajor, Code: "0807", Name: "电子信息类", Level: LevelCategory, IsActive: true},
+		{ID: 7, Kind: KindMajor, Code: "080799", Name: "电子信息类", Level: LevelMajor, ParentID: int64Ptr(6), IsActive: true},
+		{ID: 10, Kind: KindSkill, Code: "python", Name: "Python", Synonyms: []string{"py", "Python3"}, IsActive: true},
+		{ID: 11, Kind: KindSkill, Code: "javascript", Name: "JavaScript", Synonyms: []string{"JS", "ECMAScript"}, IsActive: true},
+		{ID: 12, Kind: KindSkill, Code: "ml", Name: "Machine Learning", Synonyms: []string{"机器学习", "CS"}, IsActive: true},
+		{ID: 13, Kind: KindSkill, Code: "perl", Name: "Perl", IsActive: false},
+	})
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Python", "python"},
+		{"  Machine  Learning ", "machinelearning"},
+		{"C++", "c++"},
+		{"计算机\t科学\n与技术", "计算机科学与技术"},
+		{"　全角空格　", "全角空格"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := Key(tt.value); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexLookup(t *testing.T) {
+	idx := testIndex()
+
+	tests := []struct {
+		name   string
+		kind   string
+		value  string
+		wantID int64
+		wantOK bool
+	}{
+		{"canonical name", KindSkill, "Python", 10, true},
+		{"case folding", KindSkill, "PYTHON", 10, true},
+		{"whitespace folding", KindSkill, " machine learning ", 12, true},
+		{"synonym", KindSkill, "js", 11, true},
+		{"chinese synonym", KindSkill, "机器学习", 12, true},
+		{"code", KindMajor, "080902", 4, true},
+		{"major synonym", KindMajor, "软工", 4, true},
+		{"synonym scoped to kind", KindMajor, "cs", 3, true},
+		{"more specific level wins", KindMajor, "电子信息类", 7, true},
+		{"wrong kind", KindSkill, "软件工程", 0, false},
+		{"inactive term", KindSkill, "Perl", 0, false},
+		{"unknown", KindSkill, "Rust", 0, false},
+		{"empty", KindSkill, "  ", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, ok := idx.Lookup(tt.kind, tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("Lookup(%q, %q) ok = %v, want %v", tt.kind, tt.value, ok, tt.wantOK)
+			}
+			if ok && term.ID != tt.wantID {
+				t.Errorf("Lookup(%q, %q) = term %d, want %d", tt.kind, tt.value, term.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestNormalizeAll(t *testing.T) {
+	idx := testIndex()
+
+	tests := []struct {
+		name   string
+		idx    *Index
+		values []string
+		want   []string
+	}{
+		{"nil values", idx, nil, nil},
+		{"synonyms to canonical", idx, []string{"py", "JS"}, []string{"Python", "JavaScript"}},
+		{"duplicates after normalization", idx, []string{"Python", "python3", " PY "}, []string{"Python"}},
+		{"unknown kept trimmed", idx, []string{" Rust ", "Python"}, []string{"Rust", "Python"}},
+		{"unknown deduplicated by key", idx, []string{"Deep Learning", "deeplearning"}, []string{"Deep Learning"}},
+		{"empty values dropped", idx, []string{"", "  ", "ECMAScript"}, []string{"JavaScript"}},
+		{"nil index trims only", nil, []string{" py ", "py"}, []string{"py"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.idx.NormalizeAll(KindSkill, tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeAll(%q) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	idx := testIndex()
+
+	tests := []struct {
+		name  string
+		value string
+		want  []int64
+	}{
+		{"major to discipline", "软件工程", []int64{2, 1}},
+		{"category", "计算机类", []int64{1}},
+		{"root", "工学", nil},
+		{"no parent", "临床医学", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, ok := idx.Lookup(KindMajor, tt.value)
+			if !ok {
+				t.Fatalf("Lookup(%q) not found", tt.value)
+			}
+			var got []int64
+			for _, ancestor := range idx.Ancestors(term) {
+				got = append(got, ancestor.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ancestors(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAncestorsCycle(t *testing.T) {
+	idx := NewIndex([]*domain.TaxonomyTerm{
+		{ID: 1, Kind: KindMajor, Name: "A", ParentID: int64Ptr(2), IsActive: true},
+		{ID: 2, Kind: KindMajor, Name: "B", ParentID: int64Ptr(1), IsActive: true},
+	})
+
+	term, _ := idx.Lookup(KindMajor, "A")
+	if got := idx.Ancestors(term); len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("Ancestors() = %v, want [B]", got)
+	}
+}
+
+func TestRelation(t *testing.T) {
+	idx := testIndex()
+
+	tests := []struct {
+		name string
+		a, b string
+		want Relation
+	}{
+		{"same via synonym", "计科", "计算机科学与技术", RelationSame},
+		{"parent", "计算机类", "软件工程", RelationAncestor},
+		{"grandparent", "工学", "软工", RelationAncestor},
+		{"child", "软件工程", "计算机类", RelationDescendant},
+		{"siblings", "软件工程", "计算机科学与技术", RelationUnrelated},
+		{"other branch", "临床医学", "计算机类", RelationUnrelated},
+		{"unknown", "软件工程", "金融学", RelationUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idx.Relation(KindMajor, tt.a, tt.b); got != tt.want {
+				t.Errorf("Relation(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
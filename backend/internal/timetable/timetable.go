@@ -0,0 +1,328 @@
+// Package timetable parses course timetables exported as CSV.
+//
+// The first row is a header; columns are matched by name (Chinese or English,
+// case-insensitive) so their order does not matter:
+//
+//	课程名称/title, 星期/weekday, 节次/periods, 周次/weeks, 地点/location, 教师/teacher
+//
+// Weekdays accept 1-7, 周一/星期一 or Mon/Monday. Periods accept "1-2", "3" or
+// "第1-2节". Weeks accept lists of ranges such as "1-8,10-16", "1-16周" and
+// odd/even markers like "1-16单" or "2-16(双周)".
+package timetable
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 单个学期允许的最大周次与节次
+const (
+	MaxWeek   = 30
+	MaxPeriod = 20
+)
+
+// maxRows 单个文件允许的最大行数
+const maxRows = 2000
+
+// ErrInvalidTimetable indicates the CSV header is missing required columns
+var ErrInvalidTimetable = errors.New("invalid timetable")
+
+// Entry is one row of a timetable
+type Entry struct {
+	Line        int // 文件中的行号（表头为第1行）
+	Title       string
+	Weekday     time.Weekday
+	FirstPeriod int
+	LastPeriod  int
+	Weeks       []int // 升序、去重
+	Location    string
+	Teacher     string
+}
+
+// LineError describes a row that could not be parsed
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// 列名别名（小写、去空格）
+var columnAliases = map[string][]string{
+	"title":    {"title", "course", "name", "课程", "课程名", "课程名称"},
+	"weekday":  {"weekday", "day", "星期", "上课星期"},
+	"periods":  {"periods", "period", "节次", "上课节次"},
+	"weeks":    {"weeks", "week", "周次", "上课周次"},
+	"location": {"location", "room", "地点", "教室", "上课地点"},
+	"teacher":  {"teacher", "instructor", "教师", "任课教师"},
+}
+
+// requiredColumns 必须存在的列
+var requiredColumns = []string{"title", "weekday", "periods", "weeks"}
+
+// weekdayAliases 星期名称
+var weekdayAliases = map[string]time.Weekday{
+	"1": time.Monday, "2": time.Tuesday, "3": time.Wednesday, "4": time.Thursday,
+	"5": time.Friday, "6": time.Saturday, "7": time.Sunday,
+	"一": time.Monday, "二": time.Tuesday, "三": time.Wednesday, "四": time.Thursday,
+	"五": time.Friday, "六": time.Saturday, "日": time.Sunday, "天": time.Sunday,
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// ParseCSV reads a timetable; rows with invalid values are reported instead of failing the file
+func ParseCSV(r io.Reader) ([]*Entry, []*LineError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("%w: empty file", ErrInvalidTimetable)
+		}
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTimetable, err)
+	}
+	columns, err := mapColumns(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []*Entry
+	var invalid []*LineError
+	for rows := 1; ; rows++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			line := 0
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+			}
+			invalid = append(invalid, &LineError{Line: line, Err: err})
+			continue
+		}
+		if rows > maxRows {
+			return nil, nil, fmt.Errorf("%w: more than %d rows", ErrInvalidTimetable, maxRows)
+		}
+		if isBlank(record) {
+			continue
+		}
+		// 空行被csv.Reader跳过，行号取记录在文件中的实际位置
+		line, _ := reader.FieldPos(0)
+
+		entry, err := parseRecord(record, columns)
+		if err != nil {
+			invalid = append(invalid, &LineError{Line: line, Err: err})
+			continue
+		}
+		entry.Line = line
+		entries = append(entries, entry)
+	}
+
+	return entries, invalid, nil
+}
+
+// mapColumns 根据表头确定各字段所在列
+func mapColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.Join(strings.Fields(strings.TrimPrefix(name, "\ufeff")), ""))
+		for field, aliases := range columnAliases {
+			for _, alias := range aliases {
+				if key == alias {
+					if _, ok := columns[field]; !ok {
+						columns[field] = i
+					}
+				}
+			}
+		}
+	}
+
+	var missing []string
+	for _, field := range requiredColumns {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing columns %s", ErrInvalidTimetable, strings.Join(missing, ", "))
+	}
+
+	return columns, nil
+}
+
+func parseRecord(record []string, columns map[string]int) (*Entry, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	entry := &Entry{
+		Title:    field("title"),
+		Location: field("location"),
+		Teacher:  field("teacher"),
+	}
+	if entry.Title == "" {
+		return nil, errors.New("title is required")
+	}
+
+	weekday, err := ParseWeekday(field("weekday"))
+	if err != nil {
+		return nil, err
+	}
+	entry.Weekday = weekday
+
+	entry.FirstPeriod, entry.LastPeriod, err = ParsePeriods(field("periods"))
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Weeks, err = ParseWeeks(field("weeks"))
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// ParseWeekday 解析星期：1-7、周一/星期一/礼拜一、Mon/Monday
+func ParseWeekday(value string) (time.Weekday, error) {
+	key := strings.ToLower(strings.TrimSpace(value))
+	for _, prefix := range []string{"星期", "周", "礼拜"} {
+		key = strings.TrimPrefix(key, prefix)
+	}
+	if len(key) > 3 && key[0] < 0x80 {
+		key = key[:3]
+	}
+
+	if day, ok := weekdayAliases[key]; ok {
+		return day, nil
+	}
+	return 0, fmt.Errorf("invalid weekday %q", value)
+}
+
+// ParsePeriods 解析节次："1-2"、"3"、"第1-2节"、"1,2"（取首尾）
+func ParsePeriods(value string) (int, int, error) {
+	v := strings.TrimSpace(value)
+	v = strings.TrimPrefix(v, "第")
+	v = strings.TrimSuffix(v, "节")
+
+	numbers, err := parseNumbers(v)
+	if err != nil || len(numbers) == 0 {
+		return 0, 0, fmt.Errorf("invalid periods %q", value)
+	}
+
+	first, last := numbers[0], numbers[len(numbers)-1]
+	if first < 1 || last > MaxPeriod || last < first {
+		return 0, 0, fmt.Errorf("invalid periods %q", value)
+	}
+	return first, last, nil
+}
+
+// ParseWeeks 解析周次："1-16"、"1-8,10-16"、"1-16周"、"1-16单"、"2-16(双周)"
+// 单/双标记作用于其所在的区间
+func ParseWeeks(value string) ([]int, error) {
+	v := strings.NewReplacer("，", ",", "、", ",", "（", "(", "）", ")", " ", "").Replace(strings.TrimSpace(value))
+	if v == "" {
+		return nil, fmt.Errorf("invalid weeks %q", value)
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(v, ",") {
+		if part == "" {
+			continue
+		}
+
+		parity := 0 // 0全部 1单周 2双周
+		part = strings.NewReplacer("(", "", ")", "", "第", "").Replace(part)
+		switch {
+		case strings.Contains(part, "单"):
+			parity = 1
+		case strings.Contains(part, "双"):
+			parity = 2
+		}
+		part = strings.NewReplacer("单", "", "双", "", "周", "").Replace(part)
+
+		numbers, err := parseNumbers(part)
+		if err != nil || len(numbers) == 0 {
+			return nil, fmt.Errorf("invalid weeks %q", value)
+		}
+		for _, week := range numbers {
+			if week < 1 || week > MaxWeek {
+				return nil, fmt.Errorf("week %d out of range 1-%d", week, MaxWeek)
+			}
+			if parity == 1 && week%2 == 0 || parity == 2 && week%2 == 1 {
+				continue
+			}
+			seen[week] = true
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("invalid weeks %q", value)
+	}
+
+	weeks := make([]int, 0, len(seen))
+	for week := range seen {
+		weeks = append(weeks, week)
+	}
+	sort.Ints(weeks)
+	return weeks, nil
+}
+
+// parseNumbers 解析单个数字、"a-b"区间或逗号分隔列表
+func parseNumbers(value string) ([]int, error) {
+	var numbers []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(strings.ReplaceAll(part, "~", "-"), "-", 2)
+		from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, err = strconv.Atoi(strings.TrimSpace(bounds[1])); err != nil {
+				return nil, err
+			}
+		}
+		if to < from || to-from > MaxWeek+MaxPeriod {
+			return nil, fmt.Errorf("invalid range %q", part)
+		}
+		for n := from; n <= to; n++ {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers, nil
+}
+
+// isBlank 判断是否为空行
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package timetable
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWeeks(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []int
+		wantErr bool
+	}{
+		{value: "1-4", want: []int{1, 2, 3, 4}},
+		{value: "3", want: []int{3}},
+		{value: "1-3,6-7", want: []int{1, 2, 3, 6, 7}},
+		{value: "1-3，6、8", want: []int{1, 2, 3, 6, 8}},
+		{value: "1-4周", want: []int{1, 2, 3, 4}},
+		{value: "第1-4周", want: []int{1, 2, 3, 4}},
+		{value: "1~4", want: []int{1, 2, 3, 4}},
+		{value: "1-8单", want: []int{1, 3, 5, 7}},
+		{value: "2-8(双周)", want: []int{2, 4, 6, 8}},
+		{value: "1-7（单）", want: []int{1, 3, 5, 7}},
+		{value: "1-5单,6-8", want: []int{1, 3, 5, 6, 7, 8}},
+		{value: "5-8,1-4,3", want: []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{value: " 1 - 3 ", want: []int{1, 2, 3}},
+		{value: "", wantErr: true},
+		{value: "周", wantErr: true},
+		{value: "8-1", wantErr: true},
+		{value: "0-3", wantErr: true},
+		{value: "1-31", wantErr: true},
+		{value: "2双", want: []int{2}},
+		{value: "1双", wantErr: true},
+		{value: "a-b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseWeeks(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseWeeks(%q) = %v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWeeks(%q) error = %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWeeks(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePeriods(t *testing.T) {
+	tests := []struct {
+		value       string
+		first, last int
+		wantErr     bool
+	}{
+		{value: "1-2", first: 1, last: 2},
+		{value: "3", first: 3, last: 3},
+		{value: "第3-4节", first: 3, last: 4},
+		{value: "5,6,7", first: 5, last: 7},
+		{value: "0-2", wantErr: true},
+		{value: "4-3", wantErr: true},
+		{value: "1-21", wantErr: true},
+		{value: "节", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			first, last, err := ParsePeriods(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriods(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && (first != tt.first || last != tt.last) {
+				t.Errorf("ParsePeriods(%q) = %d-%d, want %d-%d", tt.value, first, last, tt.first, tt.last)
+			}
+		})
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Weekday
+		wantErr bool
+	}{
+		{value: "1", want: time.Monday},
+		{value: "7", want: time.Sunday},
+		{value: "周三", want: time.Wednesday},
+		{value: "星期日", want: time.Sunday},
+		{value: "礼拜天", want: time.Sunday},
+		{value: "Fri", want: time.Friday},
+		{value: "Thursday", want: time.Thursday},
+		{value: "8", wantErr: true},
+		{value: "周八", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseWeekday(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeekday(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseWeekday(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "周次,课程名称,星期,节次,地点\n" +
+		"1-8,高等数学,周一,1-2,A101\n" +
+		"\n" +
+		"10-16双,高等数学,周一,1-2,A101\n" +
+		"1-16,大学物理,九,3-4,B202\n"
+
+	entries, invalid, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ParseCSV() returned %d entries, want 2", len(entries))
+	}
+	if got := entries[1]; got.Line != 4 || !reflect.DeepEqual(got.Weeks, []int{10, 12, 14, 16}) || got.Location != "A101" {
+		t.Errorf("entries[1] = %+v", got)
+	}
+	if len(invalid) != 1 || invalid[0].Line != 5 {
+		t.Errorf("ParseCSV() invalid = %v, want line 5", invalid)
+	}
+
+	if _, _, err := ParseCSV(strings.NewReader("课程名称,星期\n")); !errors.Is(err, ErrInvalidTimetable) {
+		t.Errorf("ParseCSV() with missing columns error = %v, want ErrInvalidTimetable", err)
+	}
+}
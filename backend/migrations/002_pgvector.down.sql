@@ -0,0 +1,7 @@
+-- 002_pgvector.down.sql
+-- 回滚pgvector相关对象
+
+DROP INDEX IF EXISTS idx_opportunities_has_vector;
+
+-- 扩展可能被其他数据库对象使用，默认不删除
+-- DROP EXTENSION IF EXISTS vector;
@@ -0,0 +1,16 @@
+-- 002_pgvector.up.sql
+-- 启用pgvector扩展（可选）
+-- 扩展不可用时（如未安装pgvector的PostgreSQL镜像）迁移依然成功，
+-- 后端会自动退化为在Go中计算余弦相似度
+
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS vector;
+EXCEPTION WHEN OTHERS THEN
+    RAISE NOTICE 'pgvector extension not available, vector search will fall back to in-process similarity';
+END
+$$;
+
+-- 便于筛选已向量化的机会
+CREATE INDEX IF NOT EXISTS idx_opportunities_has_vector
+    ON opportunities(id) WHERE description_vector IS NOT NULL;
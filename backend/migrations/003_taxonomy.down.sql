@@ -0,0 +1,5 @@
+-- 003_taxonomy.down.sql
+-- 回滚分类体系
+
+DROP TRIGGER IF EXISTS update_taxonomy_terms_updated_at ON taxonomy_terms;
+DROP TABLE IF EXISTS taxonomy_terms;
@@ -0,0 +1,339 @@
+-- 003_taxonomy.up.sql
+-- 技能与专业分类体系（规范ID、同义词、层级）
+
+-- ============================================
+-- 1. 分类词条表
+-- ============================================
+-- kind: skill/major
+-- level: 专业为 discipline(学科门类)/category(专业类)/major(专业)，技能为空
+-- code: 同一kind内唯一的规范ID，专业使用《普通高等学校本科专业目录》代码
+CREATE TABLE taxonomy_terms (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    level VARCHAR(20),
+    parent_id BIGINT REFERENCES taxonomy_terms(id) ON DELETE SET NULL,
+    synonyms TEXT[] DEFAULT ARRAY[]::TEXT[],
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(kind, code)
+);
+
+CREATE INDEX idx_taxonomy_terms_kind ON taxonomy_terms(kind);
+CREATE INDEX idx_taxonomy_terms_parent ON taxonomy_terms(parent_id);
+CREATE INDEX idx_taxonomy_terms_name ON taxonomy_terms(name);
+CREATE INDEX idx_taxonomy_terms_synonyms ON taxonomy_terms USING GIN(synonyms);
+
+CREATE TRIGGER update_taxonomy_terms_updated_at BEFORE UPDATE ON taxonomy_terms
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 2. 种子数据：本科专业目录
+-- ============================================
+-- 收录全部学科门类、专业类及各类常见基本专业，其余专业可通过管理接口补充
+-- 学科门类
+INSERT INTO taxonomy_terms (kind, code, name, level, synonyms) VALUES
+('major', '01', '哲学', 'discipline', ARRAY[]::TEXT[]),
+('major', '02', '经济学', 'discipline', ARRAY[]::TEXT[]),
+('major', '03', '法学', 'discipline', ARRAY[]::TEXT[]),
+('major', '04', '教育学', 'discipline', ARRAY[]::TEXT[]),
+('major', '05', '文学', 'discipline', ARRAY[]::TEXT[]),
+('major', '06', '历史学', 'discipline', ARRAY[]::TEXT[]),
+('major', '07', '理学', 'discipline', ARRAY['理科']),
+('major', '08', '工学', 'discipline', ARRAY['工科']),
+('major', '09', '农学', 'discipline', ARRAY['农科']),
+('major', '10', '医学', 'discipline', ARRAY['医科']),
+('major', '12', '管理学', 'discipline', ARRAY[]::TEXT[]),
+('major', '13', '艺术学', 'discipline', ARRAY['艺术']);
+
+-- 专业类
+INSERT INTO taxonomy_terms (kind, code, name, level, synonyms) VALUES
+('major', '0101', '哲学类', 'category', ARRAY[]::TEXT[]),
+('major', '0201', '经济学类', 'category', ARRAY['经济']),
+('major', '0202', '财政学类', 'category', ARRAY['财政']),
+('major', '0203', '金融学类', 'category', ARRAY['金融']),
+('major', '0204', '经济与贸易类', 'category', ARRAY['经贸']),
+('major', '0301', '法学类', 'category', ARRAY[]::TEXT[]),
+('major', '0302', '政治学类', 'category', ARRAY[]::TEXT[]),
+('major', '0303', '社会学类', 'category', ARRAY[]::TEXT[]),
+('major', '0304', '民族学类', 'category', ARRAY[]::TEXT[]),
+('major', '0305', '马克思主义理论类', 'category', ARRAY[]::TEXT[]),
+('major', '0306', '公安学类', 'category', ARRAY[]::TEXT[]),
+('major', '0401', '教育学类', 'category', ARRAY['教育']),
+('major', '0402', '体育学类', 'category', ARRAY['体育']),
+('major', '0501', '中国语言文学类', 'category', ARRAY[]::TEXT[]),
+('major', '0502', '外国语言文学类', 'category', ARRAY['外语']),
+('major', '0503', '新闻传播学类', 'category', ARRAY['新闻传播']),
+('major', '0601', '历史学类', 'category', ARRAY[]::TEXT[]),
+('major', '0701', '数学类', 'category', ARRAY['数学']),
+('major', '0702', '物理学类', 'category', ARRAY[]::TEXT[]),
+('major', '0703', '化学类', 'category', ARRAY[]::TEXT[]),
+('major', '0704', '天文学类', 'category', ARRAY[]::TEXT[]),
+('major', '0705', '地理科学类', 'category', ARRAY[]::TEXT[]),
+('major', '0706', '大气科学类', 'category', ARRAY[]::TEXT[]),
+('major', '0707', '海洋科学类', 'category', ARRAY[]::TEXT[]),
+('major', '0708', '地球物理学类', 'category', ARRAY[]::TEXT[]),
+('major', '0709', '地质学类', 'category', ARRAY[]::TEXT[]),
+('major', '0710', '生物科学类', 'category', ARRAY['生物']),
+('major', '0711', '心理学类', 'category', ARRAY[]::TEXT[]),
+('major', '0712', '统计学类', 'category', ARRAY['统计']),
+('major', '0801', '力学类', 'category', ARRAY['力学']),
+('major', '0802', '机械类', 'category', ARRAY['机械']),
+('major', '0803', '仪器类', 'category', ARRAY['仪器']),
+('major', '0804', '材料类', 'category', ARRAY['材料']),
+('major', '0805', '能源动力类', 'category', ARRAY['能源动力']),
+('major', '0806', '电气类', 'category', ARRAY['电气']),
+('major', '0807', '电子信息类', 'category', ARRAY['电子', '电子信息']),
+('major', '0808', '自动化类', 'category', ARRAY[]::TEXT[]),
+('major', '0809', '计算机类', 'category', ARRAY['计算机', '计算机相关专业']),
+('major', '0810', '土木类', 'category', ARRAY['土木']),
+('major', '0811', '水利类', 'category', ARRAY['水利']),
+('major', '0812', '测绘类', 'category', ARRAY['测绘']),
+('major', '0813', '化工与制药类', 'category', ARRAY['化工']),
+('major', '0814', '地质类', 'category', ARRAY[]::TEXT[]),
+('major', '0815', '矿业类', 'category', ARRAY['矿业']),
+('major', '0816', '纺织类', 'category', ARRAY['纺织']),
+('major', '0817', '轻工类', 'category', ARRAY['轻工']),
+('major', '0818', '交通运输类', 'category', ARRAY[]::TEXT[]),
+('major', '0819', '海洋工程类', 'category', ARRAY[]::TEXT[]),
+('major', '0820', '航空航天类', 'category', ARRAY['航空航天']),
+('major', '0821', '兵器类', 'category', ARRAY[]::TEXT[]),
+('major', '0822', '核工程类', 'category', ARRAY[]::TEXT[]),
+('major', '0823', '农业工程类', 'category', ARRAY[]::TEXT[]),
+('major', '0824', '林业工程类', 'category', ARRAY[]::TEXT[]),
+('major', '0825', '环境科学与工程类', 'category', ARRAY['环境']),
+('major', '0826', '生物医学工程类', 'category', ARRAY[]::TEXT[]),
+('major', '0827', '食品科学与工程类', 'category', ARRAY['食品']),
+('major', '0828', '建筑类', 'category', ARRAY['建筑']),
+('major', '0829', '安全科学与工程类', 'category', ARRAY[]::TEXT[]),
+('major', '0830', '生物工程类', 'category', ARRAY[]::TEXT[]),
+('major', '0831', '公安技术类', 'category', ARRAY[]::TEXT[]),
+('major', '0901', '植物生产类', 'category', ARRAY[]::TEXT[]),
+('major', '0902', '自然保护与环境生态类', 'category', ARRAY[]::TEXT[]),
+('major', '0903', '动物生产类', 'category', ARRAY[]::TEXT[]),
+('major', '0904', '动物医学类', 'category', ARRAY[]::TEXT[]),
+('major', '0905', '林学类', 'category', ARRAY[]::TEXT[]),
+('major', '0906', '水产类', 'category', ARRAY[]::TEXT[]),
+('major', '0907', '草学类', 'category', ARRAY[]::TEXT[]),
+('major', '1001', '基础医学类', 'category', ARRAY[]::TEXT[]),
+('major', '1002', '临床医学类', 'category', ARRAY[]::TEXT[]),
+('major', '1003', '口腔医学类', 'category', ARRAY[]::TEXT[]),
+('major', '1004', '公共卫生与预防医学类', 'category', ARRAY['公共卫生']),
+('major', '1005', '中医学类', 'category', ARRAY[]::TEXT[]),
+('major', '1006', '中西医结合类', 'category', ARRAY[]::TEXT[]),
+('major', '1007', '药学类', 'category', ARRAY[]::TEXT[]),
+('major', '1008', '中药学类', 'category', ARRAY[]::TEXT[]),
+('major', '1009', '法医学类', 'category', ARRAY[]::TEXT[]),
+('major', '1010', '医学技术类', 'category', ARRAY[]::TEXT[]),
+('major', '1011', '护理学类', 'category', ARRAY[]::TEXT[]),
+('major', '1201', '管理科学与工程类', 'category', ARRAY[]::TEXT[]),
+('major', '1202', '工商管理类', 'category', ARRAY[]::TEXT[]),
+('major', '1203', '农业经济管理类', 'category', ARRAY[]::TEXT[]),
+('major', '1204', '公共管理类', 'category', ARRAY[]::TEXT[]),
+('major', '1205', '图书情报与档案管理类', 'category', ARRAY[]::TEXT[]),
+('major', '1206', '物流管理与工程类', 'category', ARRAY[]::TEXT[]),
+('major', '1207', '工业工程类', 'category', ARRAY[]::TEXT[]),
+('major', '1208', '电子商务类', 'category', ARRAY[]::TEXT[]),
+('major', '1209', '旅游管理类', 'category', ARRAY[]::TEXT[]),
+('major', '1301', '艺术学理论类', 'category', ARRAY[]::TEXT[]),
+('major', '1302', '音乐与舞蹈学类', 'category', ARRAY['音乐']),
+('major', '1303', '戏剧与影视学类', 'category', ARRAY[]::TEXT[]),
+('major', '1304', '美术学类', 'category', ARRAY[]::TEXT[]),
+('major', '1305', '设计学类', 'category', ARRAY['设计']);
+
+-- 专业
+INSERT INTO taxonomy_terms (kind, code, name, level, synonyms) VALUES
+('major', '010101', '哲学', 'major', ARRAY[]::TEXT[]),
+('major', '010102', '逻辑学', 'major', ARRAY[]::TEXT[]),
+('major', '020101', '经济学', 'major', ARRAY[]::TEXT[]),
+('major', '020102', '经济统计学', 'major', ARRAY[]::TEXT[]),
+('major', '020201K', '财政学', 'major', ARRAY[]::TEXT[]),
+('major', '020202', '税收学', 'major', ARRAY[]::TEXT[]),
+('major', '020301K', '金融学', 'major', ARRAY[]::TEXT[]),
+('major', '020302', '金融工程', 'major', ARRAY[]::TEXT[]),
+('major', '020303', '保险学', 'major', ARRAY[]::TEXT[]),
+('major', '020304', '投资学', 'major', ARRAY[]::TEXT[]),
+('major', '020401', '国际经济与贸易', 'major', ARRAY['国贸']),
+('major', '020402', '贸易经济', 'major', ARRAY[]::TEXT[]),
+('major', '030101K', '法学', 'major', ARRAY[]::TEXT[]),
+('major', '030201', '政治学与行政学', 'major', ARRAY[]::TEXT[]),
+('major', '030202', '国际政治', 'major', ARRAY[]::TEXT[]),
+('major', '030203', '外交学', 'major', ARRAY[]::TEXT[]),
+('major', '030301', '社会学', 'major', ARRAY[]::TEXT[]),
+('major', '030302', '社会工作', 'major', ARRAY[]::TEXT[]),
+('major', '030503', '思想政治教育', 'major', ARRAY[]::TEXT[]),
+('major', '040101', '教育学', 'major', ARRAY[]::TEXT[]),
+('major', '040104', '教育技术学', 'major', ARRAY[]::TEXT[]),
+('major', '040106', '学前教育', 'major', ARRAY[]::TEXT[]),
+('major', '040107', '小学教育', 'major', ARRAY[]::TEXT[]),
+('major', '040201', '体育教育', 'major', ARRAY[]::TEXT[]),
+('major', '050101', '汉语言文学', 'major', ARRAY['中文']),
+('major', '050102', '汉语言', 'major', ARRAY[]::TEXT[]),
+('major', '050103', '汉语国际教育', 'major', ARRAY[]::TEXT[]),
+('major', '050201', '英语', 'major', ARRAY[]::TEXT[]),
+('major', '050202', '俄语', 'major', ARRAY[]::TEXT[]),
+('major', '050203', '德语', 'major', ARRAY[]::TEXT[]),
+('major', '050204', '法语', 'major', ARRAY[]::TEXT[]),
+('major', '050207', '日语', 'major', ARRAY[]::TEXT[]),
+('major', '050261', '翻译', 'major', ARRAY[]::TEXT[]),
+('major', '050262', '商务英语', 'major', ARRAY[]::TEXT[]),
+('major', '050301', '新闻学', 'major', ARRAY[]::TEXT[]),
+('major', '050302', '广播电视学', 'major', ARRAY[]::TEXT[]),
+('major', '050303', '广告学', 'major', ARRAY[]::TEXT[]),
+('major', '050304', '传播学', 'major', ARRAY[]::TEXT[]),
+('major', '050306T', '网络与新媒体', 'major', ARRAY[]::TEXT[]),
+('major', '060101', '历史学', 'major', ARRAY[]::TEXT[]),
+('major', '060102', '世界史', 'major', ARRAY[]::TEXT[]),
+('major', '070101', '数学与应用数学', 'major', ARRAY['应用数学']),
+('major', '070102', '信息与计算科学', 'major', ARRAY['信计']),
+('major', '070201', '物理学', 'major', ARRAY[]::TEXT[]),
+('major', '070202', '应用物理学', 'major', ARRAY[]::TEXT[]),
+('major', '070301', '化学', 'major', ARRAY[]::TEXT[]),
+('major', '070302', '应用化学', 'major', ARRAY[]::TEXT[]),
+('major', '070501', '地理科学', 'major', ARRAY[]::TEXT[]),
+('major', '070504', '地理信息科学', 'major', ARRAY['GIS']),
+('major', '071001', '生物科学', 'major', ARRAY[]::TEXT[]),
+('major', '071002', '生物技术', 'major', ARRAY[]::TEXT[]),
+('major', '071101', '心理学', 'major', ARRAY[]::TEXT[]),
+('major', '071102', '应用心理学', 'major', ARRAY[]::TEXT[]),
+('major', '071201', '统计学', 'major', ARRAY[]::TEXT[]),
+('major', '071202', '应用统计学', 'major', ARRAY[]::TEXT[]),
+('major', '080101', '理论与应用力学', 'major', ARRAY[]::TEXT[]),
+('major', '080102', '工程力学', 'major', ARRAY[]::TEXT[]),
+('major', '080201', '机械工程', 'major', ARRAY[]::TEXT[]),
+('major', '080202', '机械设计制造及其自动化', 'major', ARRAY['机械设计', '机制']),
+('major', '080203', '材料成型及控制工程', 'major', ARRAY[]::TEXT[]),
+('major', '080204', '机械电子工程', 'major', ARRAY['机电']),
+('major', '080207', '车辆工程', 'major', ARRAY[]::TEXT[]),
+('major', '080301', '测控技术与仪器', 'major', ARRAY['测控']),
+('major', '080401', '材料科学与工程', 'major', ARRAY[]::TEXT[]),
+('major', '080403', '材料化学', 'major', ARRAY[]::TEXT[]),
+('major', '080501', '能源与动力工程', 'major', ARRAY[]::TEXT[]),
+('major', '080601', '电气工程及其自动化', 'major', ARRAY['电气工程']),
+('major', '080701', '电子信息工程', 'major', ARRAY['电信工程']),
+('major', '080702', '电子科学与技术', 'major', ARRAY[]::TEXT[]),
+('major', '080703', '通信工程', 'major', ARRAY['通信']),
+('major', '080704', '微电子科学与工程', 'major', ARRAY['微电子']),
+('major', '080705', '光电信息科学与工程', 'major', ARRAY['光电']),
+('major', '080706', '信息工程', 'major', ARRAY[]::TEXT[]),
+('major', '080710T', '集成电路设计与集成系统', 'major', ARRAY['集成电路']),
+('major', '080714T', '电子信息科学与技术', 'major', ARRAY[]::TEXT[]),
+('major', '080717T', '人工智能', 'major', ARRAY['AI']),
+('major', '080801', '自动化', 'major', ARRAY[]::TEXT[]),
+('major', '080803T', '机器人工程', 'major', ARRAY[]::TEXT[]),
+('major', '080901', '计算机科学与技术', 'major', ARRAY['计科', '计算机科学', 'CS']),
+('major', '080902', '软件工程', 'major', ARRAY['软工']),
+('major', '080903', '网络工程', 'major', ARRAY[]::TEXT[]),
+('major', '080904K', '信息安全', 'major', ARRAY['信安']),
+('major', '080905', '物联网工程', 'major', ARRAY['物联网']),
+('major', '080906', '数字媒体技术', 'major', ARRAY[]::TEXT[]),
+('major', '080907T', '智能科学与技术', 'major', ARRAY[]::TEXT[]),
+('major', '080910T', '数据科学与大数据技术', 'major', ARRAY['大数据', '数据科学']),
+('major', '080911TK', '网络空间安全', 'major', ARRAY['网安']),
+('major', '081001', '土木工程', 'major', ARRAY[]::TEXT[]),
+('major', '081101', '水利水电工程', 'major', ARRAY[]::TEXT[]),
+('major', '081201', '测绘工程', 'major', ARRAY[]::TEXT[]),
+('major', '081301', '化学工程与工艺', 'major', ARRAY[]::TEXT[]),
+('major', '081302', '制药工程', 'major', ARRAY[]::TEXT[]),
+('major', '081801', '交通运输', 'major', ARRAY[]::TEXT[]),
+('major', '081802', '交通工程', 'major', ARRAY[]::TEXT[]),
+('major', '082001', '航空航天工程', 'major', ARRAY[]::TEXT[]),
+('major', '082002', '飞行器设计与工程', 'major', ARRAY[]::TEXT[]),
+('major', '082501', '环境科学与工程', 'major', ARRAY[]::TEXT[]),
+('major', '082502', '环境工程', 'major', ARRAY[]::TEXT[]),
+('major', '082503', '环境科学', 'major', ARRAY[]::TEXT[]),
+('major', '082601', '生物医学工程', 'major', ARRAY[]::TEXT[]),
+('major', '082701', '食品科学与工程', 'major', ARRAY[]::TEXT[]),
+('major', '082702', '食品质量与安全', 'major', ARRAY[]::TEXT[]),
+('major', '082801', '建筑学', 'major', ARRAY[]::TEXT[]),
+('major', '082802', '城乡规划', 'major', ARRAY[]::TEXT[]),
+('major', '082803', '风景园林', 'major', ARRAY[]::TEXT[]),
+('major', '082901', '安全工程', 'major', ARRAY[]::TEXT[]),
+('major', '083001', '生物工程', 'major', ARRAY[]::TEXT[]),
+('major', '090101', '农学', 'major', ARRAY[]::TEXT[]),
+('major', '090102', '园艺', 'major', ARRAY[]::TEXT[]),
+('major', '090301', '动物科学', 'major', ARRAY[]::TEXT[]),
+('major', '090401', '动物医学', 'major', ARRAY[]::TEXT[]),
+('major', '090501', '林学', 'major', ARRAY[]::TEXT[]),
+('major', '100101K', '基础医学', 'major', ARRAY[]::TEXT[]),
+('major', '100201K', '临床医学', 'major', ARRAY['临床']),
+('major', '100301K', '口腔医学', 'major', ARRAY[]::TEXT[]),
+('major', '100401K', '预防医学', 'major', ARRAY[]::TEXT[]),
+('major', '100501K', '中医学', 'major', ARRAY[]::TEXT[]),
+('major', '100701', '药学', 'major', ARRAY[]::TEXT[]),
+('major', '100801', '中药学', 'major', ARRAY[]::TEXT[]),
+('major', '101101', '护理学', 'major', ARRAY[]::TEXT[]),
+('major', '120101', '管理科学', 'major', ARRAY[]::TEXT[]),
+('major', '120102', '信息管理与信息系统', 'major', ARRAY['信管']),
+('major', '120103', '工程管理', 'major', ARRAY[]::TEXT[]),
+('major', '120201K', '工商管理', 'major', ARRAY[]::TEXT[]),
+('major', '120202', '市场营销', 'major', ARRAY[]::TEXT[]),
+('major', '120203K', '会计学', 'major', ARRAY['会计']),
+('major', '120204', '财务管理', 'major', ARRAY[]::TEXT[]),
+('major', '120206', '人力资源管理', 'major', ARRAY[]::TEXT[]),
+('major', '120401', '公共事业管理', 'major', ARRAY[]::TEXT[]),
+('major', '120402', '行政管理', 'major', ARRAY[]::TEXT[]),
+('major', '120601', '物流管理', 'major', ARRAY[]::TEXT[]),
+('major', '120701', '工业工程', 'major', ARRAY[]::TEXT[]),
+('major', '120801', '电子商务', 'major', ARRAY[]::TEXT[]),
+('major', '120901K', '旅游管理', 'major', ARRAY[]::TEXT[]),
+('major', '130101', '艺术史论', 'major', ARRAY[]::TEXT[]),
+('major', '130201', '音乐表演', 'major', ARRAY[]::TEXT[]),
+('major', '130202', '音乐学', 'major', ARRAY[]::TEXT[]),
+('major', '130301', '表演', 'major', ARRAY[]::TEXT[]),
+('major', '130305', '广播电视编导', 'major', ARRAY[]::TEXT[]),
+('major', '130310', '动画', 'major', ARRAY[]::TEXT[]),
+('major', '130401', '美术学', 'major', ARRAY[]::TEXT[]),
+('major', '130402', '绘画', 'major', ARRAY[]::TEXT[]),
+('major', '130502', '视觉传达设计', 'major', ARRAY['视传']),
+('major', '130503', '环境设计', 'major', ARRAY[]::TEXT[]),
+('major', '130504', '产品设计', 'major', ARRAY[]::TEXT[]),
+('major', '130508', '数字媒体艺术', 'major', ARRAY[]::TEXT[]);
+
+-- 按专业代码建立层级：专业类取前4位，专业取前4位对应的专业类，专业类取前2位对应的学科门类
+UPDATE taxonomy_terms child
+SET parent_id = parent.id
+FROM taxonomy_terms parent
+WHERE child.kind = 'major' AND parent.kind = 'major'
+    AND child.level IN ('category', 'major')
+    AND parent.code = CASE child.level
+        WHEN 'category' THEN LEFT(child.code, 2)
+        ELSE LEFT(child.code, 4)
+    END;
+
+-- ============================================
+-- 3. 种子数据：常见技能
+-- ============================================
+INSERT INTO taxonomy_terms (kind, code, name, synonyms) VALUES
+('skill', 'python', 'Python', ARRAY['python3', 'python编程', 'py']),
+('skill', 'java', 'Java', ARRAY['java编程', 'javase']),
+('skill', 'cpp', 'C++', ARRAY['cpp', 'c/c++']),
+('skill', 'c', 'C语言', ARRAY['c', 'c语言编程']),
+('skill', 'go', 'Go', ARRAY['golang', 'go语言']),
+('skill', 'javascript', 'JavaScript', ARRAY['js', 'es6']),
+('skill', 'typescript', 'TypeScript', ARRAY['ts']),
+('skill', 'sql', 'SQL', ARRAY['sql语言', '数据库']),
+('skill', 'mysql', 'MySQL', ARRAY[]::TEXT[]),
+('skill', 'matlab', 'MATLAB', ARRAY['matlab编程']),
+('skill', 'r', 'R语言', ARRAY['r']),
+('skill', 'linux', 'Linux', ARRAY['linux系统']),
+('skill', 'git', 'Git', ARRAY['github']),
+('skill', 'machine_learning', '机器学习', ARRAY['ml', 'machine learning']),
+('skill', 'deep_learning', '深度学习', ARRAY['dl', 'deep learning']),
+('skill', 'data_analysis', '数据分析', ARRAY['data analysis']),
+('skill', 'react', 'React', ARRAY['reactjs', 'react.js']),
+('skill', 'vue', 'Vue', ARRAY['vue.js', 'vuejs']),
+('skill', 'docker', 'Docker', ARRAY[]::TEXT[]),
+('skill', 'embedded', '嵌入式开发', ARRAY['嵌入式']),
+('skill', 'mcu', '单片机', ARRAY['stm32', '51单片机']),
+('skill', 'pcb', 'PCB设计', ARRAY['altium designer']),
+('skill', 'autocad', 'AutoCAD', ARRAY['cad']),
+('skill', 'solidworks', 'SolidWorks', ARRAY[]::TEXT[]),
+('skill', 'photoshop', 'Photoshop', ARRAY['ps']),
+('skill', 'english', '英语', ARRAY['english']),
+('skill', 'latex', 'LaTeX', ARRAY[]::TEXT[]),
+('skill', 'spss', 'SPSS', ARRAY[]::TEXT[]),
+('skill', 'excel', 'Excel', ARRAY['office']);
@@ -0,0 +1,12 @@
+-- 004_schedules.down.sql
+-- 回滚日程管理扩展
+
+DROP TRIGGER IF EXISTS update_schedules_updated_at ON schedules;
+
+ALTER TABLE IF EXISTS schedules
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS exception_dates,
+    DROP COLUMN IF EXISTS semester_id;
+
+DROP TRIGGER IF EXISTS update_semesters_updated_at ON semesters;
+DROP TABLE IF EXISTS semesters;
@@ -0,0 +1,38 @@
+-- 004_schedules.up.sql
+-- 日程管理：学期边界、节假日例外与RFC 5545重复规则
+
+-- ============================================
+-- 1. 学期表
+-- ============================================
+-- holidays: [{"name": "国庆节", "start_date": "...", "end_date": "..."}]
+CREATE TABLE semesters (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    holidays JSONB DEFAULT '[]'::jsonb,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_semesters_user ON semesters(user_id, start_date);
+
+CREATE TRIGGER update_semesters_updated_at BEFORE UPDATE ON semesters
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- 2. 日程表扩展
+-- ============================================
+-- recurrence_rule 兼容紧凑格式（WEEKLY_MON_WED）与RRULE，后者可能超过100字符
+ALTER TABLE schedules
+    ALTER COLUMN recurrence_rule TYPE TEXT,
+    ADD COLUMN semester_id BIGINT REFERENCES semesters(id) ON DELETE SET NULL,
+    ADD COLUMN exception_dates DATE[] DEFAULT ARRAY[]::DATE[],
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+CREATE TRIGGER update_schedules_updated_at BEFORE UPDATE ON schedules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
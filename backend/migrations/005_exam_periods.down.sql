@@ -0,0 +1,5 @@
+-- 005_exam_periods.down.sql
+-- 回滚学期考试周
+
+ALTER TABLE IF EXISTS semesters
+    DROP COLUMN IF EXISTS exam_periods;
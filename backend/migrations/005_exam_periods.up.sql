@@ -0,0 +1,6 @@
+-- 005_exam_periods.up.sql
+-- 学期考试周，用于时间冲突检测
+-- exam_periods: [{"name": "期末考试周", "start_date": "...", "end_date": "..."}]
+
+ALTER TABLE semesters
+    ADD COLUMN exam_periods JSONB DEFAULT '[]'::jsonb;
@@ -0,0 +1,4 @@
+-- 006_calendar_feeds.down.sql
+-- 回滚日历订阅令牌
+
+DROP TABLE IF EXISTS calendar_feeds;
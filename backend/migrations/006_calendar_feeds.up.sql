@@ -0,0 +1,11 @@
+-- 006_calendar_feeds.up.sql
+-- 日历订阅令牌：每个用户一个可撤销的令牌，用于免JWT订阅 calendar.ics
+-- 仅保存令牌的SHA-256摘要，重新生成即撤销旧令牌
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    last_accessed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
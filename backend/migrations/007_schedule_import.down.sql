@@ -0,0 +1,7 @@
+-- 007_schedule_import.down.sql
+-- 回滚课表导入
+
+DROP INDEX IF EXISTS idx_schedules_user_import_key;
+
+ALTER TABLE IF EXISTS schedules
+    DROP COLUMN IF EXISTS import_key;
@@ -0,0 +1,9 @@
+-- 007_schedule_import.up.sql
+-- 课表导入：记录导入来源键，重复导入同一文件时更新而非新增
+-- import_key: ics:<UID>[#<RECURRENCE-ID>] 或 csv:<课程名>|<星期>|<起始节次>
+
+ALTER TABLE schedules
+    ADD COLUMN import_key VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_schedules_user_import_key ON schedules(user_id, import_key)
+    WHERE import_key IS NOT NULL;
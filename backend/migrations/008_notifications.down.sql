@@ -0,0 +1,5 @@
+-- 008_notifications.down.sql
+-- 回滚站内通知与提醒投递记录
+
+DROP TABLE IF EXISTS reminder_deliveries;
+DROP TABLE IF EXISTS notifications;
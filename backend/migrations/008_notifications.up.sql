@@ -0,0 +1,29 @@
+-- 008_notifications.up.sql
+-- 站内通知与截止提醒投递记录
+-- reminder_deliveries 按（关联、截止日期、提前天数、渠道）去重，保证重启或多实例扫描不会重复推送
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT,
+    url TEXT,
+    opportunity_id BIGINT REFERENCES opportunities(id) ON DELETE CASCADE,
+    data JSONB,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS reminder_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    user_opportunity_id BIGINT NOT NULL REFERENCES user_opportunities(id) ON DELETE CASCADE,
+    deadline DATE NOT NULL,
+    offset_days INT NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_opportunity_id, deadline, offset_days, channel)
+);
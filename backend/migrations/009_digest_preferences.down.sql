@@ -0,0 +1,4 @@
+-- 009_digest_preferences.down.sql
+-- 回滚摘要邮件偏好
+
+DROP TABLE IF EXISTS digest_preferences;
@@ -0,0 +1,13 @@
+-- 009_digest_preferences.up.sql
+-- 摘要邮件偏好：发送频率与上次发送时间
+-- frequency 为 NULL 表示使用配置中的默认频率；last_sent_at 同时用于计算"自上次摘要以来"的内容
+
+CREATE TABLE IF NOT EXISTS digest_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(10) CHECK (frequency IN ('daily', 'weekly', 'off')),
+    last_sent_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_digest_preferences_updated_at BEFORE UPDATE ON digest_preferences
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- 010_notification_preferences.down.sql
+-- 回滚通知偏好与暂存推送
+
+DROP TABLE IF EXISTS held_notifications;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS notification_preferences;
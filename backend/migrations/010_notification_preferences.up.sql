@@ -0,0 +1,14 @@
+-- 010_notification_preferences.up.sql
+-- 通知偏好（随用户画像存储）与免打扰期间暂存的推送
+
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS notification_preferences JSONB;
+
+CREATE TABLE IF NOT EXISTS held_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    notification JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_held_notifications_user ON held_notifications(user_id);
@@ -0,0 +1,5 @@
+-- 011_sessions.down.sql
+-- 回滚登录会话与刷新令牌
+
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,6 @@
+-- 012_roles.down.sql
+-- 回滚用户角色与机会发布者
+
+DROP INDEX IF EXISTS idx_opportunities_created_by;
+ALTER TABLE opportunities DROP COLUMN IF EXISTS created_by;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,10 @@
+-- 012_roles.up.sql
+-- 用户角色与机会发布者
+-- 首个管理员需手动授予：UPDATE users SET role = 'admin' WHERE email = '...';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'student'
+    CHECK (role IN ('student', 'publisher', 'school_admin', 'admin'));
+
+ALTER TABLE opportunities ADD COLUMN IF NOT EXISTS created_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_opportunities_created_by ON opportunities(created_by);
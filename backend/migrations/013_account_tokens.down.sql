@@ -0,0 +1,5 @@
+-- 013_account_tokens.down.sql
+-- 回滚邮箱验证与重置密码令牌
+
+DROP TABLE IF EXISTS account_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
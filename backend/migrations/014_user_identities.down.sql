@@ -0,0 +1,4 @@
+-- 014_user_identities.down.sql
+-- 回滚校园统一身份认证账号绑定
+
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,4 @@
+-- 015_api_keys.down.sql
+-- 回滚个人访问令牌
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,5 @@
+-- 016_account_privacy.down.sql
+-- 回滚账号注销申请与审计日志
+
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS account_deletions;
@@ -0,0 +1,25 @@
+-- 016_account_privacy.up.sql
+-- 账号注销申请（宽限期内可撤销，到期后删除用户及其全部数据）与审计日志
+-- audit_logs 不引用 users：账号删除后记录仍需保留
+
+CREATE TABLE IF NOT EXISTS account_deletions (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    requested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    scheduled_for TIMESTAMP NOT NULL,
+    request_ip VARCHAR(64) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletions_scheduled ON account_deletions(scheduled_for);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,  -- 被操作的账号
+    actor_id BIGINT,          -- 操作者，系统任务为空
+    action VARCHAR(50) NOT NULL,
+    detail JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user ON audit_logs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs(actor_id, created_at DESC);
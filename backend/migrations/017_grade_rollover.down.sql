@@ -0,0 +1,5 @@
+-- 017_grade_rollover.down.sql
+-- 回滚年级设置时间
+
+DROP INDEX IF EXISTS idx_users_grade_updated;
+ALTER TABLE users DROP COLUMN IF EXISTS grade_updated_at;
@@ -0,0 +1,9 @@
+-- 017_grade_rollover.up.sql
+-- 年级设置时间：每学年开始时，早于升级日设置的年级自动+1；已有用户按注册时间计
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS grade_updated_at TIMESTAMP;
+UPDATE users SET grade_updated_at = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE grade_updated_at IS NULL;
+ALTER TABLE users ALTER COLUMN grade_updated_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE users ALTER COLUMN grade_updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_grade_updated ON users(grade_updated_at);
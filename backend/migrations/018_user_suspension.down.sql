@@ -0,0 +1,5 @@
+-- 018_user_suspension.down.sql
+-- 回滚账号停用
+
+DROP INDEX IF EXISTS idx_users_school_major;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,6 @@
+-- 018_user_suspension.up.sql
+-- 管理员停用账号：停用期间无法登录，已签发的令牌与个人访问令牌均被拒绝；原因记录在审计日志中
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_school_major ON users(school, major);
@@ -0,0 +1,6 @@
+-- 019_vector_index.down.sql
+
+DROP INDEX IF EXISTS idx_opportunities_description_vector_hnsw;
+
+CREATE INDEX IF NOT EXISTS idx_opportunities_has_vector
+    ON opportunities(id) WHERE description_vector IS NOT NULL;
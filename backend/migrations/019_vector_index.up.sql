@@ -0,0 +1,19 @@
+-- 019_vector_index.up.sql
+-- 为余弦相似度检索建立HNSW索引（需要pgvector 0.5+）
+-- 002中的部分索引只覆盖id列，无法服务 ORDER BY 距离 的检索，在此替换
+
+DROP INDEX IF EXISTS idx_opportunities_has_vector;
+
+-- 索引表达式与查询中的 description_vector::vector(384) 保持一致，
+-- 384 为NLP服务默认模型（paraphrase-multilingual-MiniLM-L12-v2）的向量维度
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'vector') THEN
+        EXECUTE 'CREATE INDEX IF NOT EXISTS idx_opportunities_description_vector_hnsw
+            ON opportunities USING hnsw ((description_vector::vector(384)) vector_cosine_ops)
+            WHERE array_length(description_vector, 1) = 384';
+    END IF;
+EXCEPTION WHEN OTHERS THEN
+    RAISE NOTICE 'failed to create vector index: %, similarity search will scan candidates', SQLERRM;
+END
+$$;
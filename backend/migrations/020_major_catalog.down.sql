@@ -0,0 +1,79 @@
+-- 020_major_catalog.down.sql
+-- 删除本迁移补充的专业（003中的种子专业保留）
+
+DELETE FROM taxonomy_terms
+WHERE kind = 'major' AND level = 'major' AND code IN (
+    '010103K', '010104T', '020103T', '020104T', '020105T', '020106T', '020107T', '020108T',
+    '020109T', '020305T', '020306T', '020307T', '020308T', '020309T', '020310T', '030102T',
+    '030103T', '030104T', '030105T', '030106TK', '030107TK', '030204T', '030205T', '030206TK',
+    '030303T', '030304T', '030305T', '030306T', '030401', '030501', '030502', '030504T',
+    '030601K', '030602K', '030603K', '030604TK', '030605TK', '030606TK', '030607TK', '030608TK',
+    '030609TK', '030610TK', '030611TK', '030612TK', '030613TK', '030614TK', '030615TK', '030616TK',
+    '030617TK', '030618TK', '030619TK', '030620TK', '040102', '040103', '040105', '040108',
+    '040109T', '040110TK', '040111T', '040112T', '040202K', '040203', '040204K', '040205',
+    '040206T', '040207T', '040208T', '040209T', '040210TK', '040211TK', '040212TK', '040213T',
+    '050104', '050105', '050106T', '050107T', '050108T', '050109T', '050205', '050206',
+    '050208', '050209', '050210', '050211', '050212', '050213', '050214', '050215',
+    '050216', '050217', '050218', '050219', '050220', '050221', '050222', '050223',
+    '050224', '050225', '050226', '050227', '050228', '050229', '050230', '050231',
+    '050232', '050233', '050234', '050235', '050236', '050237', '050238', '050239',
+    '050240', '050241', '050242', '050243', '050244', '050245', '050246', '050247',
+    '050248', '050249', '050250', '050251', '050252', '050253', '050254', '050255',
+    '050256', '050257', '050258', '050259', '050260', '050263T', '050264T', '050265T',
+    '050266T', '050267T', '050268T', '050269T', '050270T', '050271T', '050272T', '050273T',
+    '050274T', '050275T', '050276T', '050277T', '050278T', '050279T', '050280T', '050281T',
+    '050282T', '050283T', '050284T', '050285T', '050286T', '050287T', '050288T', '050289T',
+    '050290T', '050291T', '050292T', '050293T', '050294T', '050295T', '050296T', '050297T',
+    '050298T', '050299T', '0502100T', '050305', '050307T', '050308T', '050309T', '050310T',
+    '060103', '060104', '060105T', '060106T', '060107T', '070103T', '070104T', '070203',
+    '070204T', '070205T', '070206T', '070303T', '070304T', '070305T', '070306T', '070401',
+    '070502', '070503', '070601', '070602', '070603T', '070701', '070702', '070703T',
+    '070704T', '070801', '070802', '070803T', '070901', '070902', '070903T', '070904T',
+    '071003', '071004', '071005T', '071006T', '080205', '080206', '080208', '080209T',
+    '080210T', '080211T', '080212T', '080213T', '080214T', '080215T', '080216T', '080217T',
+    '080218T', '080219T', '080302T', '080303T', '080402', '080404', '080405', '080406',
+    '080407', '080408', '080409T', '080410T', '080411T', '080412T', '080413T', '080414T',
+    '080415T', '080416T', '080417T', '080502T', '080503T', '080504T', '080602T', '080603T',
+    '080604T', '080605T', '080606T', '080707T', '080708T', '080709T', '080711T', '080712T',
+    '080713T', '080715T', '080716T', '080718T', '080719T', '080720T', '080802T', '080804T',
+    '080805T', '080806T', '080807T', '080908T', '080909T', '080912T', '080913T', '080914TK',
+    '080915T', '080916T', '080917T', '081002', '081003', '081004', '081005T', '081006T',
+    '081007T', '081008T', '081009T', '081010T', '081011T', '081102', '081103', '081104T',
+    '081105T', '081202', '081203T', '081204T', '081205T', '081303T', '081304T', '081305T',
+    '081306T', '081307T', '081308T', '081401', '081402', '081403', '081404T', '081405T',
+    '081501', '081502', '081503', '081504', '081505T', '081506T', '081507T', '081601',
+    '081602', '081603T', '081604T', '081605T', '081701', '081702', '081703', '081704T',
+    '081705T', '081803K', '081804K', '081805K', '081806T', '081807T', '081808TK', '081809T',
+    '081810T', '081811T', '081901', '081902T', '081903T', '081904T', '082003', '082004',
+    '082005', '082006T', '082007T', '082008T', '082009T', '082010T', '082011T', '082101',
+    '082102', '082103', '082104', '082105', '082106', '082107', '082108T', '082201',
+    '082202', '082203', '082204', '082301', '082302', '082303', '082304', '082305',
+    '082306T', '082307T', '082401', '082402', '082403', '082404T', '082504', '082505T',
+    '082506T', '082507T', '082602T', '082603T', '082604T', '082703', '082704', '082705',
+    '082706T', '082707T', '082708T', '082709T', '082710T', '082711T', '082712T', '082804T',
+    '082805T', '082806T', '082807T', '082902T', '082903T', '083002T', '083003T', '083101K',
+    '083102K', '083103TK', '083104TK', '083105TK', '083106TK', '083107TK', '083108TK', '083109TK',
+    '083110TK', '083111TK', '090103', '090104', '090105', '090106', '090107T', '090108T',
+    '090109T', '090110T', '090111T', '090112T', '090113T', '090114T', '090115T', '090201',
+    '090202', '090203', '090204T', '090302T', '090303T', '090304T', '090305T', '090306T',
+    '090307T', '090402', '090403T', '090404T', '090405T', '090406TK', '090502', '090503',
+    '090504T', '090505T', '090601', '090602', '090603T', '090604TK', '090701', '090702T',
+    '100102TK', '100103T', '100202TK', '100203TK', '100204TK', '100205TK', '100206TK', '100207TK',
+    '100402', '100403TK', '100404TK', '100405TK', '100406T', '100502K', '100503K', '100504K',
+    '100505K', '100506K', '100507K', '100508TK', '100509TK', '100510TK', '100511TK', '100512TK',
+    '100513TK', '100601K', '100702', '100703TK', '100704T', '100705T', '100706T', '100707T',
+    '100708T', '100802', '100803T', '100804T', '100805T', '100806T', '100901K', '101001',
+    '101002', '101003', '101004', '101005', '101006', '101007', '101008T', '101009T',
+    '101010T', '101011T', '101012T', '101013T', '101102T', '120104', '120105', '120106TK',
+    '120107T', '120108T', '120109T', '120110T', '120111T', '120205', '120207', '120208',
+    '120209', '120210', '120211T', '120212T', '120213T', '120214T', '120215T', '120216T',
+    '120301', '120302', '120403', '120404', '120405', '120406TK', '120407T', '120408T',
+    '120409T', '120410T', '120411TK', '120412T', '120413T', '120414T', '120415TK', '120416TK',
+    '120417T', '120418T', '120501', '120502', '120503', '120602', '120603T', '120604T',
+    '120702T', '120703T', '120802T', '120803T', '120902', '120903', '120904T', '130102T',
+    '130203', '130204', '130205', '130206', '130207T', '130208TK', '130209T', '130210T',
+    '130211T', '130302', '130303', '130304', '130306', '130307', '130308', '130309',
+    '130311T', '130312T', '130313T', '130403', '130404', '130405T', '130406T', '130407TK',
+    '130408TK', '130409T', '130410T', '130501', '130505', '130506', '130507', '130509T',
+    '130510TK', '130511T', '130512T'
+);
@@ -0,0 +1,597 @@
+-- 020_major_catalog.up.sql
+-- 补全《普通高等学校本科专业目录》全部专业（003只收录了各类常见基本专业）
+-- 已存在的专业代码保持不变（保留管理员维护的同义词）
+
+INSERT INTO taxonomy_terms (kind, code, name, level)
+SELECT 'major', m.code, m.name, 'major'
+FROM (VALUES
+    ('010103K', '宗教学'),
+    ('010104T', '伦理学'),
+    ('020103T', '国民经济管理'),
+    ('020104T', '资源与环境经济学'),
+    ('020105T', '商务经济学'),
+    ('020106T', '能源经济'),
+    ('020107T', '劳动经济学'),
+    ('020108T', '经济工程'),
+    ('020109T', '数字经济'),
+    ('020305T', '金融数学'),
+    ('020306T', '信用管理'),
+    ('020307T', '经济与金融'),
+    ('020308T', '精算学'),
+    ('020309T', '互联网金融'),
+    ('020310T', '金融科技'),
+    ('030102T', '知识产权'),
+    ('030103T', '监狱学'),
+    ('030104T', '信用风险管理与法律防控'),
+    ('030105T', '国际经贸规则'),
+    ('030106TK', '司法警察学'),
+    ('030107TK', '社区矫正'),
+    ('030204T', '国际事务与国际关系'),
+    ('030205T', '政治学、经济学与哲学'),
+    ('030206TK', '国际组织与全球治理'),
+    ('030303T', '人类学'),
+    ('030304T', '女性学'),
+    ('030305T', '家政学'),
+    ('030306T', '老年学'),
+    ('030401', '民族学'),
+    ('030501', '科学社会主义'),
+    ('030502', '中国共产党历史'),
+    ('030504T', '马克思主义理论'),
+    ('030601K', '治安学'),
+    ('030602K', '侦查学'),
+    ('030603K', '边防管理'),
+    ('030604TK', '禁毒学'),
+    ('030605TK', '警犬技术'),
+    ('030606TK', '经济犯罪侦查'),
+    ('030607TK', '边防指挥'),
+    ('030608TK', '消防指挥'),
+    ('030609TK', '警卫学'),
+    ('030610TK', '公安情报学'),
+    ('030611TK', '犯罪学'),
+    ('030612TK', '公安管理学'),
+    ('030613TK', '涉外警务'),
+    ('030614TK', '国内安全保卫'),
+    ('030615TK', '警务指挥与战术'),
+    ('030616TK', '技术侦查学'),
+    ('030617TK', '海警执法'),
+    ('030618TK', '公安政治工作'),
+    ('030619TK', '移民管理'),
+    ('030620TK', '出入境管理'),
+    ('040102', '科学教育'),
+    ('040103', '人文教育'),
+    ('040105', '艺术教育'),
+    ('040108', '特殊教育'),
+    ('040109T', '华文教育'),
+    ('040110TK', '教育康复学'),
+    ('040111T', '卫生教育'),
+    ('040112T', '认知科学与技术'),
+    ('040202K', '运动训练'),
+    ('040203', '社会体育指导与管理'),
+    ('040204K', '武术与民族传统体育'),
+    ('040205', '运动人体科学'),
+    ('040206T', '运动康复'),
+    ('040207T', '休闲体育'),
+    ('040208T', '体能训练'),
+    ('040209T', '冰雪运动'),
+    ('040210TK', '电子竞技运动与管理'),
+    ('040211TK', '智能体育工程'),
+    ('040212TK', '体育旅游'),
+    ('040213T', '运动能力开发'),
+    ('050104', '中国少数民族语言文学'),
+    ('050105', '古典文献学'),
+    ('050106T', '应用语言学'),
+    ('050107T', '秘书学'),
+    ('050108T', '中国语言与文化'),
+    ('050109T', '手语翻译'),
+    ('050205', '西班牙语'),
+    ('050206', '阿拉伯语'),
+    ('050208', '波斯语'),
+    ('050209', '朝鲜语'),
+    ('050210', '菲律宾语'),
+    ('050211', '梵语巴利语'),
+    ('050212', '印度尼西亚语'),
+    ('050213', '印地语'),
+    ('050214', '柬埔寨语'),
+    ('050215', '老挝语'),
+    ('050216', '缅甸语'),
+    ('050217', '马来语'),
+    ('050218', '蒙古语'),
+    ('050219', '僧伽罗语'),
+    ('050220', '泰语'),
+    ('050221', '乌尔都语'),
+    ('050222', '希伯来语'),
+    ('050223', '越南语'),
+    ('050224', '豪萨语'),
+    ('050225', '斯瓦希里语'),
+    ('050226', '阿尔巴尼亚语'),
+    ('050227', '保加利亚语'),
+    ('050228', '波兰语'),
+    ('050229', '捷克语'),
+    ('050230', '斯洛伐克语'),
+    ('050231', '罗马尼亚语'),
+    ('050232', '葡萄牙语'),
+    ('050233', '瑞典语'),
+    ('050234', '塞尔维亚语'),
+    ('050235', '土耳其语'),
+    ('050236', '希腊语'),
+    ('050237', '匈牙利语'),
+    ('050238', '意大利语'),
+    ('050239', '泰米尔语'),
+    ('050240', '普什图语'),
+    ('050241', '世界语'),
+    ('050242', '孟加拉语'),
+    ('050243', '尼泊尔语'),
+    ('050244', '克罗地亚语'),
+    ('050245', '荷兰语'),
+    ('050246', '芬兰语'),
+    ('050247', '乌克兰语'),
+    ('050248', '挪威语'),
+    ('050249', '丹麦语'),
+    ('050250', '冰岛语'),
+    ('050251', '爱尔兰语'),
+    ('050252', '拉脱维亚语'),
+    ('050253', '立陶宛语'),
+    ('050254', '斯洛文尼亚语'),
+    ('050255', '爱沙尼亚语'),
+    ('050256', '马耳他语'),
+    ('050257', '哈萨克语'),
+    ('050258', '乌兹别克语'),
+    ('050259', '祖鲁语'),
+    ('050260', '拉丁语'),
+    ('050263T', '阿姆哈拉语'),
+    ('050264T', '吉尔吉斯语'),
+    ('050265T', '索马里语'),
+    ('050266T', '土库曼语'),
+    ('050267T', '加泰罗尼亚语'),
+    ('050268T', '约鲁巴语'),
+    ('050269T', '亚美尼亚语'),
+    ('050270T', '马达加斯加语'),
+    ('050271T', '格鲁吉亚语'),
+    ('050272T', '阿塞拜疆语'),
+    ('050273T', '阿非利卡语'),
+    ('050274T', '马其顿语'),
+    ('050275T', '塔吉克语'),
+    ('050276T', '茨瓦纳语'),
+    ('050277T', '恩德贝莱语'),
+    ('050278T', '科摩罗语'),
+    ('050279T', '克里奥尔语'),
+    ('050280T', '绍纳语'),
+    ('050281T', '提格雷尼亚语'),
+    ('050282T', '白俄罗斯语'),
+    ('050283T', '毛利语'),
+    ('050284T', '汤加语'),
+    ('050285T', '萨摩亚语'),
+    ('050286T', '库尔德语'),
+    ('050287T', '比斯拉马语'),
+    ('050288T', '达里语'),
+    ('050289T', '德顿语'),
+    ('050290T', '迪维希语'),
+    ('050291T', '斐济语'),
+    ('050292T', '库克群岛毛利语'),
+    ('050293T', '隆迪语'),
+    ('050294T', '卢森堡语'),
+    ('050295T', '卢旺达语'),
+    ('050296T', '纽埃语'),
+    ('050297T', '皮金语'),
+    ('050298T', '切瓦语'),
+    ('050299T', '塞苏陀语'),
+    ('0502100T', '桑戈语'),
+    ('050305', '编辑出版学'),
+    ('050307T', '数字出版'),
+    ('050308T', '时尚传播'),
+    ('050309T', '国际新闻与传播'),
+    ('050310T', '会展'),
+    ('060103', '考古学'),
+    ('060104', '文物与博物馆学'),
+    ('060105T', '文物保护技术'),
+    ('060106T', '外国语言与外国历史'),
+    ('060107T', '文化遗产'),
+    ('070103T', '数理基础科学'),
+    ('070104T', '数据计算及应用'),
+    ('070203', '核物理'),
+    ('070204T', '声学'),
+    ('070205T', '系统科学与工程'),
+    ('070206T', '量子信息科学'),
+    ('070303T', '化学生物学'),
+    ('070304T', '分子科学与工程'),
+    ('070305T', '能源化学'),
+    ('070306T', '化学测量学与技术'),
+    ('070401', '天文学'),
+    ('070502', '自然地理与资源环境'),
+    ('070503', '人文地理与城乡规划'),
+    ('070601', '大气科学'),
+    ('070602', '应用气象学'),
+    ('070603T', '气象技术与工程'),
+    ('070701', '海洋科学'),
+    ('070702', '海洋技术'),
+    ('070703T', '海洋资源与环境'),
+    ('070704T', '军事海洋学'),
+    ('070801', '地球物理学'),
+    ('070802', '空间科学与技术'),
+    ('070803T', '防灾减灾科学与工程'),
+    ('070901', '地质学'),
+    ('070902', '地球化学'),
+    ('070903T', '地球信息科学与技术'),
+    ('070904T', '古生物学'),
+    ('071003', '生物信息学'),
+    ('071004', '生态学'),
+    ('071005T', '整合科学'),
+    ('071006T', '神经科学'),
+    ('080205', '工业设计'),
+    ('080206', '过程装备与控制工程'),
+    ('080208', '汽车服务工程'),
+    ('080209T', '机械工艺技术'),
+    ('080210T', '微机电系统工程'),
+    ('080211T', '机电技术教育'),
+    ('080212T', '汽车维修工程教育'),
+    ('080213T', '智能制造工程'),
+    ('080214T', '智能车辆工程'),
+    ('080215T', '仿生科学与工程'),
+    ('080216T', '新能源汽车工程'),
+    ('080217T', '增材制造工程'),
+    ('080218T', '智能交互设计'),
+    ('080219T', '应急装备技术与工程'),
+    ('080302T', '精密仪器'),
+    ('080303T', '智能感知工程'),
+    ('080402', '材料物理'),
+    ('080404', '冶金工程'),
+    ('080405', '金属材料工程'),
+    ('080406', '无机非金属材料工程'),
+    ('080407', '高分子材料与工程'),
+    ('080408', '复合材料与工程'),
+    ('080409T', '粉体材料科学与工程'),
+    ('080410T', '宝石及材料工艺学'),
+    ('080411T', '焊接技术与工程'),
+    ('080412T', '功能材料'),
+    ('080413T', '纳米材料与技术'),
+    ('080414T', '新能源材料与器件'),
+    ('080415T', '材料设计科学与工程'),
+    ('080416T', '复合材料成型工程'),
+    ('080417T', '智能材料与结构'),
+    ('080502T', '能源与环境系统工程'),
+    ('080503T', '新能源科学与工程'),
+    ('080504T', '储能科学与工程'),
+    ('080602T', '智能电网信息工程'),
+    ('080603T', '光源与照明'),
+    ('080604T', '电气工程与智能控制'),
+    ('080605T', '电机电器智能化'),
+    ('080606T', '电缆工程'),
+    ('080707T', '广播电视工程'),
+    ('080708T', '水声工程'),
+    ('080709T', '电子封装技术'),
+    ('080711T', '医学信息工程'),
+    ('080712T', '电磁场与无线技术'),
+    ('080713T', '电波传播与天线'),
+    ('080715T', '电信工程及管理'),
+    ('080716T', '应用电子技术教育'),
+    ('080718T', '海洋信息工程'),
+    ('080719T', '柔性电子学'),
+    ('080720T', '智能测控工程'),
+    ('080802T', '轨道交通信号与控制'),
+    ('080804T', '邮政工程'),
+    ('080805T', '核电技术与控制工程'),
+    ('080806T', '智能装备与系统'),
+    ('080807T', '工业智能'),
+    ('080908T', '空间信息与数字技术'),
+    ('080909T', '电子与计算机工程'),
+    ('080912T', '新媒体技术'),
+    ('080913T', '电影制作'),
+    ('080914TK', '保密技术'),
+    ('080915T', '服务科学与工程'),
+    ('080916T', '虚拟现实技术'),
+    ('080917T', '区块链工程'),
+    ('081002', '建筑环境与能源应用工程'),
+    ('081003', '给排水科学与工程'),
+    ('081004', '建筑电气与智能化'),
+    ('081005T', '城市地下空间工程'),
+    ('081006T', '道路桥梁与渡河工程'),
+    ('081007T', '铁道工程'),
+    ('081008T', '智能建造'),
+    ('081009T', '土木、水利与海洋工程'),
+    ('081010T', '土木、水利与交通工程'),
+    ('081011T', '城市水系统工程'),
+    ('081102', '水文与水资源工程'),
+    ('081103', '港口航道与海岸工程'),
+    ('081104T', '水务工程'),
+    ('081105T', '水利科学与工程'),
+    ('081202', '遥感科学与技术'),
+    ('081203T', '导航工程'),
+    ('081204T', '地理国情监测'),
+    ('081205T', '地理空间信息工程'),
+    ('081303T', '资源循环科学与工程'),
+    ('081304T', '能源化学工程'),
+    ('081305T', '化学工程与工业生物工程'),
+    ('081306T', '化工安全工程'),
+    ('081307T', '涂料工程'),
+    ('081308T', '精细化工'),
+    ('081401', '地质工程'),
+    ('081402', '勘查技术与工程'),
+    ('081403', '资源勘查工程'),
+    ('081404T', '地下水科学与工程'),
+    ('081405T', '旅游地学与规划工程'),
+    ('081501', '采矿工程'),
+    ('081502', '石油工程'),
+    ('081503', '矿物加工工程'),
+    ('081504', '油气储运工程'),
+    ('081505T', '矿物资源工程'),
+    ('081506T', '海洋油气工程'),
+    ('081507T', '智能采矿工程'),
+    ('081601', '纺织工程'),
+    ('081602', '服装设计与工程'),
+    ('081603T', '非织造材料与工程'),
+    ('081604T', '服装设计与工艺教育'),
+    ('081605T', '丝绸设计与工程'),
+    ('081701', '轻化工程'),
+    ('081702', '包装工程'),
+    ('081703', '印刷工程'),
+    ('081704T', '香料香精技术与工程'),
+    ('081705T', '化妆品技术与工程'),
+    ('081803K', '航海技术'),
+    ('081804K', '轮机工程'),
+    ('081805K', '飞行技术'),
+    ('081806T', '交通设备与控制工程'),
+    ('081807T', '救助与打捞工程'),
+    ('081808TK', '船舶电子电气工程'),
+    ('081809T', '轨道交通电气与控制'),
+    ('081810T', '邮轮工程与管理'),
+    ('081811T', '智慧交通'),
+    ('081901', '船舶与海洋工程'),
+    ('081902T', '海洋工程与技术'),
+    ('081903T', '海洋资源开发技术'),
+    ('081904T', '海洋机器人'),
+    ('082003', '飞行器制造工程'),
+    ('082004', '飞行器动力工程'),
+    ('082005', '飞行器环境与生命保障工程'),
+    ('082006T', '飞行器质量与可靠性'),
+    ('082007T', '飞行器适航技术'),
+    ('082008T', '飞行器控制与信息工程'),
+    ('082009T', '无人驾驶航空器系统工程'),
+    ('082010T', '智能飞行器技术'),
+    ('082011T', '空天智能电推进技术'),
+    ('082101', '武器系统与工程'),
+    ('082102', '武器发射工程'),
+    ('082103', '探测制导与控制技术'),
+    ('082104', '弹药工程与爆炸技术'),
+    ('082105', '特种能源技术与工程'),
+    ('082106', '装甲车辆工程'),
+    ('082107', '信息对抗技术'),
+    ('082108T', '智能无人系统技术'),
+    ('082201', '核工程与核技术'),
+    ('082202', '辐射防护与核安全'),
+    ('082203', '工程物理'),
+    ('082204', '核化工与核燃料工程'),
+    ('082301', '农业工程'),
+    ('082302', '农业机械化及其自动化'),
+    ('082303', '农业电气化'),
+    ('082304', '农业建筑环境与能源工程'),
+    ('082305', '农业水利工程'),
+    ('082306T', '土地整治工程'),
+    ('082307T', '农业智能装备工程'),
+    ('082401', '森林工程'),
+    ('082402', '木材科学与工程'),
+    ('082403', '林产化工'),
+    ('082404T', '家具设计与工程'),
+    ('082504', '环境生态工程'),
+    ('082505T', '环保设备工程'),
+    ('082506T', '资源环境科学'),
+    ('082507T', '水质科学与技术'),
+    ('082602T', '假肢矫形工程'),
+    ('082603T', '临床工程技术'),
+    ('082604T', '康复工程'),
+    ('082703', '粮食工程'),
+    ('082704', '乳品工程'),
+    ('082705', '酿酒工程'),
+    ('082706T', '葡萄与葡萄酒工程'),
+    ('082707T', '食品营养与检验教育'),
+    ('082708T', '烹饪与营养教育'),
+    ('082709T', '食品安全与检测'),
+    ('082710T', '食品营养与健康'),
+    ('082711T', '食用菌科学与工程'),
+    ('082712T', '白酒酿造工程'),
+    ('082804T', '历史建筑保护工程'),
+    ('082805T', '人居环境科学与技术'),
+    ('082806T', '城市设计'),
+    ('082807T', '智慧建筑与建造'),
+    ('082902T', '应急技术与管理'),
+    ('082903T', '职业卫生工程'),
+    ('083002T', '生物制药'),
+    ('083003T', '合成生物学'),
+    ('083101K', '刑事科学技术'),
+    ('083102K', '消防工程'),
+    ('083103TK', '交通管理工程'),
+    ('083104TK', '安全防范工程'),
+    ('083105TK', '公安视听技术'),
+    ('083106TK', '抢险救援指挥与技术'),
+    ('083107TK', '火灾勘查'),
+    ('083108TK', '网络安全与执法'),
+    ('083109TK', '核生化消防'),
+    ('083110TK', '海警舰艇指挥与技术'),
+    ('083111TK', '数据警务技术'),
+    ('090103', '植物保护'),
+    ('090104', '植物科学与技术'),
+    ('090105', '种子科学与工程'),
+    ('090106', '设施农业科学与工程'),
+    ('090107T', '茶学'),
+    ('090108T', '烟草'),
+    ('090109T', '应用生物科学'),
+    ('090110T', '农艺教育'),
+    ('090111T', '园艺教育'),
+    ('090112T', '智慧农业'),
+    ('090113T', '菌物科学与工程'),
+    ('090114T', '农药化肥'),
+    ('090115T', '生物质科学与工程'),
+    ('090201', '农业资源与环境'),
+    ('090202', '野生动物与自然保护区管理'),
+    ('090203', '水土保持与荒漠化防治'),
+    ('090204T', '土地科学与技术'),
+    ('090302T', '蚕学'),
+    ('090303T', '蜂学'),
+    ('090304T', '经济动物学'),
+    ('090305T', '马业科学'),
+    ('090306T', '饲料工程'),
+    ('090307T', '智慧牧业科学与工程'),
+    ('090402', '动物药学'),
+    ('090403T', '动植物检疫'),
+    ('090404T', '实验动物学'),
+    ('090405T', '中兽医学'),
+    ('090406TK', '兽医公共卫生'),
+    ('090502', '园林'),
+    ('090503', '森林保护'),
+    ('090504T', '经济林'),
+    ('090505T', '智慧林业'),
+    ('090601', '水产养殖学'),
+    ('090602', '海洋渔业科学与技术'),
+    ('090603T', '水族科学与技术'),
+    ('090604TK', '水生动物医学'),
+    ('090701', '草业科学'),
+    ('090702T', '草坪科学与工程'),
+    ('100102TK', '生物医学'),
+    ('100103T', '生物医学科学'),
+    ('100202TK', '麻醉学'),
+    ('100203TK', '医学影像学'),
+    ('100204TK', '眼视光医学'),
+    ('100205TK', '精神医学'),
+    ('100206TK', '放射医学'),
+    ('100207TK', '儿科学'),
+    ('100402', '食品卫生与营养学'),
+    ('100403TK', '妇幼保健医学'),
+    ('100404TK', '卫生监督'),
+    ('100405TK', '全球健康学'),
+    ('100406T', '运动与公共健康'),
+    ('100502K', '针灸推拿学'),
+    ('100503K', '藏医学'),
+    ('100504K', '蒙医学'),
+    ('100505K', '维医学'),
+    ('100506K', '壮医学'),
+    ('100507K', '哈医学'),
+    ('100508TK', '傣医学'),
+    ('100509TK', '回医学'),
+    ('100510TK', '中医康复学'),
+    ('100511TK', '中医养生学'),
+    ('100512TK', '中医儿科学'),
+    ('100513TK', '中医骨伤科学'),
+    ('100601K', '中西医临床医学'),
+    ('100702', '药物制剂'),
+    ('100703TK', '临床药学'),
+    ('100704T', '药事管理'),
+    ('100705T', '药物分析'),
+    ('100706T', '药物化学'),
+    ('100707T', '海洋药学'),
+    ('100708T', '化妆品科学与技术'),
+    ('100802', '中药资源与开发'),
+    ('100803T', '藏药学'),
+    ('100804T', '蒙药学'),
+    ('100805T', '中药制药'),
+    ('100806T', '中草药栽培与鉴定'),
+    ('100901K', '法医学'),
+    ('101001', '医学检验技术'),
+    ('101002', '医学实验技术'),
+    ('101003', '医学影像技术'),
+    ('101004', '眼视光学'),
+    ('101005', '康复治疗学'),
+    ('101006', '口腔医学技术'),
+    ('101007', '卫生检验与检疫'),
+    ('101008T', '听力与言语康复学'),
+    ('101009T', '康复物理治疗'),
+    ('101010T', '康复作业治疗'),
+    ('101011T', '智能医学工程'),
+    ('101012T', '生物医药数据科学'),
+    ('101013T', '智能影像工程'),
+    ('101102T', '助产学'),
+    ('120104', '房地产开发与管理'),
+    ('120105', '工程造价'),
+    ('120106TK', '保密管理'),
+    ('120107T', '邮政管理'),
+    ('120108T', '大数据管理与应用'),
+    ('120109T', '工程审计'),
+    ('120110T', '计算金融'),
+    ('120111T', '应急管理'),
+    ('120205', '国际商务'),
+    ('120207', '审计学'),
+    ('120208', '资产评估'),
+    ('120209', '物业管理'),
+    ('120210', '文化产业管理'),
+    ('120211T', '劳动关系'),
+    ('120212T', '体育经济与管理'),
+    ('120213T', '财务会计教育'),
+    ('120214T', '市场营销教育'),
+    ('120215T', '零售业管理'),
+    ('120216T', '创业管理'),
+    ('120301', '农林经济管理'),
+    ('120302', '农村区域发展'),
+    ('120403', '劳动与社会保障'),
+    ('120404', '土地资源管理'),
+    ('120405', '城市管理'),
+    ('120406TK', '海关管理'),
+    ('120407T', '交通管理'),
+    ('120408T', '海事管理'),
+    ('120409T', '公共关系学'),
+    ('120410T', '健康服务与管理'),
+    ('120411TK', '海警后勤管理'),
+    ('120412T', '医疗产品管理'),
+    ('120413T', '医疗保险'),
+    ('120414T', '养老服务管理'),
+    ('120415TK', '海关检验检疫安全'),
+    ('120416TK', '海外安全管理'),
+    ('120417T', '自然资源登记与管理'),
+    ('120418T', '慈善管理'),
+    ('120501', '图书馆学'),
+    ('120502', '档案学'),
+    ('120503', '信息资源管理'),
+    ('120602', '物流工程'),
+    ('120603T', '采购管理'),
+    ('120604T', '供应链管理'),
+    ('120702T', '标准化工程'),
+    ('120703T', '质量管理工程'),
+    ('120802T', '电子商务及法律'),
+    ('120803T', '跨境电子商务'),
+    ('120902', '酒店管理'),
+    ('120903', '会展经济与管理'),
+    ('120904T', '旅游管理与服务教育'),
+    ('130102T', '艺术管理'),
+    ('130203', '作曲与作曲技术理论'),
+    ('130204', '舞蹈表演'),
+    ('130205', '舞蹈学'),
+    ('130206', '舞蹈编导'),
+    ('130207T', '舞蹈教育'),
+    ('130208TK', '航空服务艺术与管理'),
+    ('130209T', '流行音乐'),
+    ('130210T', '音乐治疗'),
+    ('130211T', '流行舞蹈'),
+    ('130302', '戏剧学'),
+    ('130303', '电影学'),
+    ('130304', '戏剧影视文学'),
+    ('130306', '戏剧影视导演'),
+    ('130307', '戏剧影视美术设计'),
+    ('130308', '录音艺术'),
+    ('130309', '播音与主持艺术'),
+    ('130311T', '影视摄影与制作'),
+    ('130312T', '影视技术'),
+    ('130313T', '戏剧教育'),
+    ('130403', '雕塑'),
+    ('130404', '摄影'),
+    ('130405T', '书法学'),
+    ('130406T', '中国画'),
+    ('130407TK', '实验艺术'),
+    ('130408TK', '跨媒体艺术'),
+    ('130409T', '文物保护与修复'),
+    ('130410T', '漫画'),
+    ('130501', '艺术设计学'),
+    ('130505', '服装与服饰设计'),
+    ('130506', '公共艺术'),
+    ('130507', '工艺美术'),
+    ('130509T', '艺术与科技'),
+    ('130510TK', '陶瓷艺术设计'),
+    ('130511T', '新媒体艺术'),
+    ('130512T', '包装设计')
+) AS m(code, name)
+ON CONFLICT (kind, code) DO NOTHING;
+
+-- 挂到对应的专业类下（专业代码前4位）
+UPDATE taxonomy_terms child
+SET parent_id = parent.id
+FROM taxonomy_terms parent
+WHERE child.kind = 'major' AND parent.kind = 'major'
+    AND child.level = 'major' AND parent.level = 'category'
+    AND child.parent_id IS NULL
+    AND parent.code = LEFT(child.code, 4);
@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -15,15 +17,19 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
-// Claims represents JWT claims
+// Claims represents JWT claims; RegisteredClaims.ID carries the jti used for revocation
 type Claims struct {
-	UserID   int64  `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	UserID     int64  `json:"user_id"`
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	Role       string `json:"role"`
+	SessionID  int64  `json:"sid,omitempty"`    // 签发该令牌的登录会话
+	IssuedAtMs int64  `json:"iat_ms,omitempty"` // 毫秒级签发时间，iat 只精确到秒
 	jwt.RegisteredClaims
 }
 
 // Manager handles JWT token generation and validation
+// Access tokens are short-lived; sessions are extended with refresh tokens instead
 type Manager struct {
 	secret     []byte
 	expiration time.Duration
@@ -37,14 +43,23 @@ func NewManager(cfg *config.JWTConfig) *Manager {
 	}
 }
 
-// GenerateToken generates a new JWT token for a user
-func (m *Manager) GenerateToken(userID int64, username, email string) (string, error) {
+// Expiration returns the access token lifetime
+func (m *Manager) Expiration() time.Duration {
+	return m.expiration
+}
+
+// GenerateToken generates a new short-lived access token for a user's session
+func (m *Manager) GenerateToken(userID int64, username, email, role string, sessionID int64) (string, error) {
 	now := time.Now()
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
+		UserID:     userID,
+		Username:   username,
+		Email:      email,
+		Role:       role,
+		SessionID:  sessionID,
+		IssuedAtMs: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        newTokenID(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(m.expiration)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
@@ -80,13 +95,9 @@ func (m *Manager) ValidateToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
-// RefreshToken generates a new token with extended expiration
-func (m *Manager) RefreshToken(tokenString string) (string, error) {
-	claims, err := m.ValidateToken(tokenString)
-	if err != nil {
-		return "", err
-	}
-
-	// Generate new token with same user info
-	return m.GenerateToken(claims.UserID, claims.Username, claims.Email)
+// newTokenID 生成令牌唯一标识（jti）
+func newTokenID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
 }
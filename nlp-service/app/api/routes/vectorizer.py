@@ -0,0 +1,42 @@
+"""
+文本向量化API路由
+"""
+from typing import List
+
+from fastapi import APIRouter, HTTPException
+from fastapi.concurrency import run_in_threadpool
+from pydantic import BaseModel
+from loguru import logger
+
+from app.services.vectorizer import Vectorizer
+
+router = APIRouter()
+vectorizer = Vectorizer()
+
+
+class TextRequest(BaseModel):
+    """向量化请求"""
+    text: str
+
+
+class VectorResponse(BaseModel):
+    """向量化响应"""
+    vector: List[float]
+    dimension: int
+
+
+@router.post("/text", response_model=VectorResponse, tags=["Vectorization"])
+async def vectorize_text(request: TextRequest):
+    """
+    将文本转换为归一化句向量
+
+    - **text**: 待向量化的文本
+    """
+    try:
+        vector = await run_in_threadpool(vectorizer.vectorize, request.text)
+        return VectorResponse(vector=vector, dimension=len(vector))
+    except ValueError as e:
+        raise HTTPException(status_code=400, detail=str(e))
+    except Exception as e:
+        logger.error(f"Vectorization error: {e}")
+        raise HTTPException(status_code=500, detail=f"Vectorization failed: {str(e)}")
@@ -38,7 +38,7 @@ async def startup_event():
     logger.info("Starting UniFocus NLP Service...")
     # TODO: 加载NLP模型
     # - Spacy中文模型
-    # - Sentence Transformer模型
+    # - Sentence Transformer模型（向量化路由首次调用时懒加载）
     # - PaddleOCR模型
     logger.info("NLP Service started successfully")
 
@@ -67,14 +67,14 @@ async def root():
     }
 
 # 引入路由
-from app.api.routes import text_extractor
+from app.api.routes import text_extractor, vectorizer
 app.include_router(text_extractor.router, prefix="/api/v1/extract", tags=["Text Extraction"])
+app.include_router(vectorizer.router, prefix="/api/v1/vectorize", tags=["Vectorization"])
 
 # TODO: 其他路由待实现
-# from app.api.routes import entity_recognizer, ocr_service, vectorizer
+# from app.api.routes import entity_recognizer, ocr_service
 # app.include_router(entity_recognizer.router, prefix="/api/v1/entity", tags=["Entity Recognition"])
 # app.include_router(ocr_service.router, prefix="/api/v1/ocr", tags=["OCR"])
-# app.include_router(vectorizer.router, prefix="/api/v1/vectorize", tags=["Vectorization"])
 
 if __name__ == "__main__":
     import uvicorn
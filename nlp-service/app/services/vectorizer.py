@@ -0,0 +1,53 @@
+"""
+文本向量化服务
+使用Sentence Transformer模型生成归一化的句向量，供后端做余弦相似度检索
+"""
+import os
+import threading
+from typing import List
+
+from loguru import logger
+
+# 默认模型输出384维向量，需与后端迁移 019_vector_index 中的索引维度一致
+DEFAULT_MODEL = "paraphrase-multilingual-MiniLM-L12-v2"
+
+
+class Vectorizer:
+    """文本向量化器（模型在首次使用时加载）"""
+
+    def __init__(self, model_name: str = None):
+        """
+        初始化向量化器
+
+        Args:
+            model_name: Sentence Transformer模型名称，默认读取环境变量 VECTORIZER_MODEL
+        """
+        self.model_name = model_name or os.getenv("VECTORIZER_MODEL", DEFAULT_MODEL)
+        self._model = None
+        self._lock = threading.Lock()
+
+    def _get_model(self):
+        """懒加载模型"""
+        if self._model is None:
+            with self._lock:
+                if self._model is None:
+                    from sentence_transformers import SentenceTransformer
+                    logger.info(f"Loading sentence transformer model: {self.model_name}")
+                    self._model = SentenceTransformer(self.model_name)
+        return self._model
+
+    def vectorize(self, text: str) -> List[float]:
+        """
+        将文本转换为归一化向量
+
+        Args:
+            text: 输入文本
+
+        Returns:
+            向量（float列表）
+        """
+        if not text or not text.strip():
+            raise ValueError("Text cannot be empty")
+
+        embedding = self._get_model().encode(text, normalize_embeddings=True)
+        return embedding.tolist()